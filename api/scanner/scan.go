@@ -4,19 +4,34 @@ package scanner
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/CycloneDX/cyclonedx-go"
 	"github.com/CycloneDX/license-scanner/configurer"
 	"github.com/CycloneDX/license-scanner/identifier"
 	"github.com/CycloneDX/license-scanner/licenses"
+	"github.com/CycloneDX/license-scanner/manifest"
+	"github.com/CycloneDX/license-scanner/metrics"
 	"github.com/CycloneDX/license-scanner/normalizer"
+	"github.com/CycloneDX/license-scanner/purl"
 	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"golang.org/x/exp/slices"
 )
 
 // NOASSERTION_SPDX_NAME in License SPDX Name signify that the license text passed through the scan without any errors but no match was found
 const NOASSERTION_SPDX_NAME = "NOASSERTION"
 
+var (
+	resultsCacheLookups = metrics.NewCounter("license_scanner_results_cache_total",
+		"Lookups of scanned license text in the results cache, by result (hit or miss).", "result")
+	resultsCacheHits   = resultsCacheLookups.With("hit")
+	resultsCacheMisses = resultsCacheLookups.With("miss")
+	scans              = metrics.NewCounter("license_scanner_scans_total",
+		"Scan specifications scanned, by kind (text, or directory for packages and archives).", "kind")
+)
+
 // ScanSpecs holds the package manager, the programming language, and a list of multiple packages with their specifications
 type ScanSpecs struct {
 	// package manager to search for
@@ -45,6 +60,7 @@ type ScanSpec struct {
 	Location string
 	// Package URL to search for.
 	// This is the standardized URL used to identify and locate a software package across many programming languages and package managers.
+	// ScanPackages resolves the package URL to the package directory in the local mirror (see purl.Mirror).
 	PURL string
 	// file hash or package hash to search for.
 	// This will also be matched against known file hashes.
@@ -52,6 +68,9 @@ type ScanSpec struct {
 	Hash *normalizer.Digest
 	// license input text to match and identify the license against the data set
 	LicenseText string
+	// declared license of the package (e.g. an SPDX expression or license name from the package manager).
+	// If provided, the declared license is compared with the detected licenses in the ScanResult.
+	DeclaredLicense string
 }
 
 // Licenses is a collection LicenseChoice
@@ -74,6 +93,11 @@ type ScanResult struct {
 	Error error
 	// a list of LicenseMatch i.e. a list of SPDX license IDs in sequential order, the matches of the input text across the various licenses
 	CycloneDXLicenses Licenses
+	// the declared license compared with the detected licenses
+	// set only when the specification has a declared license
+	Declared *manifest.Comparison
+	// the files of the package in which licenses were found (when scanning packages)
+	Files []string
 }
 
 // WithConfig sets the config to use for the scan
@@ -82,19 +106,26 @@ func (s *ScanSpecs) WithFlags(flags *pflag.FlagSet) *ScanSpecs {
 	return s
 }
 
-// ScanLicenseText scans the specified license file to retrieve license information
-func (s *ScanSpecs) ScanLicenseText() ([]*ScanResult, error) {
+// initLicenseLibrary initializes the config and the license data set to compare against
+func (s *ScanSpecs) initLicenseLibrary() (*viper.Viper, *licenses.LicenseLibrary, error) {
 	cfg, err := configurer.InitConfig(s.flags)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	licenseLibrary, err := licenses.NewLicenseLibrary(cfg)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-
-	// initialize the license data set to compare against
 	if err := licenseLibrary.AddAll(); err != nil {
+		return nil, nil, err
+	}
+	return cfg, licenseLibrary, nil
+}
+
+// ScanLicenseText scans the specified license file to retrieve license information
+func (s *ScanSpecs) ScanLicenseText() ([]*ScanResult, error) {
+	_, licenseLibrary, err := s.initLicenseLibrary()
+	if err != nil {
 		return nil, err
 	}
 
@@ -115,6 +146,7 @@ func (s *ScanSpecs) ScanLicenseText() ([]*ScanResult, error) {
 
 // ScanLicenseText scans the specified license file to retrieve license information
 func (s *ScanSpec) ScanLicenseText(licenseLibrary *licenses.LicenseLibrary, resultsCache map[normalizer.Digest]*ScanResult) *ScanResult {
+	scans.With("text").Inc()
 	// create a scanResult with the specifications and licenseText
 	r := &ScanResult{
 		Spec:              *s,
@@ -125,6 +157,7 @@ func (s *ScanSpec) ScanLicenseText(licenseLibrary *licenses.LicenseLibrary, resu
 	// instantiate normalizedData with the input license text
 	normalizedData := normalizer.NormalizationData{
 		OriginalText: s.LicenseText,
+		Pipeline:     licenseLibrary.Pipeline,
 	}
 
 	// normalize the input license text
@@ -139,10 +172,17 @@ func (s *ScanSpec) ScanLicenseText(licenseLibrary *licenses.LicenseLibrary, resu
 
 	// check the cache in memory if we have seen the same license before
 	// return the result if it exists in the cache to avoid running identification for it
+	// the cached result is copied with this specification (it may be for another name or package)
 	if cachedResult, ok := resultsCache[*r.Hash]; ok {
-		return cachedResult
+		resultsCacheHits.Inc()
+		c := *cachedResult
+		c.Spec = *s
+		c.OriginalText = s.LicenseText
+		return s.compareDeclared(&c, licenseLibrary)
 	}
 
+	resultsCacheMisses.Inc()
+
 	// find the licenses in the normalized text and return a list of SPDX IDs
 	// in case of an error, return as much as we have along with an error
 	results, err := identifier.Identify(identifier.Options{}, licenseLibrary, normalizedData)
@@ -151,17 +191,32 @@ func (s *ScanSpec) ScanLicenseText(licenseLibrary *licenses.LicenseLibrary, resu
 		return r
 	}
 
+	var ids []string
+	for id := range results.Matches {
+		ids = append(ids, id)
+	}
+	r.CycloneDXLicenses = cycloneDXLicenses(ids, licenseLibrary)
+
+	// populate the results cache to keep the match in memory for next license match
+	resultsCache[*r.Hash] = r
+
+	return s.compareDeclared(r, licenseLibrary)
+}
+
+// cycloneDXLicenses returns the licenses for the matched SPDX IDs, or NOASSERTION when there are no matches
+func cycloneDXLicenses(ids []string, licenseLibrary *licenses.LicenseLibrary) Licenses {
+	r := Licenses{}
 	// if the results are empty, add unknown as the SPDX ID
-	if len(results.Matches) == 0 {
+	if len(ids) == 0 {
 		// Add NOASSERTION to the LicenseChoice of the SPDX Name for this scan
-		r.CycloneDXLicenses = append(r.CycloneDXLicenses, cyclonedx.LicenseChoice{
+		r = append(r, cyclonedx.LicenseChoice{
 			License: &cyclonedx.License{
 				Name: NOASSERTION_SPDX_NAME,
 			},
 		})
 	} else {
 		// iterate over the list of matches and maintain the unique list of SPDX IDs in the result
-		for id := range results.Matches {
+		for _, id := range ids {
 			// Add an SPDX ID from the match
 			// update the LicenseChoice to include each new match
 
@@ -171,7 +226,7 @@ func (s *ScanSpec) ScanLicenseText(licenseLibrary *licenses.LicenseLibrary, resu
 			if family != "" {
 				name = fmt.Sprintf("%s (%s)", name, family)
 			}
-			r.CycloneDXLicenses = append(r.CycloneDXLicenses, cyclonedx.LicenseChoice{
+			r = append(r, cyclonedx.LicenseChoice{
 				License: &cyclonedx.License{
 					ID:   id,
 					Name: name,
@@ -187,11 +242,106 @@ func (s *ScanSpec) ScanLicenseText(licenseLibrary *licenses.LicenseLibrary, resu
 
 		}
 	}
+	return r
+}
 
-	// populate the results cache to keep the match in memory for next license match
-	resultsCache[*r.Hash] = r
+// compareDeclared returns the scan result with the declared license compared with the detected licenses.
+// The scan result is copied (it may be cached for other specifications), unless there is nothing to compare.
+func (s *ScanSpec) compareDeclared(r *ScanResult, licenseLibrary *licenses.LicenseLibrary) *ScanResult {
+	if s.DeclaredLicense == "" || r.Error != nil {
+		return r
+	}
 
-	return r
+	var detected []string
+	for _, l := range r.CycloneDXLicenses {
+		if l.License != nil && l.License.ID != "" {
+			detected = append(detected, l.License.ID)
+		}
+	}
+	sort.Strings(detected)
+
+	declared, _, _ := manifest.ResolveLicense(s.DeclaredLicense, licenseLibrary)
+	if declared == "" {
+		// Compare the license as declared, which is conflicting unless it is an unknown license ID
+		declared = s.DeclaredLicense
+	}
+	comparison := manifest.Compare(declared, detected)
+
+	c := *r
+	c.Spec = *s
+	c.Declared = &comparison
+	return &c
+}
+
+// ScanPackages resolves the package URL of each specification to a package directory in the local mirror
+// (the mirrorPath config), scans the files of the package, and returns the results keyed by the canonical
+// package URL. Nothing is downloaded. An invalid or unresolved package URL is reported in the result Error.
+func (s *ScanSpecs) ScanPackages() (map[string]*ScanResult, error) {
+	cfg, licenseLibrary, err := s.initLicenseLibrary()
+	if err != nil {
+		return nil, err
+	}
+	mirror := purl.Mirror{Root: cfg.GetString(configurer.MirrorPathFlag)}
+
+	r := make(map[string]*ScanResult)
+	for _, p := range s.Specs {
+		key, scanResult := p.ScanPackage(mirror, licenseLibrary)
+		r[key] = scanResult
+	}
+	return r, nil
+}
+
+// ScanPackage resolves the package URL in the mirror, scans the files of the package, and returns the result
+// with the canonical package URL (or the package URL as specified, if it is invalid)
+func (s *ScanSpec) ScanPackage(mirror purl.Mirror, licenseLibrary *licenses.LicenseLibrary) (string, *ScanResult) {
+	p, err := purl.Parse(s.PURL)
+	if err != nil {
+		return s.PURL, &ScanResult{Spec: *s, CycloneDXLicenses: Licenses{}, Error: err}
+	}
+	key := p.String()
+
+	dir, err := mirror.Resolve(p)
+	if err != nil {
+		return key, &ScanResult{Spec: *s, CycloneDXLicenses: Licenses{}, Error: err}
+	}
+	r := s.ScanDirectory(dir, licenseLibrary)
+	if r.Spec.Location == "" {
+		r.Spec.Location = dir
+	}
+	return key, r
+}
+
+// ScanDirectory scans the files in the directory (recursively) and returns one result
+// with the licenses found in any of the files
+func (s *ScanSpec) ScanDirectory(dir string, licenseLibrary *licenses.LicenseLibrary) *ScanResult {
+	scans.With("directory").Inc()
+	r := &ScanResult{
+		Spec:              *s,
+		CycloneDXLicenses: Licenses{},
+	}
+
+	results, err := identifier.IdentifyLicensesInDirectory(dir, identifier.Options{}, licenseLibrary)
+	if err != nil {
+		r.Error = err
+		return r
+	}
+
+	var ids []string
+	for _, result := range results {
+		if len(result.Matches) == 0 {
+			continue
+		}
+		r.Files = append(r.Files, result.File)
+		for id := range result.Matches {
+			if !slices.Contains(ids, id) {
+				ids = append(ids, id)
+			}
+		}
+	}
+	sort.Strings(ids)
+	r.CycloneDXLicenses = cycloneDXLicenses(ids, licenseLibrary)
+
+	return s.compareDeclared(r, licenseLibrary)
 }
 
 // ScanFile looks up a specific file by name to retrieve license data.
@@ -13,7 +13,9 @@ import (
 	"github.com/CycloneDX/license-scanner/api/scanner"
 	"github.com/CycloneDX/license-scanner/configurer"
 	"github.com/CycloneDX/license-scanner/licenses"
+	"github.com/CycloneDX/license-scanner/manifest"
 	"github.com/CycloneDX/license-scanner/normalizer"
+	"github.com/CycloneDX/license-scanner/purl"
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 )
@@ -201,6 +203,109 @@ func TestScanSpec_ScanLicenseText_With_CachedResults(t *testing.T) {
 	}
 }
 
+func TestScanSpec_ScanLicenseText_With_DeclaredLicense(t *testing.T) {
+	mitLicense := "Permission is hereby granted, free of charge, to any person obtaining a copy\nof this software and associated documentation files (the \"Software\"), to deal\nin the Software without restriction, including without limitation the rights\nto use, copy, modify, merge, publish, distribute, sublicense, and/or sell\ncopies of the Software, and to permit persons to whom the Software is\nfurnished to do so, subject to the following conditions:\n\nThe above copyright notice and this permission notice shall be included in\nall copies or substantial portions of the Software.\n\nTHE SOFTWARE IS PROVIDED \"AS IS\", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR\nIMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,\nFITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE\nAUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER\nLIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,\nOUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN\nTHE SOFTWARE."
+
+	licenseLibrary, err := licenses.NewLicenseLibrary(nil)
+	if err != nil {
+		t.Fatalf("Error initializing license library %v", err.Error())
+	}
+	if err := licenseLibrary.AddAll(); err != nil {
+		t.Fatalf("Error adding licenses %v", err.Error())
+	}
+
+	tests := []struct {
+		name            string
+		declaredLicense string
+		want            manifest.Outcome
+	}{
+		{name: "declared", declaredLicense: "The MIT License", want: manifest.OutcomeConsistent},
+		{name: "declared more restrictive", declaredLicense: "GPL-3.0-only", want: manifest.OutcomeMorePermissive},
+		{name: "declared choice", declaredLicense: "Apache-2.0 OR MIT", want: manifest.OutcomeConsistent},
+	}
+
+	// the same cache is used for each spec, so the comparison must not be cached with the result
+	cache := map[normalizer.Digest]*scanner.ScanResult{}
+	for _, tt := range tests {
+		spec := scanner.ScanSpec{LicenseText: mitLicense, DeclaredLicense: tt.declaredLicense}
+		actualResult := spec.ScanLicenseText(licenseLibrary, cache)
+		if actualResult.Declared == nil {
+			t.Fatalf("%v: expected the declared license to be compared", tt.name)
+		}
+		if actualResult.Declared.Outcome != tt.want {
+			t.Errorf("%v: got outcome %q, want %q (%v)", tt.name, actualResult.Declared.Outcome, tt.want, actualResult.Declared.Reason)
+		}
+		if actualResult.Spec.DeclaredLicense != tt.declaredLicense {
+			t.Errorf("%v: got spec with declared license %q, want %q", tt.name, actualResult.Spec.DeclaredLicense, tt.declaredLicense)
+		}
+	}
+}
+
+func TestScanSpecs_ScanPackages(t *testing.T) {
+	flags := configurer.NewDefaultFlags()
+	_ = flags.Set(configurer.MirrorPathFlag, "../../testdata/mirror")
+
+	scanSpecs := scanner.ScanSpecs{
+		Specs: []scanner.ScanSpec{
+			{PURL: "pkg:npm/%40scope/example@1.0.0"},
+			{PURL: "pkg:cargo/example@0.1.0", DeclaredLicense: "MIT OR Apache-2.0"},
+			{PURL: "pkg:PyPI/Example_Pkg"},
+			{PURL: "pkg:cargo/example@0.1.0#src"},
+			{PURL: "pkg:npm/missing@1.0.0"},
+			{PURL: "not a purl"},
+		},
+	}
+	results, err := scanSpecs.WithFlags(flags).ScanPackages()
+	if err != nil {
+		t.Fatalf("ScanPackages() error = %v", err)
+	}
+
+	ids := func(r *scanner.ScanResult) []string {
+		var ids []string
+		for _, l := range r.CycloneDXLicenses {
+			if l.License.ID != "" {
+				ids = append(ids, l.License.ID)
+			} else {
+				ids = append(ids, l.License.Name)
+			}
+		}
+		return ids
+	}
+
+	want := map[string][]string{
+		"pkg:npm/%40scope/example@1.0.0": {"MIT"},
+		"pkg:cargo/example@0.1.0":        {"GPL-2.0-only", "GPL-2.0-or-later"},
+		"pkg:pypi/example-pkg":           {"BSD-3-Clause"},
+		"pkg:cargo/example@0.1.0#src":    {scanner.NOASSERTION_SPDX_NAME},
+	}
+	for key, wantIDs := range want {
+		r, ok := results[key]
+		if !ok {
+			t.Errorf("ScanPackages() expected result for %v", key)
+			continue
+		}
+		if r.Error != nil {
+			t.Errorf("ScanPackages() %v error = %v", key, r.Error)
+		}
+		if d := cmp.Diff(wantIDs, ids(r)); d != "" {
+			t.Errorf("ScanPackages() %v (-want, +got): %v", key, d)
+		}
+		if r.Spec.Location == "" {
+			t.Errorf("ScanPackages() %v expected the resolved location", key)
+		}
+	}
+
+	if r := results["pkg:cargo/example@0.1.0"]; r.Declared == nil || r.Declared.Outcome != manifest.OutcomeMoreRestrictive {
+		t.Errorf("ScanPackages() expected the declared license to be less restrictive, got %+v", r.Declared)
+	}
+	if r := results["pkg:npm/missing@1.0.0"]; r == nil || !errors.Is(r.Error, purl.ErrNotFound) {
+		t.Errorf("ScanPackages() expected a not found error, got %+v", r)
+	}
+	if r := results["not a purl"]; r == nil || !errors.Is(r.Error, purl.ErrInvalid) {
+		t.Errorf("ScanPackages() expected an invalid error, got %+v", r)
+	}
+}
+
 func TestScanSpecs_ScanFile(t *testing.T) {
 	async_specs := scanner.ScanSpec{
 		Name:     "async",
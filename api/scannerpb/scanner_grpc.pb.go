@@ -0,0 +1,285 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             v3.21.12
+// source: scanner.proto
+
+package scannerpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// ScannerClient is the client API for Scanner service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ScannerClient interface {
+	// Scan scans the license text (or the package in the mirror) of each specification
+	Scan(ctx context.Context, in *ScanRequest, opts ...grpc.CallOption) (*ScanResponse, error)
+	// ScanFile scans an uploaded file or archive. The first message has the file name and
+	// the following messages have the chunks of the content.
+	ScanFile(ctx context.Context, opts ...grpc.CallOption) (Scanner_ScanFileClient, error)
+	// ScanBatch scans the specifications and streams each result when it is scanned
+	ScanBatch(ctx context.Context, in *ScanRequest, opts ...grpc.CallOption) (Scanner_ScanBatchClient, error)
+	// ListLicenses lists the licenses and exceptions in the license library
+	ListLicenses(ctx context.Context, in *ListLicensesRequest, opts ...grpc.CallOption) (*ListLicensesResponse, error)
+}
+
+type scannerClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewScannerClient(cc grpc.ClientConnInterface) ScannerClient {
+	return &scannerClient{cc}
+}
+
+func (c *scannerClient) Scan(ctx context.Context, in *ScanRequest, opts ...grpc.CallOption) (*ScanResponse, error) {
+	out := new(ScanResponse)
+	err := c.cc.Invoke(ctx, "/licensescanner.v1.Scanner/Scan", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *scannerClient) ScanFile(ctx context.Context, opts ...grpc.CallOption) (Scanner_ScanFileClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Scanner_ServiceDesc.Streams[0], "/licensescanner.v1.Scanner/ScanFile", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &scannerScanFileClient{stream}
+	return x, nil
+}
+
+type Scanner_ScanFileClient interface {
+	Send(*ScanFileRequest) error
+	CloseAndRecv() (*ScanResult, error)
+	grpc.ClientStream
+}
+
+type scannerScanFileClient struct {
+	grpc.ClientStream
+}
+
+func (x *scannerScanFileClient) Send(m *ScanFileRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *scannerScanFileClient) CloseAndRecv() (*ScanResult, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(ScanResult)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *scannerClient) ScanBatch(ctx context.Context, in *ScanRequest, opts ...grpc.CallOption) (Scanner_ScanBatchClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Scanner_ServiceDesc.Streams[1], "/licensescanner.v1.Scanner/ScanBatch", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &scannerScanBatchClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Scanner_ScanBatchClient interface {
+	Recv() (*ScanResult, error)
+	grpc.ClientStream
+}
+
+type scannerScanBatchClient struct {
+	grpc.ClientStream
+}
+
+func (x *scannerScanBatchClient) Recv() (*ScanResult, error) {
+	m := new(ScanResult)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *scannerClient) ListLicenses(ctx context.Context, in *ListLicensesRequest, opts ...grpc.CallOption) (*ListLicensesResponse, error) {
+	out := new(ListLicensesResponse)
+	err := c.cc.Invoke(ctx, "/licensescanner.v1.Scanner/ListLicenses", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ScannerServer is the server API for Scanner service.
+// All implementations must embed UnimplementedScannerServer
+// for forward compatibility
+type ScannerServer interface {
+	// Scan scans the license text (or the package in the mirror) of each specification
+	Scan(context.Context, *ScanRequest) (*ScanResponse, error)
+	// ScanFile scans an uploaded file or archive. The first message has the file name and
+	// the following messages have the chunks of the content.
+	ScanFile(Scanner_ScanFileServer) error
+	// ScanBatch scans the specifications and streams each result when it is scanned
+	ScanBatch(*ScanRequest, Scanner_ScanBatchServer) error
+	// ListLicenses lists the licenses and exceptions in the license library
+	ListLicenses(context.Context, *ListLicensesRequest) (*ListLicensesResponse, error)
+	mustEmbedUnimplementedScannerServer()
+}
+
+// UnimplementedScannerServer must be embedded to have forward compatible implementations.
+type UnimplementedScannerServer struct {
+}
+
+func (UnimplementedScannerServer) Scan(context.Context, *ScanRequest) (*ScanResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Scan not implemented")
+}
+func (UnimplementedScannerServer) ScanFile(Scanner_ScanFileServer) error {
+	return status.Errorf(codes.Unimplemented, "method ScanFile not implemented")
+}
+func (UnimplementedScannerServer) ScanBatch(*ScanRequest, Scanner_ScanBatchServer) error {
+	return status.Errorf(codes.Unimplemented, "method ScanBatch not implemented")
+}
+func (UnimplementedScannerServer) ListLicenses(context.Context, *ListLicensesRequest) (*ListLicensesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListLicenses not implemented")
+}
+func (UnimplementedScannerServer) mustEmbedUnimplementedScannerServer() {}
+
+// UnsafeScannerServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ScannerServer will
+// result in compilation errors.
+type UnsafeScannerServer interface {
+	mustEmbedUnimplementedScannerServer()
+}
+
+func RegisterScannerServer(s grpc.ServiceRegistrar, srv ScannerServer) {
+	s.RegisterService(&Scanner_ServiceDesc, srv)
+}
+
+func _Scanner_Scan_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ScanRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ScannerServer).Scan(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/licensescanner.v1.Scanner/Scan",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ScannerServer).Scan(ctx, req.(*ScanRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Scanner_ScanFile_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(ScannerServer).ScanFile(&scannerScanFileServer{stream})
+}
+
+type Scanner_ScanFileServer interface {
+	SendAndClose(*ScanResult) error
+	Recv() (*ScanFileRequest, error)
+	grpc.ServerStream
+}
+
+type scannerScanFileServer struct {
+	grpc.ServerStream
+}
+
+func (x *scannerScanFileServer) SendAndClose(m *ScanResult) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *scannerScanFileServer) Recv() (*ScanFileRequest, error) {
+	m := new(ScanFileRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _Scanner_ScanBatch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ScanRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ScannerServer).ScanBatch(m, &scannerScanBatchServer{stream})
+}
+
+type Scanner_ScanBatchServer interface {
+	Send(*ScanResult) error
+	grpc.ServerStream
+}
+
+type scannerScanBatchServer struct {
+	grpc.ServerStream
+}
+
+func (x *scannerScanBatchServer) Send(m *ScanResult) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Scanner_ListLicenses_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListLicensesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ScannerServer).ListLicenses(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/licensescanner.v1.Scanner/ListLicenses",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ScannerServer).ListLicenses(ctx, req.(*ListLicensesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Scanner_ServiceDesc is the grpc.ServiceDesc for Scanner service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Scanner_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "licensescanner.v1.Scanner",
+	HandlerType: (*ScannerServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Scan",
+			Handler:    _Scanner_Scan_Handler,
+		},
+		{
+			MethodName: "ListLicenses",
+			Handler:    _Scanner_ListLicenses_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ScanFile",
+			Handler:       _Scanner_ScanFile_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "ScanBatch",
+			Handler:       _Scanner_ScanBatch_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "scanner.proto",
+}
@@ -0,0 +1,143 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package client is the Go client of the gRPC API of the license scanner (license-scanner serve --grpcAddr).
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/CycloneDX/license-scanner/api/scannerpb"
+)
+
+// ChunkSize is the size of the chunks in which files are uploaded
+const ChunkSize = 64 << 10
+
+// Client scans license text, files, and packages with a license scanner service
+type Client struct {
+	conn    *grpc.ClientConn
+	scanner scannerpb.ScannerClient
+}
+
+// Dial connects to the service at the target address. The connection is not secure (plaintext),
+// unless the options have transport credentials.
+func Dial(ctx context.Context, target string, opts ...grpc.DialOption) (*Client, error) {
+	opts = append([]grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}, opts...)
+	conn, err := grpc.DialContext(ctx, target, opts...)
+	if err != nil {
+		return nil, err
+	}
+	c := New(conn)
+	c.conn = conn
+	return c, nil
+}
+
+// New returns a client using the connection, which is not closed by Close
+func New(conn grpc.ClientConnInterface) *Client {
+	return &Client{scanner: scannerpb.NewScannerClient(conn)}
+}
+
+// Close closes the connection of a client from Dial
+func (c *Client) Close() error {
+	if c.conn == nil {
+		return nil
+	}
+	return c.conn.Close()
+}
+
+// ScanText scans the license text and compares the declared license (if any) with the detected licenses
+func (c *Client) ScanText(ctx context.Context, text string, declaredLicense string) (*scannerpb.ScanResult, error) {
+	results, err := c.Scan(ctx, &scannerpb.ScanSpec{LicenseText: text, DeclaredLicense: declaredLicense})
+	if err != nil {
+		return nil, err
+	}
+	return results[0], nil
+}
+
+// Scan scans the specifications and returns the results in the same order
+func (c *Client) Scan(ctx context.Context, specs ...*scannerpb.ScanSpec) ([]*scannerpb.ScanResult, error) {
+	response, err := c.scanner.Scan(ctx, &scannerpb.ScanRequest{Specs: specs})
+	if err != nil {
+		return nil, err
+	}
+	if len(response.GetResults()) != len(specs) {
+		return nil, errors.New("the scan response does not have a result for each specification")
+	}
+	return response.GetResults(), nil
+}
+
+// ScanBatch scans the specifications and calls fn with each result as it is received.
+// The result Index is the index of its specification. An error from fn stops the batch.
+func (c *Client) ScanBatch(ctx context.Context, specs []*scannerpb.ScanSpec, fn func(*scannerpb.ScanResult) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := c.scanner.ScanBatch(ctx, &scannerpb.ScanRequest{Specs: specs})
+	if err != nil {
+		return err
+	}
+	for {
+		result, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := fn(result); err != nil {
+			return err
+		}
+	}
+}
+
+// ScanFile uploads the content of the file in chunks and scans it.
+// Archives (by the file name, e.g. .zip or .tgz) are extracted by the service and scanned as a directory.
+func (c *Client) ScanFile(ctx context.Context, info *scannerpb.FileInfo, r io.Reader) (*scannerpb.ScanResult, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := c.scanner.ScanFile(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := stream.Send(&scannerpb.ScanFileRequest{Data: &scannerpb.ScanFileRequest_Info{Info: info}}); err != nil {
+		return nil, closeAndRecv(stream, err)
+	}
+
+	buf := make([]byte, ChunkSize)
+	for {
+		n, err := r.Read(buf)
+		if n > 0 {
+			chunk := &scannerpb.ScanFileRequest{Data: &scannerpb.ScanFileRequest_Chunk{Chunk: buf[:n]}}
+			if err := stream.Send(chunk); err != nil {
+				return nil, closeAndRecv(stream, err)
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return stream.CloseAndRecv()
+}
+
+// closeAndRecv returns the status of the stream when a message could not be sent
+// (io.EOF from Send means the service ended the stream, e.g. when the file is too large)
+func closeAndRecv(stream scannerpb.Scanner_ScanFileClient, err error) error {
+	if !errors.Is(err, io.EOF) {
+		return err
+	}
+	_, err = stream.CloseAndRecv()
+	return err
+}
+
+// ListLicenses lists the licenses and exceptions of the service, optionally with the deprecated ones
+func (c *Client) ListLicenses(ctx context.Context, includeDeprecated bool) (*scannerpb.ListLicensesResponse, error) {
+	return c.scanner.ListLicenses(ctx, &scannerpb.ListLicensesRequest{IncludeDeprecated: includeDeprecated})
+}
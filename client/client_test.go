@@ -0,0 +1,216 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package client
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/CycloneDX/license-scanner/api/scannerpb"
+	"github.com/CycloneDX/license-scanner/licenses"
+	"github.com/CycloneDX/license-scanner/manifest"
+	"github.com/CycloneDX/license-scanner/server"
+	"github.com/CycloneDX/license-scanner/server/servertest"
+)
+
+const mitLicenseFile = "../testdata/manifests/npm/LICENSE"
+
+func testClient(t *testing.T, options server.Options, loaded bool) *Client {
+	t.Helper()
+	s := server.New(options)
+	if loaded {
+		licenseLibrary, err := licenses.NewLicenseLibrary(nil)
+		if err != nil {
+			t.Fatalf("NewLicenseLibrary() error = %v", err)
+		}
+		if err := licenseLibrary.AddAll(); err != nil {
+			t.Fatalf("licenseLibrary.AddAll() error = %v", err)
+		}
+		s.SetLicenseLibrary(licenseLibrary)
+	}
+	return New(servertest.NewGRPC(t, s))
+}
+
+func licenseIDs(r *scannerpb.ScanResult) []string {
+	var ids []string
+	for _, l := range r.GetLicenses() {
+		if l.GetId() != "" {
+			ids = append(ids, l.GetId())
+		} else {
+			ids = append(ids, l.GetName())
+		}
+	}
+	return ids
+}
+
+func wantCode(t *testing.T, err error, want codes.Code) {
+	t.Helper()
+	if got := status.Code(err); got != want {
+		t.Errorf("error = %v, want code %v", err, want)
+	}
+}
+
+func TestClient_NotReady(t *testing.T) {
+	t.Parallel()
+	c := testClient(t, server.Options{}, false)
+	ctx := context.Background()
+
+	_, err := c.ScanText(ctx, "MIT", "")
+	wantCode(t, err, codes.Unavailable)
+	_, err = c.ListLicenses(ctx, false)
+	wantCode(t, err, codes.Unavailable)
+}
+
+func TestClient(t *testing.T) {
+	t.Parallel()
+	c := testClient(t, server.Options{MaxRequestBytes: 256 << 10, MaxExtractedBytes: 256 << 10, MaxConcurrentScans: 2}, true)
+	ctx := context.Background()
+	mit, err := os.ReadFile(mitLicenseFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("scan text", func(t *testing.T) {
+		got, err := c.ScanText(ctx, string(mit), "GPL-3.0-only")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d := cmp.Diff([]string{"MIT"}, licenseIDs(got)); d != "" {
+			t.Errorf("licenses (-want, +got): %v", d)
+		}
+		if got.GetHash().GetSha256() == "" {
+			t.Errorf("expected the hash of the license text")
+		}
+		if got.GetDeclared().GetOutcome() != string(manifest.OutcomeMorePermissive) {
+			t.Errorf("declared = %v, want outcome %v", got.GetDeclared(), manifest.OutcomeMorePermissive)
+		}
+	})
+
+	t.Run("scan", func(t *testing.T) {
+		got, err := c.Scan(ctx,
+			&scannerpb.ScanSpec{Name: "mit", LicenseText: string(mit)},
+			&scannerpb.ScanSpec{Name: "unknown", LicenseText: "this is not a license"},
+			&scannerpb.ScanSpec{Name: "empty"},
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d := cmp.Diff([]string{"MIT"}, licenseIDs(got[0])); d != "" {
+			t.Errorf("licenses (-want, +got): %v", d)
+		}
+		if d := cmp.Diff([]string{"NOASSERTION"}, licenseIDs(got[1])); d != "" {
+			t.Errorf("licenses (-want, +got): %v", d)
+		}
+		if got[2].GetError() == "" {
+			t.Errorf("expected an error for empty license text")
+		}
+		_, err = c.Scan(ctx)
+		wantCode(t, err, codes.InvalidArgument)
+	})
+
+	t.Run("scan batch", func(t *testing.T) {
+		specs := []*scannerpb.ScanSpec{
+			{Name: "a", LicenseText: string(mit)},
+			{Name: "b", LicenseText: "this is not a license"},
+			{Name: "c", LicenseText: string(mit)},
+		}
+		var names []string
+		var indexes []int32
+		err := c.ScanBatch(ctx, specs, func(r *scannerpb.ScanResult) error {
+			names = append(names, r.GetSpec().GetName())
+			indexes = append(indexes, r.GetIndex())
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d := cmp.Diff([]string{"a", "b", "c"}, names); d != "" {
+			t.Errorf("names (-want, +got): %v", d)
+		}
+		if d := cmp.Diff([]int32{0, 1, 2}, indexes); d != "" {
+			t.Errorf("indexes (-want, +got): %v", d)
+		}
+	})
+
+	t.Run("scan file", func(t *testing.T) {
+		got, err := c.ScanFile(ctx, &scannerpb.FileInfo{Filename: "LICENSE"}, bytes.NewReader(mit))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.GetSpec().GetName() != "LICENSE" {
+			t.Errorf("name = %q, want LICENSE", got.GetSpec().GetName())
+		}
+		if d := cmp.Diff([]string{"MIT"}, licenseIDs(got)); d != "" {
+			t.Errorf("licenses (-want, +got): %v", d)
+		}
+	})
+
+	t.Run("scan archive", func(t *testing.T) {
+		var b bytes.Buffer
+		gw := gzip.NewWriter(&b)
+		tw := tar.NewWriter(gw)
+		for name, content := range map[string][]byte{"package/LICENSE": mit, "package/README": []byte("readme")} {
+			_ = tw.WriteHeader(&tar.Header{Name: name, Mode: 0o600, Size: int64(len(content)), Typeflag: tar.TypeReg})
+			_, _ = tw.Write(content)
+		}
+		_ = tw.Close()
+		_ = gw.Close()
+
+		got, err := c.ScanFile(ctx, &scannerpb.FileInfo{Filename: "package.tgz", Name: "package"}, &b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.GetSpec().GetName() != "package" {
+			t.Errorf("name = %q, want package", got.GetSpec().GetName())
+		}
+		if d := cmp.Diff([]string{"MIT"}, licenseIDs(got)); d != "" {
+			t.Errorf("licenses (-want, +got): %v", d)
+		}
+		if d := cmp.Diff([]string{"package/LICENSE"}, got.GetFiles()); d != "" {
+			t.Errorf("files (-want, +got): %v", d)
+		}
+	})
+
+	t.Run("invalid files", func(t *testing.T) {
+		_, err := c.ScanFile(ctx, &scannerpb.FileInfo{}, bytes.NewReader(mit))
+		wantCode(t, err, codes.InvalidArgument)
+		_, err = c.ScanFile(ctx, &scannerpb.FileInfo{Filename: "package.zip"}, bytes.NewReader(mit))
+		wantCode(t, err, codes.InvalidArgument)
+		_, err = c.ScanFile(ctx, &scannerpb.FileInfo{Filename: "big.txt"}, bytes.NewReader(bytes.Repeat([]byte("x"), 257<<10)))
+		wantCode(t, err, codes.ResourceExhausted)
+	})
+
+	t.Run("list licenses", func(t *testing.T) {
+		got, err := c.ListLicenses(ctx, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		withDeprecated, err := c.ListLicenses(ctx, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var found bool
+		for _, l := range got.GetLicenses() {
+			found = found || l.GetId() == "MIT"
+			if l.GetIsDeprecated() {
+				t.Errorf("unexpected deprecated license %v", l.GetId())
+			}
+		}
+		if !found {
+			t.Errorf("expected MIT in %v licenses", len(got.GetLicenses()))
+		}
+		if len(withDeprecated.GetLicenses()) < len(got.GetLicenses()) {
+			t.Errorf("got %v licenses with the deprecated licenses, want at least %v", len(withDeprecated.GetLicenses()), len(got.GetLicenses()))
+		}
+	})
+}
@@ -0,0 +1,83 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/CycloneDX/license-scanner/debugger"
+	"github.com/CycloneDX/license-scanner/identifier"
+	"github.com/CycloneDX/license-scanner/licenses"
+	"github.com/CycloneDX/license-scanner/normalizer"
+
+	"github.com/spf13/cobra"
+)
+
+func newExplainCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "explain <license-id> <file>",
+		Short: "Explain why a license did or did not match a file",
+		Long: `Explain why the license <license-id> did or did not match the text of <file>.
+Use "-" as the file to read the text from stdin.
+
+For each template (primary pattern) of the license, the report shows which prechecks (static blocks
+which must be in the text) passed or failed, and whether the template matched. When it did not match,
+the report shows the longest prefix of the template which matches, and where the text diverges from
+the template (the expected template text, the normalized text found, and the line and column in the file).
+When no template matches, the aliases and URLs of the license which were tried are shown.`,
+		Example: "  license-scanner explain MIT LICENSE.txt",
+		Args:    cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := initConfig(cmd)
+			if err != nil {
+				return err
+			}
+
+			licenseLibrary, err := licenses.NewLicenseLibrary(cfg)
+			if err != nil {
+				return err
+			}
+			if err := licenseLibrary.AddAll(); err != nil {
+				return err
+			}
+
+			options := identifier.Options{ForceResult: true}
+			var results identifier.IdentifierResults
+			if args[1] == stdinPath {
+				results, err = identifyLicensesInReader(cmd.InOrStdin(), options, licenseLibrary)
+			} else {
+				results, err = identifier.IdentifyLicensesInFile(args[1], options, licenseLibrary)
+			}
+			if err != nil {
+				return err
+			}
+			return explainLicense(cmd.OutOrStdout(), licenseLibrary, args[0], results)
+		},
+	}
+}
+
+// explainLicense writes why the license did or did not match the text of the results
+func explainLicense(w io.Writer, licenseLibrary *licenses.LicenseLibrary, id string, results identifier.IdentifierResults) error {
+	license, ok := licenseLibrary.LicenseMap[id]
+	if !ok {
+		return fmt.Errorf("license ID %v is not in the license library", id)
+	}
+
+	ProjectLogger.Info("Looking for a specific license")
+	var language *normalizer.Language
+	if results.File != stdinName {
+		language = normalizer.DetectLanguage(results.File, results.OriginalText)
+	}
+	explanation, err := debugger.Explain(license, licenseLibrary, results.OriginalText, language)
+	if err != nil {
+		return err
+	}
+	explanation.WriteText(w)
+
+	// The scan result may differ, e.g. when a license exception (mutator) is applied to the license
+	if _, identified := results.Matches[id]; identified != explanation.Matched {
+		fmt.Fprintf(w, "\nThe scan result differs: license %v was %videntified after the other licenses were applied\n", id, map[bool]string{true: "", false: "not "}[identified])
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"github.com/CycloneDX/license-scanner/configurer"
+	"github.com/CycloneDX/license-scanner/importer"
+
+	"github.com/spf13/cobra"
+)
+
+func newImportCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "import <dir>",
+		Short: "Add licenses from a directory to the SPDX or custom templates",
+		Long: `Validate, preprocess, and copy the license templates in <dir> into the SPDX or custom resources.
+The destination is set with one of the spdx, spdxPath, custom, or customPath flags.`,
+		Example: "  license-scanner import ./input --spdxPath ./resources/spdx/3.21",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := initConfig(cmd)
+			if err != nil {
+				return err
+			}
+			cfg.Set(configurer.AddAllFlag, args[0])
+			return importer.Import(cfg)
+		},
+	}
+}
+
+func newUpdateCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "update",
+		Short: "Update the preprocessed prechecks of existing licenses",
+		Long: `Validate, preprocess, and update the prechecks of the SPDX or custom templates in-place.
+The templates to update are set with one of the spdx, spdxPath, custom, or customPath flags.`,
+		Example: "  license-scanner update --customPath ./resources/custom/mine",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := initConfig(cmd)
+			if err != nil {
+				return err
+			}
+			cfg.Set(configurer.UpdateAllFlag, true)
+			return importer.Update(cfg)
+		},
+	}
+}
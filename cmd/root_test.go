@@ -14,6 +14,11 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/CycloneDX/license-scanner/configurer"
+	"github.com/CycloneDX/license-scanner/identifier"
+	"github.com/CycloneDX/license-scanner/licenses"
+
+	"github.com/google/go-cmp/cmp"
 	"github.com/spf13/viper"
 )
 
@@ -227,3 +232,316 @@ func Test_CLI_list_spdx(t *testing.T) {
 		t.Fatalf("Expected nil err for valid --spdx dir and --list got: %v", err)
 	}
 }
+
+func Test_CLI_help_commands(t *testing.T) {
+	t.Parallel()
+	cmd := NewRootCmd()
+	bOut := bytes.NewBufferString("")
+	cmd.SetOut(bOut)
+	cmd.SetArgs([]string{"--help"})
+	if err := cmd.Execute(); err != nil {
+		t.Error(err)
+	}
+	for _, expected := range []string{"scan ", "list ", "import ", "update ", "explain ", "trace "} {
+		if !strings.Contains(bOut.String(), expected) {
+			t.Errorf("expected help listing command %q got %s", expected, bOut.String())
+		}
+	}
+	// The deprecated flags still work, but are no longer in the help
+	if strings.Contains(bOut.String(), "--addAll") {
+		t.Errorf("expected help without deprecated flag --addAll got %s", bOut.String())
+	}
+}
+
+func Test_CLI_scan(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr bool
+	}{
+		{name: "file", args: []string{"scan", "-c", "../testdata/addAll/input/text/0BSD.txt"}},
+		{name: "files and dir", args: []string{"scan", "-q", "../testdata/addAll/input/text/0BSD.txt", "../testdata/addAll/input/text"}},
+		{name: "declared licenses", args: []string{"scan", "-q", "--declared", "../testdata/manifests"}},
+		{name: "package URL", args: []string{"scan", "-q", "--mirrorPath", "../testdata/mirror", "pkg:npm/%40scope/example@1.0.0"}},
+		{name: "package URL not found", args: []string{"scan", "-q", "--mirrorPath", "../testdata/mirror", "pkg:npm/missing@1.0.0"}, wantErr: true},
+		{name: "supplied declared license", args: []string{"scan", "-q", "--declaredLicense", "MIT", "../testdata/manifests/cargo"}},
+		{name: "global flags after command", args: []string{"scan", "../testdata/addAll/input/text/0BSD.txt", "--spdx", "default"}},
+		{name: "no paths", args: []string{"scan"}, wantErr: true},
+		{name: "not found", args: []string{"scan", "../testdata/addAll/input/text/0BSD.txt", "FILE.TXT"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			cmd := NewRootCmd()
+			cmd.SetOut(bytes.NewBufferString(""))
+			cmd.SetErr(bytes.NewBufferString(""))
+			cmd.SetArgs(tt.args)
+			if err := cmd.Execute(); (err != nil) != tt.wantErr {
+				t.Errorf("scan error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_CLI_scan_metrics(t *testing.T) {
+	t.Parallel()
+	f := path.Join(t.TempDir(), "metrics.txt")
+	cmd := NewRootCmd()
+	cmd.SetArgs([]string{"scan", "-q", "--metrics", f, "../testdata/addAll/input/text/0BSD.txt"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("scan --metrics error = %v", err)
+	}
+	b, err := os.ReadFile(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"# TYPE license_scanner_files_scanned_total counter", `license_scanner_license_evaluation_seconds_count{license="0BSD"}`} {
+		if !strings.Contains(string(b), want) {
+			t.Errorf("expected %q in the metrics", want)
+		}
+	}
+}
+
+func Test_CLI_serve_invalid_addr(t *testing.T) {
+	t.Parallel()
+	cmd := NewRootCmd()
+	cmd.SetArgs([]string{"serve", "-q", "--addr", "invalid:address:"})
+	if err := cmd.Execute(); err == nil {
+		t.Errorf("Expected an error for an invalid address")
+	}
+}
+
+func Test_CLI_serve_invalid_grpc_addr(t *testing.T) {
+	t.Parallel()
+	cmd := NewRootCmd()
+	cmd.SetArgs([]string{"serve", "-q", "--addr", "127.0.0.1:0", "--grpcAddr", "invalid:address:"})
+	if err := cmd.Execute(); err == nil {
+		t.Errorf("Expected an error for an invalid gRPC address")
+	}
+}
+
+func Test_CLI_list_command(t *testing.T) {
+	t.Parallel()
+	cmd := NewRootCmd()
+	cmd.SetArgs([]string{"list", "--spdx", "default"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Expected nil err for list got: %v", err)
+	}
+}
+
+// Test_CLI_import_Bogus verifies that import <dir-does-not-exist> returns a ErrNotExist error
+func Test_CLI_import_Bogus(t *testing.T) {
+	t.Parallel()
+	cmd := NewRootCmd()
+	cmd.SetArgs([]string{"import", "../testdata/addAll/bogus/no-dir-here", "--spdx", "testing"})
+	if err := cmd.Execute(); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("Expected ErrNotExist got: %v", err)
+	}
+}
+
+func Test_CLI_explain(t *testing.T) {
+	t.Parallel()
+	cmd := NewRootCmd()
+	cmd.SetArgs([]string{"explain", "0BSD", "../testdata/addAll/input/text/0BSD.txt"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Got unexpected error: %v", err)
+	}
+
+	cmd = NewRootCmd()
+	cmd.SetOut(bytes.NewBufferString(""))
+	cmd.SetErr(bytes.NewBufferString(""))
+	cmd.SetArgs([]string{"explain", "NOT-A-LICENSE", "../testdata/addAll/input/text/0BSD.txt"})
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "NOT-A-LICENSE") {
+		t.Fatalf("Expected error for unknown license ID got: %v", err)
+	}
+}
+
+func Test_CLI_trace(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		args     []string
+		expected []string
+		wantErr  bool
+	}{
+		{
+			name:     "file",
+			args:     []string{"trace", "../testdata/addAll/input/text/0BSD.txt"},
+			expected: []string{"Normalizing ../testdata/addAll/input/text/0BSD.txt (input text)", " 1. normalizeUnicode ", "20. replaceVarietalWordSpellings ", "Normalized text:"},
+		},
+		{
+			name:     "source",
+			args:     []string{"trace", "../testdata/comments/mit.c"},
+			expected: []string{"Normalizing ../testdata/comments/mit.c (C source)", " 1. normalizeUnicode ", " 2. extractComments (", "int main(void)"},
+		},
+		{
+			name:     "template",
+			args:     []string{"trace", "--template", "../resources/spdx/default/template/0BSD.template.txt"},
+			expected: []string{"(template)", "<<[-beginoptional-]{+omitable+}>>"},
+		},
+		{
+			name:     "license",
+			args:     []string{"trace", "--license", "0BSD"},
+			expected: []string{"Normalizing spdx/default/template/0BSD.template.txt (template)"},
+		},
+		{name: "unknown license", args: []string{"trace", "--license", "NOT-A-LICENSE"}, wantErr: true},
+		{name: "file and license", args: []string{"trace", "--license", "0BSD", "../testdata/addAll/input/text/0BSD.txt"}, wantErr: true},
+		{name: "nothing to trace", args: []string{"trace"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			cmd := NewRootCmd()
+			bOut := bytes.NewBufferString("")
+			cmd.SetOut(bOut)
+			cmd.SetErr(bytes.NewBufferString(""))
+			cmd.SetArgs(tt.args)
+			if err := cmd.Execute(); (err != nil) != tt.wantErr {
+				t.Fatalf("trace error = %v, wantErr %v", err, tt.wantErr)
+			}
+			for _, expected := range tt.expected {
+				if !strings.Contains(bOut.String(), expected) {
+					t.Errorf("expected output containing %q got %s", expected, bOut.String())
+				}
+			}
+		})
+	}
+}
+
+func Test_CLI_scan_stdin(t *testing.T) {
+	t.Parallel()
+	input, err := os.ReadFile("../testdata/addAll/input/text/0BSD.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr bool
+	}{
+		{name: "scan", args: []string{"scan", "-c", "-k", "-"}},
+		{name: "deprecated file flag", args: []string{"-f", "-"}},
+		{name: "license debugging", args: []string{"scan", "--license", "0BSD", "-"}},
+		{name: "explain", args: []string{"explain", "0BSD", "-"}},
+		{name: "trace", args: []string{"trace", "-"}},
+		{name: "stdin and file", args: []string{"scan", "-", "../testdata/addAll/input/text/0BSD.txt"}},
+		{name: "stdin twice", args: []string{"scan", "-", "-"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			cmd := NewRootCmd()
+			cmd.SetIn(bytes.NewReader(input))
+			cmd.SetOut(bytes.NewBufferString(""))
+			cmd.SetErr(bytes.NewBufferString(""))
+			cmd.SetArgs(tt.args)
+			if err := cmd.Execute(); (err != nil) != tt.wantErr {
+				t.Errorf("scan stdin error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_identifyLicensesInReader(t *testing.T) {
+	t.Parallel()
+	input, err := os.ReadFile("../testdata/addAll/input/text/0BSD.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := configurer.InitConfig(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	licenseLibrary, err := licenses.NewLicenseLibrary(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := licenseLibrary.AddAll(); err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := identifyLicensesInReader(bytes.NewReader(input), identifier.Options{}, licenseLibrary)
+	if err != nil {
+		t.Fatalf("identifyLicensesInReader() error = %v", err)
+	}
+	if results.File != stdinName {
+		t.Errorf("identifyLicensesInReader() File = %q want %q", results.File, stdinName)
+	}
+	if _, ok := results.Matches["0BSD"]; !ok {
+		t.Errorf("identifyLicensesInReader() expected 0BSD match got %v", results.Matches)
+	}
+
+	tooLarge := strings.NewReader(strings.Repeat("x", maxInputSize+1))
+	if _, err := identifyLicensesInReader(tooLarge, identifier.Options{}, licenseLibrary); err == nil {
+		t.Error("identifyLicensesInReader() expected error for input that is too large")
+	}
+}
+
+func Test_readFilesFrom(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{name: "newline", input: "a/LICENSE\nb/COPYING\n", want: []string{"a/LICENSE", "b/COPYING"}},
+		{name: "CRLF and blank lines", input: "a/LICENSE\r\n\r\nb/COPYING", want: []string{"a/LICENSE", "b/COPYING"}},
+		{name: "NUL", input: "a/LICENSE\x00b/my\nCOPYING\x00", want: []string{"a/LICENSE", "b/my\nCOPYING"}},
+		{name: "empty", input: "", want: nil},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := readFilesFrom(stdinPath, strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatalf("readFilesFrom() error = %v", err)
+			}
+			if d := cmp.Diff(tt.want, got); d != "" {
+				t.Errorf("readFilesFrom() (-want, +got): %v", d)
+			}
+		})
+	}
+}
+
+func Test_CLI_scan_filesFrom(t *testing.T) {
+	t.Parallel()
+	list := path.Join(t.TempDir(), "files.txt")
+	if err := os.WriteFile(list, []byte("../testdata/addAll/input/text/0BSD.txt\n../README.md\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name    string
+		args    []string
+		stdin   string
+		wantErr bool
+	}{
+		{name: "list file", args: []string{"scan", "--filesFrom", list}},
+		{name: "list file and paths", args: []string{"scan", "--filesFrom", list, "../LICENSE"}},
+		{name: "files-from alias", args: []string{"scan", "--files-from", list}},
+		{name: "files-from alias without command", args: []string{"--files-from=" + list}},
+		{name: "list from stdin", args: []string{"scan", "--filesFrom", "-"}, stdin: "../LICENSE\x00../README.md\x00"},
+		{name: "deprecated without command", args: []string{"--filesFrom", list}},
+		{name: "list and text from stdin", args: []string{"scan", "--filesFrom", "-", "-"}, stdin: "../LICENSE", wantErr: true},
+		{name: "list not found", args: []string{"scan", "--filesFrom", "bogus.txt"}, wantErr: true},
+		{name: "empty list", args: []string{"scan", "--filesFrom", "-"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			cmd := NewRootCmd()
+			cmd.SetIn(strings.NewReader(tt.stdin))
+			cmd.SetOut(bytes.NewBufferString(""))
+			cmd.SetErr(bytes.NewBufferString(""))
+			cmd.SetArgs(tt.args)
+			if err := cmd.Execute(); (err != nil) != tt.wantErr {
+				t.Errorf("scan --filesFrom error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,556 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/CycloneDX/license-scanner/configurer"
+	"github.com/CycloneDX/license-scanner/identifier"
+	"github.com/CycloneDX/license-scanner/licenses"
+	"github.com/CycloneDX/license-scanner/manifest"
+	"github.com/CycloneDX/license-scanner/metrics"
+	"github.com/CycloneDX/license-scanner/notice"
+	"github.com/CycloneDX/license-scanner/purl"
+	"github.com/CycloneDX/license-scanner/report"
+	"github.com/CycloneDX/sbom-utility/log"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	// stdinPath is the path argument used to scan text from stdin
+	stdinPath = "-"
+	// stdinName is the file name used in the results and output for text from stdin
+	stdinName = "<stdin>"
+	// stdoutPath is the file name used to write to stdout
+	stdoutPath = "-"
+	// maxInputSize is the same limit used by identifier.IdentifyLicensesInFile
+	maxInputSize = 1000000
+)
+
+func newScanCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "scan [<path>...]",
+		Short: "Scan files and directories to detect licenses",
+		Long: `Scan one or more files and/or directories to detect licenses.
+Directories are scanned recursively. The license library is loaded once for all the paths.
+Use "-" as the path to scan text from stdin. Use --filesFrom to read a list of paths to scan from a file.
+Use a package URL (e.g. pkg:npm/lodash@4.17.21) as the path to scan a package in the --mirrorPath directory.
+Files are scanned concurrently and the output is combined.`,
+		Example: `  license-scanner scan LICENSE.txt
+  license-scanner scan -c -x ./a/LICENSE ./b/COPYING
+  license-scanner scan --notice markdown ./vendor
+  curl -s https://example.com/LICENSE | license-scanner scan -
+  find . -name 'LICENSE*' -print0 | license-scanner scan --filesFrom -
+  license-scanner scan --mirrorPath ./mirror pkg:cargo/serde@1.0.147`,
+		Args: cobra.ArbitraryArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := initConfig(cmd)
+			if err != nil {
+				return err
+			}
+			return scan(cfg, cmd.InOrStdin(), args)
+		},
+	}
+	configurer.AddScanFlags(cmd.Flags())
+	return cmd
+}
+
+// scan identifies the licenses in each file or directory path and prints the results (or writes a NOTICE).
+// The path "-" reads the text to scan from in (stdin).
+func scan(cfg *viper.Viper, in io.Reader, paths []string) (err error) {
+	ProjectLogger.Enter()
+	defer ProjectLogger.Exit()
+	defer func() {
+		// The metrics are also written when the scan fails, to see how far it got
+		if metricsErr := writeMetrics(cfg); err == nil {
+			err = metricsErr
+		}
+	}()
+	startTime := time.Now().UnixMicro()
+	defer logScanTimeMS(startTime)
+	ProjectLogger.Info("Looking for all licenses")
+
+	licenseLibrary, err := licenses.NewLicenseLibrary(cfg)
+	if err != nil {
+		return err
+	}
+	if err := licenseLibrary.AddAll(); err != nil {
+		return err
+	}
+
+	options := scanOptions(cfg)
+	noticeFormat := cfg.GetString(configurer.NoticeFlag)
+
+	var readStdin bool
+	if filesFrom := cfg.GetString(configurer.FilesFromFlag); filesFrom != "" {
+		list, err := readFilesFrom(filesFrom, in)
+		if err != nil {
+			return err
+		}
+		paths = append(paths, list...)
+		readStdin = filesFrom == stdinPath
+	}
+	if len(paths) == 0 {
+		return errors.New("you must provide a path to scan")
+	}
+	if paths, err = resolvePackageURLs(cfg, paths); err != nil {
+		return err
+	}
+
+	// Scan the files concurrently first. The directories and stdin are scanned in order below.
+	fileResults, err := identifyLicensesInFiles(paths, options, licenseLibrary)
+	if err != nil {
+		return err
+	}
+
+	// Paths in the NOTICE and report are relative to the directory when only one directory is scanned
+	var root string
+	var all []identifier.IdentifierResults
+	for _, p := range paths {
+		var results identifier.IdentifierResults
+		if p == stdinPath {
+			if readStdin {
+				return fmt.Errorf("stdin (%q) can only be scanned once", stdinPath)
+			}
+			readStdin = true
+			if results, err = identifyLicensesInReader(in, options, licenseLibrary); err != nil {
+				return err
+			}
+		} else if r, ok := fileResults[p]; ok {
+			results = r
+		} else {
+			if len(paths) == 1 {
+				root = p
+			}
+			results, err := identifier.IdentifyLicensesInDirectory(p, options, licenseLibrary)
+			if err != nil {
+				return err
+			}
+			all = append(all, results...)
+			if noticeFormat == "" {
+				if err := printDirectoryResults(cfg, licenseLibrary, p, results); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		all = append(all, results)
+		if noticeFormat == "" {
+			// Only label the output with the file name when there is more than one input
+			var label string
+			if len(paths) > 1 {
+				label = results.File
+				if label == "" {
+					label = p // e.g. a file that was too large to scan
+				}
+			}
+			if err := printFileResults(cfg, licenseLibrary, results, label); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := writeHTMLReport(cfg, root, all); err != nil {
+		return err
+	}
+
+	if noticeFormat != "" {
+		return writeNotice(cfg, root, all, licenseLibrary)
+	}
+	return nil
+}
+
+// writeMetrics writes a snapshot of the metrics to the --metrics file (or stdout)
+func writeMetrics(cfg *viper.Viper) error {
+	f := cfg.GetString(configurer.MetricsFlag)
+	if f == "" {
+		return nil
+	}
+	if f == stdoutPath {
+		return metrics.Default.WriteText(os.Stdout)
+	}
+	w, err := os.Create(f)
+	if err != nil {
+		return err
+	}
+	if err := metrics.Default.WriteText(w); err != nil {
+		_ = w.Close()
+		return err
+	}
+	return w.Close()
+}
+
+// resolvePackageURLs replaces the package URL (pkg:) paths with the package directories in the local mirror
+func resolvePackageURLs(cfg *viper.Viper, paths []string) ([]string, error) {
+	mirror := purl.Mirror{Root: cfg.GetString(configurer.MirrorPathFlag)}
+	resolved := make([]string, len(paths))
+	for i, p := range paths {
+		resolved[i] = p
+		if !strings.HasPrefix(p, purl.Scheme+":") {
+			continue
+		}
+		packageURL, err := purl.Parse(p)
+		if err != nil {
+			return nil, err
+		}
+		if resolved[i], err = mirror.Resolve(packageURL); err != nil {
+			return nil, err
+		}
+		ProjectLogger.Infof("Resolved %v: %v", packageURL, resolved[i])
+	}
+	return resolved, nil
+}
+
+// identifyLicensesInFiles scans the paths which are files (not directories or stdin) concurrently.
+// The results are mapped by path.
+func identifyLicensesInFiles(paths []string, options identifier.Options, licenseLibrary *licenses.LicenseLibrary) (map[string]identifier.IdentifierResults, error) {
+	var files []string
+	for _, p := range paths {
+		if p == stdinPath {
+			continue
+		}
+		fileInfo, err := os.Stat(p)
+		if err != nil {
+			return nil, err
+		}
+		if !fileInfo.IsDir() {
+			files = append(files, p)
+		}
+	}
+
+	results, err := identifier.IdentifyLicensesInFiles(files, options, licenseLibrary)
+	if err != nil {
+		return nil, err
+	}
+	ret := make(map[string]identifier.IdentifierResults, len(files))
+	for i, f := range files {
+		ret[f] = results[i]
+	}
+	return ret, nil
+}
+
+// readFilesFrom reads a list of paths from a file (or stdin for "-").
+// The paths are NUL separated (e.g. from find -print0) if the list contains a NUL, otherwise newline separated.
+func readFilesFrom(filesFrom string, in io.Reader) ([]string, error) {
+	var b []byte
+	var err error
+	if filesFrom == stdinPath {
+		b, err = io.ReadAll(in)
+	} else {
+		b, err = os.ReadFile(filesFrom)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	sep := "\n"
+	if bytes.IndexByte(b, 0) >= 0 {
+		sep = "\x00"
+	}
+	var paths []string
+	for _, p := range strings.Split(string(b), sep) {
+		if sep == "\n" {
+			p = strings.TrimRight(p, "\r")
+		}
+		if p != "" {
+			paths = append(paths, p)
+		}
+	}
+	return paths, nil
+}
+
+// identifyLicensesInReader identifies the licenses in text read from stdin (or another reader)
+func identifyLicensesInReader(in io.Reader, options identifier.Options, licenseLibrary *licenses.LicenseLibrary) (identifier.IdentifierResults, error) {
+	b, err := io.ReadAll(io.LimitReader(in, maxInputSize+1))
+	if err != nil {
+		return identifier.IdentifierResults{}, err
+	}
+	if len(b) > maxInputSize {
+		return identifier.IdentifierResults{}, fmt.Errorf("%v input too large (> %v)", stdinName, maxInputSize)
+	}
+
+	results, err := identifier.IdentifyLicensesInBytes(b, options, licenseLibrary)
+	results.File = stdinName
+	return results, err
+}
+
+// scanOptions sets the identifier enhancements from the flags
+func scanOptions(cfg *viper.Viper) identifier.Options {
+	options := identifier.Options{
+		ForceResult: true,
+		Enhancements: identifier.Enhancements{
+			AddNotes:       "",
+			AddTextBlocks:  true,
+			FlagAcceptable: cfg.GetBool(configurer.AcceptableFlag),
+			FlagCopyrights: cfg.GetBool(configurer.CopyrightsFlag),
+			FlagAuthors:    cfg.GetBool(configurer.AuthorsFlag),
+			FlagKeywords:   cfg.GetBool(configurer.KeywordsFlag),
+		},
+	}
+
+	if cfg.GetString(configurer.NoticeFlag) != "" {
+		options.Enhancements.FlagCopyrights = true
+	}
+	withReportEnhancements(cfg, &options)
+	return options
+}
+
+// printDirectoryResults prints the matches for each file, the copyright holders merged across the files,
+// and the declared licenses of each package compared with the detected licenses
+func printDirectoryResults(cfg *viper.Viper, licenseLibrary *licenses.LicenseLibrary, dir string, results []identifier.IdentifierResults) error {
+	for _, result := range results {
+		if len(result.Matches) > 0 {
+			fmt.Printf("\nFOUND LICENSE MATCHES: %v\n", result.File)
+			printMatches(result.Matches)
+
+			if ProjectLogger.GetLevel() >= log.INFO {
+				for _, block := range result.Blocks {
+					ProjectLogger.Infof("%v :: %v", block.Matches, block.Text)
+				}
+			}
+		} else {
+			fmt.Printf("\nNo licenses were found: %v\n", result.File)
+		}
+	}
+
+	if cfg.GetBool(configurer.CopyrightsFlag) {
+		printCopyrightHolders(identifier.MergeCopyrightHolders(results))
+	}
+	if cfg.GetBool(configurer.AuthorsFlag) {
+		for _, result := range results {
+			if len(result.Authors) > 0 {
+				fmt.Printf("\n%v\n", result.File)
+				printAuthors(result.Authors)
+			}
+		}
+	}
+	declaredLicense := cfg.GetString(configurer.DeclaredLicenseFlag)
+	if cfg.GetBool(configurer.DeclaredFlag) || declaredLicense != "" {
+		var supplied map[string]string
+		if declaredLicense != "" {
+			supplied = map[string]string{dir: declaredLicense}
+		}
+		packages, err := manifest.PackagesWithDeclared(results, supplied, licenseLibrary)
+		if err != nil {
+			return err
+		}
+		printPackages(packages)
+	}
+	return nil
+}
+
+// printFileResults prints the matches and enhancements found in one file.
+// The label (file name) is included in the output when not empty.
+func printFileResults(cfg *viper.Viper, licenseLibrary *licenses.LicenseLibrary, results identifier.IdentifierResults, label string) error {
+	licenseArg := cfg.GetString(configurer.LicenseFlag)
+	if len(results.Matches) > 0 {
+		if label != "" {
+			fmt.Printf("\nFOUND LICENSE MATCHES: %v\n", label)
+		} else {
+			fmt.Printf("\nFOUND LICENSE MATCHES:\n")
+		}
+		printMatches(results.Matches)
+
+		if licenseArg == "" {
+			for _, block := range results.Blocks {
+				ProjectLogger.Infof("%v :: %v", block.Matches, block.Text)
+			}
+		}
+	} else if label != "" {
+		fmt.Printf("\nNo licenses were found: %v\n", label)
+	} else {
+		ProjectLogger.Info("No licenses were found")
+	}
+
+	if cfg.GetBool(configurer.CopyrightsFlag) {
+		printCopyrights(results.Copyrights)
+	}
+	if cfg.GetBool(configurer.AuthorsFlag) {
+		printAuthors(results.Authors)
+	}
+	if cfg.GetBool(configurer.KeywordsFlag) {
+		printKeywords(results.Keywords)
+	}
+
+	if licenseArg != "" {
+		// If a license is also provided, debug against that license.
+		if err := explainLicense(os.Stdout, licenseLibrary, licenseArg, results); err != nil {
+			return err
+		}
+	}
+
+	if cfg.GetBool(configurer.HashFlag) {
+		ProjectLogger.Infof("File Hash: %v", results.Hash.Md5)
+	}
+	if cfg.GetBool(configurer.NormalizedFlag) {
+		ProjectLogger.Info("Normalized Text:")
+		ProjectLogger.Info(results.NormalizedText)
+	}
+	return nil
+}
+
+// printMatches prints the matches by license ID in alphabetical order
+func printMatches(matches map[string][]identifier.Match) {
+	var found []string
+	for id := range matches {
+		found = append(found, id)
+	}
+	sort.Strings(found)
+	for _, id := range found {
+		fmt.Printf("\tLicense ID:\t%v", id)
+		fmt.Println()
+		var prev identifier.Match
+		for _, m := range matches[id] {
+			// Print if not same as prev
+			if m != prev {
+				printRange(m.Begins, m.Ends, m.Start, m.End)
+				prev = m
+			}
+		}
+	}
+	fmt.Println()
+}
+
+// printRange prints the byte offsets (the ends is inclusive) and the line:column range (the end is exclusive) of a match
+func printRange(begins int, ends int, start identifier.Position, end identifier.Position) {
+	fmt.Printf("\t\tbegins: %5v\tends: %5v\tlines: %v-%v\n", begins, ends, start, end)
+}
+
+// withReportEnhancements enables the enhancements highlighted in the HTML report, when a report is requested
+func withReportEnhancements(cfg *viper.Viper, options *identifier.Options) {
+	if cfg.GetString(configurer.HTMLReportFlag) == "" {
+		return
+	}
+	options.Enhancements.FlagAcceptable = true
+	options.Enhancements.FlagCopyrights = true
+	options.Enhancements.FlagKeywords = true
+}
+
+// writeHTMLReport writes the HTML report file, if requested
+func writeHTMLReport(cfg *viper.Viper, root string, results []identifier.IdentifierResults) error {
+	reportPath := cfg.GetString(configurer.HTMLReportFlag)
+	if reportPath == "" {
+		return nil
+	}
+	reportFile, err := os.Create(reportPath)
+	if err != nil {
+		return err
+	}
+	defer reportFile.Close()
+
+	if err := report.New(results, report.Options{Root: root}).WriteHTML(reportFile); err != nil {
+		return err
+	}
+	ProjectLogger.Infof("Wrote HTML report: %v", reportPath)
+	return reportFile.Close()
+}
+
+// writeNotice writes a NOTICE for the scanned directory to stdout
+func writeNotice(cfg *viper.Viper, root string, results []identifier.IdentifierResults, licenseLibrary *licenses.LicenseLibrary) error {
+	options := notice.Options{
+		Root:         root,
+		Format:       cfg.GetString(configurer.NoticeFlag),
+		TemplatePath: cfg.GetString(configurer.NoticeTemplateFlag),
+	}
+	return notice.New(results, licenseLibrary, options).Render(os.Stdout, options)
+}
+
+func printCopyrights(copyrights []identifier.CopyrightStatement) {
+	if len(copyrights) == 0 {
+		return
+	}
+	fmt.Printf("FOUND COPYRIGHTS:\n")
+	for _, c := range copyrights {
+		fmt.Printf("\tHolder:\t%v\n", c.Holder)
+		fmt.Printf("\t\tyears: %v\temails: %v\tall rights reserved: %v\n", notice.Years(c.Years), strings.Join(c.Emails, ", "), y(c.AllRightsReserved))
+		printRange(c.Begins, c.Ends, c.Start, c.End)
+	}
+	fmt.Println()
+}
+
+func printAuthors(authors []identifier.Author) {
+	if len(authors) == 0 {
+		return
+	}
+	fmt.Printf("FOUND AUTHORS:\n")
+	for _, a := range authors {
+		fmt.Printf("\t%v:\t%v", a.Kind, a.Name)
+		if a.Email != "" {
+			fmt.Printf(" <%v>", a.Email)
+		}
+		fmt.Println()
+		printRange(a.Begins, a.Ends, a.Start, a.End)
+	}
+	fmt.Println()
+}
+
+func printKeywords(keywords []identifier.KeywordMatch) {
+	if len(keywords) == 0 {
+		return
+	}
+	fmt.Printf("FOUND KEYWORDS:\n")
+	for _, k := range keywords {
+		fmt.Printf("\t%v (%v):\t%v\n", k.Category, k.Severity, k.Text)
+		printRange(k.Begins, k.Ends, k.Start, k.End)
+	}
+	fmt.Println()
+}
+
+func printPackages(packages []manifest.Package) {
+	for _, p := range packages {
+		fmt.Printf("\nDECLARED AND DETECTED LICENSES: %v\n", p.Dir)
+		for _, d := range p.Declared {
+			if p.Supplied && d.File != "" {
+				continue // The supplied license is used instead of the manifests
+			}
+			if d.File == "" {
+				fmt.Printf("\tsupplied:\n")
+			} else {
+				fmt.Printf("\t%v:\t%v\t%v\n", d.Type, filepath.Base(d.File), strings.TrimSpace(d.Name+" "+d.Version))
+			}
+			fmt.Printf("\t\tdeclared:\t%v\n", strings.Join(d.Licenses, ", "))
+			if d.Expression != "" {
+				fmt.Printf("\t\texpression:\t%v\n", d.Expression)
+			}
+			if len(d.Unresolved) > 0 {
+				fmt.Printf("\t\tunresolved:\t%v\n", strings.Join(d.Unresolved, ", "))
+			}
+		}
+		detected := strings.Join(p.Detected, ", ")
+		if detected == "" {
+			detected = "(none)"
+		}
+		fmt.Printf("\tdetected:\t%v\n", detected)
+
+		c := p.Compare()
+		fmt.Printf("\toutcome:\t%v (%v)\n", c.Outcome, c.Reason)
+	}
+	fmt.Println()
+}
+
+func printCopyrightHolders(holders []identifier.CopyrightHolder) {
+	if len(holders) == 0 {
+		return
+	}
+	fmt.Printf("\nFOUND COPYRIGHT HOLDERS:\n")
+	for _, h := range holders {
+		fmt.Printf("\tHolder:\t%v\n", h.Holder)
+		fmt.Printf("\t\tyears: %v\temails: %v\tall rights reserved: %v\n", notice.Years(h.Years), strings.Join(h.Emails, ", "), y(h.AllRightsReserved))
+		for _, f := range h.Files {
+			fmt.Printf("\t\tfile: %v\n", f)
+		}
+	}
+	fmt.Println()
+}
@@ -0,0 +1,149 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/CycloneDX/license-scanner/configurer"
+	"github.com/CycloneDX/license-scanner/licenses"
+	"github.com/CycloneDX/license-scanner/purl"
+	"github.com/CycloneDX/license-scanner/server"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"google.golang.org/grpc"
+)
+
+// shutdownTimeout is how long to wait for the requests in progress when the service is stopped
+const shutdownTimeout = 30 * time.Second
+
+func newServeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Run the license scanning service with a REST API (and optionally a gRPC API)",
+		Long: `Run an HTTP service which scans license text, uploaded files, and archives using a shared license library.
+The license library is loaded once when the service starts. The service is ready (/readyz) when it is loaded.
+
+  GET  /healthz                   the service is running
+  GET  /readyz                    the license library is loaded
+  POST /v1/scan                   scan license text (text/plain) or scan specs (application/json)
+  POST /v1/scan/file              scan an uploaded file or archive (multipart/form-data "file")
+  GET  /v1/licenses               list the licenses and exceptions
+  POST /v1/expressions/validate   validate and normalize a license expression
+  GET  /metrics                   the metrics in the Prometheus text format
+
+Scan results are JSON, or a CycloneDX BOM with ?format=cyclonedx.
+
+With --grpcAddr, the licensescanner.v1.Scanner gRPC service (api/scannerpb) is also available,
+sharing the license library and limits of the REST API.`,
+		Example: `  license-scanner serve --addr :8080 --grpcAddr :9090
+  curl --data-binary @LICENSE http://localhost:8080/v1/scan
+  curl -F file=@package.tgz 'http://localhost:8080/v1/scan/file?format=cyclonedx'`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := initConfig(cmd)
+			if err != nil {
+				return err
+			}
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+			return serve(ctx, cfg)
+		},
+	}
+	configurer.AddServeFlags(cmd.Flags())
+	return cmd
+}
+
+// serve listens for HTTP (and gRPC) requests until the context is done, while the license library loads in the background
+func serve(ctx context.Context, cfg *viper.Viper) error {
+	s := server.New(server.Options{
+		MaxRequestBytes:    cfg.GetInt64(configurer.MaxRequestSizeFlag),
+		MaxConcurrentScans: cfg.GetInt(configurer.MaxScansFlag),
+		Mirror:             purl.Mirror{Root: cfg.GetString(configurer.MirrorPathFlag)},
+	})
+
+	listener, err := net.Listen("tcp", cfg.GetString(configurer.AddrFlag))
+	if err != nil {
+		return err
+	}
+	httpServer := &http.Server{
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	var grpcServer *grpc.Server
+	var grpcListener net.Listener
+	if grpcAddr := cfg.GetString(configurer.GRPCAddrFlag); grpcAddr != "" {
+		grpcListener, err = net.Listen("tcp", grpcAddr)
+		if err != nil {
+			_ = listener.Close()
+			return err
+		}
+		grpcServer = s.GRPCServer()
+	}
+
+	errs := make(chan error, 3)
+	go func() {
+		licenseLibrary, err := licenses.NewLicenseLibrary(cfg)
+		if err == nil {
+			err = licenseLibrary.AddAll()
+		}
+		if err != nil {
+			errs <- err
+			return
+		}
+		s.SetLicenseLibrary(licenseLibrary)
+		ProjectLogger.Infof("License library loaded (%v licenses)", len(licenseLibrary.LicenseMap))
+	}()
+	go func() {
+		ProjectLogger.Infof("Listening on %v", listener.Addr())
+		errs <- httpServer.Serve(listener)
+	}()
+	if grpcServer != nil {
+		go func() {
+			ProjectLogger.Infof("Listening for gRPC on %v", grpcListener.Addr())
+			errs <- grpcServer.Serve(grpcListener)
+		}()
+	}
+
+	select {
+	case err := <-errs:
+		_ = httpServer.Close()
+		if grpcServer != nil {
+			grpcServer.Stop()
+		}
+		return err
+	case <-ctx.Done():
+	}
+
+	ProjectLogger.Info("Shutting down")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	grpcStopped := make(chan struct{})
+	go func() {
+		if grpcServer != nil {
+			grpcServer.GracefulStop()
+		}
+		close(grpcStopped)
+	}()
+	if err := httpServer.Shutdown(shutdownCtx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	select {
+	case <-grpcStopped:
+	case <-shutdownCtx.Done():
+		// The gRPC requests in progress are canceled when they are not done in time
+		if grpcServer != nil {
+			grpcServer.Stop()
+		}
+	}
+	return nil
+}
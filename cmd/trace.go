@@ -0,0 +1,124 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/CycloneDX/license-scanner/configurer"
+	"github.com/CycloneDX/license-scanner/debugger"
+	"github.com/CycloneDX/license-scanner/licenses"
+	"github.com/CycloneDX/license-scanner/normalizer"
+
+	"github.com/spf13/cobra"
+)
+
+func newTraceCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "trace [<file>]",
+		Short: "Show how each normalization step changes the text of a file or license template",
+		Long: `Normalize the text of <file> (or the templates of the --license) and show how each step of the
+normalization changed the text. Use "-" as the file to read the text from stdin. Use --template to
+normalize the file as a license template.
+
+Each step shows its changes in context, as [-removed text-]{+added text+}, and a checksum of the index map
+(which maps the normalized text back to the original text). Newlines, carriage returns, and tabs are shown
+as ␊, ␍, and ␉. The steps are the configured normalization pipeline.`,
+		Example: `  license-scanner trace LICENSE.txt
+  license-scanner trace --template resources/spdx/default/template/MIT.template.txt
+  license-scanner trace --license MIT`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := initConfig(cmd)
+			if err != nil {
+				return err
+			}
+
+			id := cfg.GetString(configurer.LicenseFlag)
+			if (id == "") == (len(args) == 0) {
+				return errors.New("you must provide either a file or a --license")
+			}
+
+			licenseLibrary, err := licenses.NewLicenseLibrary(cfg)
+			if err != nil {
+				return err
+			}
+			if id != "" {
+				if err := licenseLibrary.AddAll(); err != nil {
+					return err
+				}
+				return traceLicense(cmd.OutOrStdout(), licenseLibrary, id)
+			}
+
+			text, err := readTraceInput(cmd.InOrStdin(), args[0])
+			if err != nil {
+				return err
+			}
+			var language *normalizer.Language
+			isTemplate := cfg.GetBool(configurer.TemplateFlag)
+			if !isTemplate && args[0] != stdinPath {
+				language = normalizer.DetectLanguage(args[0], text)
+			}
+			return traceText(cmd.OutOrStdout(), args[0], text, isTemplate, language, licenseLibrary.Pipeline)
+		},
+	}
+	configurer.AddTraceFlags(cmd.Flags())
+	return cmd
+}
+
+// readTraceInput reads the text of the file (or stdin)
+func readTraceInput(in io.Reader, path string) (string, error) {
+	if path == stdinPath {
+		b, err := io.ReadAll(io.LimitReader(in, maxInputSize+1))
+		if err != nil {
+			return "", err
+		}
+		if len(b) > maxInputSize {
+			return "", fmt.Errorf("%v input too large (> %v)", stdinName, maxInputSize)
+		}
+		return normalizer.Decode(b).Text, nil
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return normalizer.Decode(b).Text, nil
+}
+
+// traceLicense writes the normalization trace of each template (primary pattern) of the license
+func traceLicense(w io.Writer, licenseLibrary *licenses.LicenseLibrary, id string) error {
+	license, ok := licenseLibrary.LicenseMap[id]
+	if !ok {
+		return fmt.Errorf("license ID %v is not in the license library", id)
+	}
+	for i, pattern := range license.PrimaryPatterns {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		if err := traceText(w, pattern.FileName, pattern.Text, true, nil, pattern.Pipeline); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// traceText writes the normalization trace of the text
+func traceText(w io.Writer, name string, text string, isTemplate bool, language *normalizer.Language, pipeline *normalizer.Pipeline) error {
+	n, err := debugger.Trace(text, isTemplate, language, pipeline)
+	if err != nil {
+		return err
+	}
+	kind := "input text"
+	switch {
+	case isTemplate:
+		kind = "template"
+	case language != nil:
+		kind = language.Name + " source"
+	}
+	fmt.Fprintf(w, "Normalizing %v (%v)\n", name, kind)
+	debugger.WriteTrace(w, n)
+	return nil
+}
@@ -15,26 +15,51 @@ import (
 )
 
 const (
-	DefaultResource = "default"
-	AcceptableFlag  = "acceptable"
-	CopyrightsFlag  = "copyrights"
-	NormalizedFlag  = "normalized"
-	HashFlag        = "hash"
-	KeywordsFlag    = "keywords"
-	ListFlag        = "list"
-	AddAllFlag      = "addAll"
-	UpdateAllFlag   = "updateAll"
-	DebugFlag       = "debug"
-	QuietFlag       = "quiet"
-	LicenseFlag     = "license"
-	DirFlag         = "dir"
-	FileFlag        = "file"
-	ConfigPathFlag  = "configPath"
-	ConfigNameFlag  = "configName"
-	SpdxFlag        = "spdx"
-	SpdxPathFlag    = "spdxPath"
-	CustomFlag      = "custom"
-	CustomPathFlag  = "customPath"
+	DefaultResource     = "default"
+	AcceptableFlag      = "acceptable"
+	CopyrightsFlag      = "copyrights"
+	AuthorsFlag         = "authors"
+	DeclaredFlag        = "declared"
+	DeclaredLicenseFlag = "declaredLicense"
+	NormalizedFlag      = "normalized"
+	HashFlag            = "hash"
+	KeywordsFlag        = "keywords"
+	KeywordListFlag     = "keywordList"
+	KeywordsPathFlag    = "keywordsPath"
+	NoticeFlag          = "notice"
+	NoticeTemplateFlag  = "noticeTemplate"
+	HTMLReportFlag      = "htmlReport"
+	MetricsFlag         = "metrics"
+	MirrorPathFlag      = "mirrorPath"
+	AddrFlag            = "addr"
+	GRPCAddrFlag        = "grpcAddr"
+	MaxRequestSizeFlag  = "maxRequestSize"
+	MaxScansFlag        = "maxConcurrentScans"
+	ListFlag            = "list"
+	AddAllFlag          = "addAll"
+	UpdateAllFlag       = "updateAll"
+	DebugFlag           = "debug"
+	QuietFlag           = "quiet"
+	LicenseFlag         = "license"
+	TemplateFlag        = "template"
+	DirFlag             = "dir"
+	FileFlag            = "file"
+	FilesFromFlag       = "filesFrom"
+	ConfigPathFlag      = "configPath"
+	ConfigNameFlag      = "configName"
+	SpdxFlag            = "spdx"
+	SpdxPathFlag        = "spdxPath"
+	CustomFlag          = "custom"
+	CustomPathFlag      = "customPath"
+)
+
+// Config file settings of the normalization pipeline (see normalizer.ConfigurePipeline)
+const (
+	NormalizationStepsFlag       = "normalizationSteps"
+	NormalizationSkipFlag        = "normalizationSkip"
+	NormalizationRegexpStepsFlag = "normalizationRegexpSteps"
+	ReplacementWordsFlag         = "replacementWords"
+	ReplacementWordsPathFlag     = "replacementWordsPath"
 )
 
 var (
@@ -88,6 +113,9 @@ func InitConfig(flags *pflag.FlagSet) (*viper.Viper, error) {
 	// Make relative paths from config.* relative to the config file
 	relativeToConfig(SpdxPathFlag, flags, newViper)
 	relativeToConfig(CustomPathFlag, flags, newViper)
+	relativeToConfig(KeywordsPathFlag, flags, newViper)
+	relativeToConfig(ReplacementWordsPathFlag, flags, newViper)
+	relativeToConfig(MirrorPathFlag, flags, newViper)
 
 	// TODO: env from a file is W-I-P.
 	// Doc and test or just use config.env with above code and remove this.
@@ -121,30 +149,89 @@ func relativeToConfig(flag string, flags *pflag.FlagSet, newViper *viper.Viper)
 	}
 }
 
+// flagAliases are the other spellings of the flags (e.g. the --files-from of tools like tar and rsync)
+var flagAliases = map[string]string{
+	"files-from": FilesFromFlag,
+}
+
+// NormalizeFlagName is a pflag normalize func which accepts the aliases of the flags (see flagAliases)
+func NormalizeFlagName(_ *pflag.FlagSet, name string) pflag.NormalizedName {
+	if alias, ok := flagAliases[name]; ok {
+		name = alias
+	}
+	return pflag.NormalizedName(name)
+}
+
 func NewDefaultFlags() *pflag.FlagSet {
 	flagSet := pflag.NewFlagSet("default flagset for configurer", pflag.ContinueOnError)
+	flagSet.SetNormalizeFunc(NormalizeFlagName)
 	AddDefaultFlags(flagSet)
 	return flagSet
 }
 
+// AddDefaultFlags adds all the flags (config, scan, and the legacy mode flags) to the flag set
 func AddDefaultFlags(flagSet *pflag.FlagSet) {
+	AddConfigFlags(flagSet)
+	AddModeFlags(flagSet)
+	AddScanFlags(flagSet)
+}
+
+// AddConfigFlags adds the logging, config file, and resource flags used by every command
+func AddConfigFlags(flagSet *pflag.FlagSet) {
 	flagSet.BoolP(DebugFlag, "d", false, "Enable debug logging")
 	flagSet.BoolP(QuietFlag, "q", false, "Set logging to quiet")
+	flagSet.String(ConfigPathFlag, "", "Path to any config files")
+	flagSet.String(ConfigNameFlag, "config", "Base name for config file")
+	flagSet.String(SpdxFlag, DefaultResource, "Set of embedded SPDX templates to use")
+	flagSet.String(SpdxPathFlag, "", "Path to external SPDX templates to use")
+	flagSet.String(CustomFlag, DefaultResource, "Custom templates to use")
+	flagSet.String(CustomPathFlag, "", "Path to external custom templates to use")
+}
+
+// AddModeFlags adds the flags which select what the root command does (replaced by subcommands in the CLI)
+func AddModeFlags(flagSet *pflag.FlagSet) {
 	flagSet.String(DirFlag, "", "A directory in which to identify licenses")
 	flagSet.StringP(FileFlag, "f", "", "A file in which to identify licenses")
+	flagSet.Bool(ListFlag, false, "List the license templates to be used")
+	flagSet.String(AddAllFlag, "", "Add licenses from this dir to spdx, spdxPath, custom or customPath dir")
+	flagSet.Bool(UpdateAllFlag, false, "Update existing licenses")
+}
+
+// AddScanFlags adds the enhancement and output flags used when scanning files
+func AddScanFlags(flagSet *pflag.FlagSet) {
+	flagSet.String(FilesFromFlag, "", "Read more paths to scan from this file (newline or NUL separated, - for stdin)")
 	flagSet.BoolP(AcceptableFlag, "g", false, "Flag acceptable")
 	flagSet.BoolP(KeywordsFlag, "k", false, "Flag keywords")
+	flagSet.String(KeywordsPathFlag, "", "Path to a JSON file of keywords to flag (instead of the default keywords)")
 	flagSet.BoolP(CopyrightsFlag, "c", false, "Flag copyrights")
+	flagSet.BoolP(AuthorsFlag, "a", false, "Flag authors and attributions")
+	flagSet.Bool(DeclaredFlag, false, "Report the licenses declared in package manifests next to the detected licenses (in directory scans)")
+	flagSet.String(DeclaredLicenseFlag, "", "The declared license of the scanned directories, instead of their manifests (implies --declared)")
 	flagSet.BoolP(NormalizedFlag, "n", false, "Flag normalized")
 	flagSet.BoolP(HashFlag, "x", false, "Output file hash")
 	flagSet.StringP(LicenseFlag, "l", "", "Display match debugging for the given license")
-	flagSet.Bool(ListFlag, false, "List the license templates to be used")
-	flagSet.String(AddAllFlag, "", "Add licenses from this dir to spdx, spdxPath, custom or customPath dir")
-	flagSet.Bool(UpdateAllFlag, false, "Update existing licenses")
-	flagSet.String(ConfigPathFlag, "", "Path to any config files")
-	flagSet.String(ConfigNameFlag, "config", "Base name for config file")
-	flagSet.String(SpdxFlag, DefaultResource, "Set of embedded SPDX templates to use")
-	flagSet.String(SpdxPathFlag, "", "Path to external SPDX templates to use")
-	flagSet.String(CustomFlag, DefaultResource, "Custom templates to use")
-	flagSet.String(CustomPathFlag, "", "Path to external custom templates to use")
+	flagSet.String(NoticeFlag, "", "Output a NOTICE of the licenses and copyrights found (text, markdown, or html)")
+	flagSet.String(NoticeTemplateFlag, "", "Go template file to use for the NOTICE")
+	flagSet.String(HTMLReportFlag, "", "Write an HTML report with highlighted matches to this file")
+	flagSet.String(MetricsFlag, "", "Write a snapshot of the metrics (Prometheus text format) to this file after the scan (- for stdout)")
+	addMirrorPathFlag(flagSet)
+}
+
+// AddServeFlags adds the flags used by the scanning service
+func AddServeFlags(flagSet *pflag.FlagSet) {
+	flagSet.String(AddrFlag, ":8080", "Address on which to listen for HTTP requests")
+	flagSet.String(GRPCAddrFlag, "", "Address on which to listen for gRPC requests (e.g. :9090, default is no gRPC API)")
+	flagSet.Int64(MaxRequestSizeFlag, 10<<20, "Maximum size in bytes of a request body (license text, JSON, or uploaded file)")
+	flagSet.Int(MaxScansFlag, 0, "Maximum number of scans at the same time (default is the number of CPUs)")
+	addMirrorPathFlag(flagSet)
+}
+
+// AddTraceFlags adds the flags used to trace the normalization of a file or license template
+func AddTraceFlags(flagSet *pflag.FlagSet) {
+	flagSet.StringP(LicenseFlag, "l", "", "Trace the normalization of the templates of the given license (instead of a file)")
+	flagSet.Bool(TemplateFlag, false, "Normalize the file as a license template")
+}
+
+func addMirrorPathFlag(flagSet *pflag.FlagSet) {
+	flagSet.String(MirrorPathFlag, "", "Local mirror directory in which to find the packages of package URL (pkg:) paths")
 }
@@ -0,0 +1,391 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package debugger
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/CycloneDX/license-scanner/identifier"
+	"github.com/CycloneDX/license-scanner/licenses"
+	"github.com/CycloneDX/license-scanner/normalizer"
+)
+
+const (
+	// contextSize is the number of characters of text shown before and after a divergence
+	contextSize = 60
+	// expectedAtoms is the number of template words and tags shown as expected after a divergence
+	expectedAtoms = 12
+
+	beginOmitable = "<<omitable>>"
+	endOmitable   = "<</omitable>>"
+)
+
+// tagRE finds the tags (e.g. <<omitable>> or <<.{0,144}>>) of a normalized template
+var tagRE = regexp.MustCompile(`<<.*?>>`)
+
+// Explanation explains why a license did or did not match the input text
+type Explanation struct {
+	LicenseID string
+	// Matched is true when a primary pattern, alias, or URL of the license matched
+	Matched bool
+	// Patterns are the primary patterns (templates) of the license
+	Patterns []PatternExplanation
+	// Associated are the associated patterns, which are only checked when the license matched
+	Associated []PatternExplanation
+	// FallbacksTried is true when no primary pattern matched, so the aliases and URLs were tried
+	FallbacksTried bool
+	Aliases        []Fallback
+	URLs           []Fallback
+}
+
+// PatternExplanation explains the match of one pattern
+type PatternExplanation struct {
+	FileName string
+	// Prechecks are the static blocks which must be in the input before the pattern is tried
+	Prechecks []Precheck
+	// Matches of the pattern in the original text. The pattern is not tried when a precheck failed.
+	Matches []identifier.Match
+	// MatchedPrefix is the longest prefix of the normalized template which matches the input
+	MatchedPrefix string
+	// Divergence is where the input diverges from the template after the matched prefix (nil when the pattern
+	// matches, or when not even the beginning of the template is in the input)
+	Divergence *Divergence
+	// Error is set when the pattern can not be normalized or compiled
+	Error error
+}
+
+// Precheck is a static block of a pattern and whether the normalized input contains it
+type Precheck struct {
+	StaticBlock string
+	Passed      bool
+}
+
+// Divergence is the first point at which the input no longer matches the template
+type Divergence struct {
+	// Expected is the template text (with its tags) after the matched prefix
+	Expected string
+	// Found is the normalized input text at the divergence
+	Found string
+	// Offset is the position of the divergence in the original text, and Line and Column (from 1) are the same position
+	Offset int
+	Line   int
+	Column int
+	// Before and After are the original text around the divergence
+	Before string
+	After  string
+}
+
+// Fallback is an alias or URL of the license and where it was found (if at all)
+type Fallback struct {
+	Text  string
+	Found bool
+	Match identifier.Match
+}
+
+// PrechecksPassed is true when the input has every static block (so the pattern is tried)
+func (p PatternExplanation) PrechecksPassed() bool {
+	for _, c := range p.Prechecks {
+		if !c.Passed {
+			return false
+		}
+	}
+	return true
+}
+
+// Explain normalizes the input text and explains which prechecks, patterns, aliases, and URLs of the license match.
+// For each pattern that does not match, the longest matching prefix of the template is found, and the point at
+// which the input diverges from the template is mapped to the original text.
+// The language is the language of a source file (or nil), as used by the scan.
+func Explain(license licenses.License, licenseLibrary *licenses.LicenseLibrary, input string, language *normalizer.Language) (Explanation, error) {
+	nd := normalizer.NormalizationData{OriginalText: input, Language: language, Pipeline: licenseLibrary.Pipeline}
+	if err := nd.NormalizeText(); err != nil {
+		return Explanation{}, err
+	}
+
+	e := Explanation{LicenseID: license.SPDXLicenseID}
+	for _, pattern := range license.PrimaryPatterns {
+		p := explainPattern(pattern, licenseLibrary, &nd)
+		e.Matched = e.Matched || len(p.Matches) > 0
+		e.Patterns = append(e.Patterns, p)
+	}
+
+	if !e.Matched {
+		e.FallbacksTried = true
+		for _, alias := range license.Aliases {
+			m, found := identifier.FindAlias(alias, &nd)
+			e.Aliases = append(e.Aliases, Fallback{Text: alias, Found: found, Match: m})
+			e.Matched = e.Matched || found
+		}
+		for _, url := range license.URLs {
+			m, found := identifier.FindURL(url, &nd)
+			e.URLs = append(e.URLs, Fallback{Text: url, Found: found, Match: m})
+			e.Matched = e.Matched || found
+		}
+	}
+
+	for _, pattern := range license.AssociatedPatterns {
+		e.Associated = append(e.Associated, explainPattern(pattern, licenseLibrary, &nd))
+	}
+	e.addPositions(input)
+	return e, nil
+}
+
+// addPositions sets the line and column range of the matches
+func (e *Explanation) addPositions(input string) {
+	lines := identifier.NewLines(input)
+	for _, patterns := range [][]PatternExplanation{e.Patterns, e.Associated} {
+		for _, p := range patterns {
+			for i, m := range p.Matches {
+				p.Matches[i].Start, p.Matches[i].End = lines.Range(m.Begins, m.Ends)
+			}
+		}
+	}
+	for _, fallbacks := range [][]Fallback{e.Aliases, e.URLs} {
+		for i, f := range fallbacks {
+			if f.Found {
+				fallbacks[i].Match.Start, fallbacks[i].Match.End = lines.Range(f.Match.Begins, f.Match.Ends)
+			}
+		}
+	}
+}
+
+func explainPattern(pattern *licenses.PrimaryPatterns, licenseLibrary *licenses.LicenseLibrary, nd *normalizer.NormalizationData) PatternExplanation {
+	p := PatternExplanation{FileName: pattern.FileName}
+
+	if preChecks := licenseLibrary.PrimaryPatternPreCheckMap[licenses.LicensePatternKey{FilePath: pattern.FileName}]; preChecks != nil {
+		for _, block := range preChecks.StaticBlocks {
+			p.Prechecks = append(p.Prechecks, Precheck{StaticBlock: block, Passed: strings.Contains(nd.NormalizedText, block)})
+		}
+	}
+
+	if p.PrechecksPassed() {
+		p.Matches, p.Error = identifier.FindMatchingPatternInNormalizedData(pattern, *nd)
+		if p.Error != nil || len(p.Matches) > 0 {
+			return p
+		}
+	}
+
+	template := normalizer.NewNormalizationData(pattern.Text, true)
+	template.Pipeline = pattern.Pipeline
+	if p.Error = template.NormalizeText(); p.Error != nil {
+		return p
+	}
+	atoms := splitAtoms(template.NormalizedText)
+	n, end := longestMatchingPrefix(atoms, nd.NormalizedText)
+	if !hasWords(atoms[:n]) {
+		// Only optional text and wildcards match any text
+		n = 0
+	}
+	p.MatchedPrefix = strings.Join(atoms[:n], "")
+	if n > 0 && n < len(atoms) {
+		p.Divergence = divergence(atoms[n:], nd, end)
+	}
+	return p
+}
+
+// splitAtoms splits a normalized template into its tags and words (with their trailing spaces),
+// which are the points at which the template can be cut into a prefix
+func splitAtoms(template string) []string {
+	var atoms []string
+	words := func(s string) {
+		for _, w := range strings.SplitAfter(s, " ") {
+			if w != "" {
+				atoms = append(atoms, w)
+			}
+		}
+	}
+	prev := 0
+	for _, ii := range tagRE.FindAllStringIndex(template, -1) {
+		words(template[prev:ii[0]])
+		atoms = append(atoms, template[ii[0]:ii[1]])
+		prev = ii[1]
+	}
+	words(template[prev:])
+	return atoms
+}
+
+// hasWords is true when some atoms are required template words (not tags, spaces, or omitable text)
+func hasWords(atoms []string) bool {
+	omitable := 0
+	for _, a := range atoms {
+		switch {
+		case a == beginOmitable:
+			omitable++
+		case a == endOmitable:
+			omitable--
+		case omitable == 0 && strings.TrimSpace(a) != "" && !tagRE.MatchString(a):
+			return true
+		}
+	}
+	return false
+}
+
+// longestMatchingPrefix returns the number of atoms of the longest prefix of the template which matches the text,
+// and the end of its first match in the text. A longer prefix only matches where a shorter one does,
+// so the prefixes are binary searched.
+func longestMatchingPrefix(atoms []string, text string) (n int, end int) {
+	lo, hi := 0, len(atoms)
+	for lo < hi {
+		mid := (lo + hi + 1) / 2
+		if loc := matchPrefix(atoms[:mid], text); loc != nil {
+			lo, end = mid, loc[1]
+		} else {
+			hi = mid - 1
+		}
+	}
+	return lo, end
+}
+
+// matchPrefix returns the location of the first match of the template prefix (with its omitable sections closed)
+func matchPrefix(atoms []string, text string) []int {
+	open := 0
+	for _, a := range atoms {
+		switch a {
+		case beginOmitable:
+			open++
+		case endOmitable:
+			open--
+		}
+	}
+	prefix := strings.Join(atoms, "") + strings.Repeat(endOmitable, open)
+	re, err := licenses.GenerateRegexFromNormalizedText(strings.TrimSpace(prefix))
+	if err != nil {
+		return nil
+	}
+	return re.FindStringIndex(text)
+}
+
+// divergence describes the input at the end of the matched prefix, mapped to the original text
+func divergence(rest []string, nd *normalizer.NormalizationData, end int) *Divergence {
+	if len(rest) > expectedAtoms {
+		rest = rest[:expectedAtoms]
+	}
+	d := &Divergence{
+		Expected: strings.TrimSpace(strings.Join(rest, "")),
+		Found:    truncate(nd.NormalizedText[end:], contextSize),
+	}
+
+	switch {
+	case nd.IndexMap.Len() == 0:
+	case end < nd.IndexMap.Len():
+		d.Offset = nd.IndexMap.At(end)
+	default:
+		d.Offset = nd.IndexMap.At(nd.IndexMap.Len()-1) + 1
+	}
+	if d.Offset > len(nd.OriginalText) {
+		d.Offset = len(nd.OriginalText)
+	}
+
+	before := nd.OriginalText[:d.Offset]
+	d.Line = strings.Count(before, "\n") + 1
+	d.Column = utf8.RuneCountInString(before[strings.LastIndex(before, "\n")+1:]) + 1
+	d.Before = truncateLeft(before, contextSize)
+	d.After = truncate(nd.OriginalText[d.Offset:], contextSize)
+	return d
+}
+
+// truncate returns at most n characters from the beginning of s
+func truncate(s string, n int) string {
+	i := 0
+	for j := range s {
+		if i == n {
+			return s[:j]
+		}
+		i++
+	}
+	return s
+}
+
+// truncateLeft returns at most n characters from the end of s
+func truncateLeft(s string, n int) string {
+	end := len(s)
+	for i := 0; i < n && end > 0; i++ {
+		_, size := utf8.DecodeLastRuneInString(s[:end])
+		end -= size
+	}
+	return s[end:]
+}
+
+// WriteText writes the explanation as a readable report
+func (e Explanation) WriteText(w io.Writer) {
+	result := "did not match"
+	if e.Matched {
+		result = "matched"
+	}
+	fmt.Fprintf(w, "License %v %v\n", e.LicenseID, result)
+
+	for i, p := range e.Patterns {
+		fmt.Fprintf(w, "\nPattern %v: %v\n", i+1, p.FileName)
+		p.writeText(w)
+	}
+
+	if e.FallbacksTried {
+		fmt.Fprintf(w, "\nNo pattern matched, so the aliases and URLs were tried:\n")
+		writeFallbacks(w, "alias", e.Aliases)
+		writeFallbacks(w, "URL", e.URLs)
+	} else if len(e.Patterns) > 0 {
+		fmt.Fprintf(w, "\nA pattern matched, so the aliases and URLs were not tried\n")
+	}
+
+	if e.Matched {
+		for i, p := range e.Associated {
+			fmt.Fprintf(w, "\nAssociated pattern %v: %v\n", i+1, p.FileName)
+			p.writeText(w)
+		}
+	}
+}
+
+func (p PatternExplanation) writeText(w io.Writer) {
+	for _, c := range p.Prechecks {
+		fmt.Fprintf(w, "  precheck %v: %q\n", passed(c.Passed), c.StaticBlock)
+	}
+	switch {
+	case p.Error != nil:
+		fmt.Fprintf(w, "  error: %v\n", p.Error)
+	case len(p.Matches) > 0:
+		for _, m := range p.Matches {
+			fmt.Fprintf(w, "  matched: %v-%v (lines %v-%v)\n", m.Begins, m.Ends, m.Start, m.End)
+		}
+	default:
+		if !p.PrechecksPassed() {
+			fmt.Fprintf(w, "  not tried (a precheck failed)\n")
+		} else {
+			fmt.Fprintf(w, "  not matched\n")
+		}
+		if p.MatchedPrefix == "" {
+			fmt.Fprintf(w, "  the beginning of the template is not in the input\n")
+			return
+		}
+		fmt.Fprintf(w, "  longest matching prefix (%v characters): %q\n", len(p.MatchedPrefix), truncateLeft(strings.TrimSpace(p.MatchedPrefix), contextSize))
+		if d := p.Divergence; d != nil {
+			fmt.Fprintf(w, "  diverges at line %v, column %v (offset %v)\n", d.Line, d.Column, d.Offset)
+			fmt.Fprintf(w, "    expected: %q\n", d.Expected)
+			fmt.Fprintf(w, "    found:    %q\n", d.Found)
+			fmt.Fprintf(w, "    context:  %q >>HERE>> %q\n", d.Before, d.After)
+		}
+	}
+}
+
+func writeFallbacks(w io.Writer, kind string, fallbacks []Fallback) {
+	if len(fallbacks) == 0 {
+		fmt.Fprintf(w, "  no %v\n", kind)
+	}
+	for _, f := range fallbacks {
+		if f.Found {
+			fmt.Fprintf(w, "  %v found: %q at %v-%v (lines %v-%v)\n", kind, f.Text, f.Match.Begins, f.Match.Ends, f.Match.Start, f.Match.End)
+		} else {
+			fmt.Fprintf(w, "  %v not found: %q\n", kind, f.Text)
+		}
+	}
+}
+
+func passed(ok bool) string {
+	if ok {
+		return "passed"
+	}
+	return "failed"
+}
@@ -0,0 +1,119 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package debugger
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/CycloneDX/license-scanner/licenses"
+)
+
+const mitPrefix = "Permission is hereby granted, free of charge, to any person obtaining a copy\n" +
+	"of this software and associated documentation files (the \"Software\"), to deal\n"
+
+func TestExplain(t *testing.T) {
+	t.Parallel()
+	licenseLibrary, err := licenses.NewLicenseLibrary(nil)
+	if err != nil {
+		t.Fatalf("NewLicenseLibrary() error = %v", err)
+	}
+	if err := licenseLibrary.AddAll(); err != nil {
+		t.Fatalf("licenseLibrary.AddAll() error = %v", err)
+	}
+	mit := licenseLibrary.LicenseMap["MIT"]
+
+	t.Run("diverges", func(t *testing.T) {
+		input := mitPrefix + "in the Software with many restrictions, including limitation the rights\n"
+		e, err := Explain(mit, licenseLibrary, input, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if e.Matched || !e.FallbacksTried {
+			t.Errorf("Matched = %v, FallbacksTried = %v, want false and true", e.Matched, e.FallbacksTried)
+		}
+		if len(e.Patterns) == 0 {
+			t.Fatalf("expected the patterns of MIT")
+		}
+		p := e.Patterns[0]
+		if p.PrechecksPassed() {
+			t.Errorf("expected a failed precheck in %+v", p.Prechecks)
+		}
+		if !strings.Contains(p.MatchedPrefix, "to deal in the software") {
+			t.Errorf("MatchedPrefix = %q", p.MatchedPrefix)
+		}
+		if p.Divergence == nil {
+			t.Fatalf("expected a divergence")
+		}
+		d := *p.Divergence
+		if d.Line != 3 || !strings.HasPrefix(d.Expected, "without restriction") || !strings.Contains(d.After, "with many restrictions") {
+			t.Errorf("Divergence = %+v", d)
+		}
+		if !strings.HasPrefix(strings.TrimSpace(input[d.Offset:]), "with many restrictions") {
+			t.Errorf("Offset %v is not at the divergence: %q", d.Offset, input[d.Offset:])
+		}
+
+		var b bytes.Buffer
+		e.WriteText(&b)
+		for _, want := range []string{"License MIT did not match", "precheck failed", "diverges at line 3", `alias not found: "mit license"`} {
+			if !strings.Contains(b.String(), want) {
+				t.Errorf("expected %q in %v", want, b.String())
+			}
+		}
+	})
+
+	t.Run("alias", func(t *testing.T) {
+		e, err := Explain(mit, licenseLibrary, "This package is under the MIT License.", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !e.Matched || !e.FallbacksTried {
+			t.Errorf("Matched = %v, FallbacksTried = %v, want true", e.Matched, e.FallbacksTried)
+		}
+		var found []string
+		for _, a := range e.Aliases {
+			if a.Found {
+				found = append(found, a.Text)
+			}
+		}
+		if d := cmp.Diff([]string{"mit license"}, found); d != "" {
+			t.Errorf("aliases found (-want, +got): %v", d)
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		e, err := Explain(mit, licenseLibrary, "nothing to see here", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, p := range e.Patterns {
+			if p.Divergence != nil || len(p.Matches) > 0 {
+				t.Errorf("pattern %v = %+v, want no match or divergence", p.FileName, p)
+			}
+		}
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		if _, err := Explain(mit, licenseLibrary, "", nil); err == nil {
+			t.Errorf("expected an error for empty text")
+		}
+	})
+}
+
+func TestSplitAtoms(t *testing.T) {
+	t.Parallel()
+	template := "<<omitable>>mit license <</omitable>> <<.{0,1000}?>> permission is granted"
+	want := []string{"<<omitable>>", "mit ", "license ", "<</omitable>>", " ", "<<.{0,1000}?>>", " ", "permission ", "is ", "granted"}
+	got := splitAtoms(template)
+	if d := cmp.Diff(want, got); d != "" {
+		t.Errorf("splitAtoms() (-want, +got): %v", d)
+	}
+	if strings.Join(got, "") != template {
+		t.Errorf("the atoms do not join into the template")
+	}
+}
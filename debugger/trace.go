@@ -0,0 +1,254 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package debugger
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/CycloneDX/license-scanner/normalizer"
+)
+
+const (
+	// traceContext is the number of characters of unchanged text shown around a change
+	traceContext = 30
+	// maxChangeLength is the length after which changes close together are no longer joined
+	maxChangeLength = 200
+	// maxTraceChanges is the number of changes shown for each step
+	maxTraceChanges = 20
+	// maxDiffEdits limits the work to diff the text of a step. With more edits, the changed text is shown as one change.
+	maxDiffEdits = 1000
+)
+
+// tokenRE splits text into words, runs of whitespace, and single other characters, which are the units of a trace diff
+var tokenRE = regexp.MustCompile(`[\p{L}\p{N}_]+|\s+|.`)
+
+// visibleReplacer shows the whitespace which would otherwise break up the lines of a trace
+var visibleReplacer = strings.NewReplacer("\n", "␊", "\r", "␍", "\t", "␉")
+
+// Trace normalizes the text (as a template or input text in the language) with the pipeline,
+// recording the text after each step
+func Trace(text string, isTemplate bool, language *normalizer.Language, pipeline *normalizer.Pipeline) (*normalizer.NormalizationData, error) {
+	n := normalizer.NewNormalizationData(text, isTemplate)
+	n.Language = language
+	n.Pipeline = pipeline
+	n.Trace = true
+	if err := n.NormalizeText(); err != nil {
+		return nil, err
+	}
+	return n, nil
+}
+
+// WriteTrace writes how each step of the normalization changed the text, as changes in context:
+// [-removed text-]{+added text+}. Newlines, carriage returns, and tabs are shown as ␊, ␍, and ␉.
+func WriteTrace(w io.Writer, n *normalizer.NormalizationData) {
+	previous := n.OriginalText
+	for i, step := range n.TraceSteps {
+		changes := diffChanges(previous, step.NormalizedText)
+		fmt.Fprintf(w, "%2d. %v", i+1, step.Step)
+		switch {
+		case len(changes) > 0:
+			fmt.Fprintf(w, " (%v %v, index map %08x)\n", len(changes), plural(len(changes), "change"), step.IndexMapChecksum)
+		case i > 0 && step.IndexMapChecksum != n.TraceSteps[i-1].IndexMapChecksum:
+			fmt.Fprintf(w, " (text unchanged, index map %08x)\n", step.IndexMapChecksum)
+		default:
+			fmt.Fprintln(w, " (unchanged)")
+		}
+		for j, change := range changes {
+			if j == maxTraceChanges {
+				fmt.Fprintf(w, "      ... %v more\n", len(changes)-maxTraceChanges)
+				break
+			}
+			fmt.Fprintf(w, "      %v\n", change)
+		}
+		previous = step.NormalizedText
+	}
+	fmt.Fprintf(w, "\nNormalized text:\n%v\n", n.NormalizedText)
+}
+
+func plural(n int, s string) string {
+	if n == 1 {
+		return s
+	}
+	return s + "s"
+}
+
+// diffOp is an operation of a diff: keep, delete, or insert the text
+type diffOp struct {
+	op   byte
+	text string
+}
+
+const (
+	opKeep   = ' '
+	opDelete = '-'
+	opInsert = '+'
+)
+
+// diffChanges returns each change from the before to the after text (with some unchanged text for context)
+func diffChanges(before string, after string) []string {
+	ops := diffTokens(tokenRE.FindAllString(before, -1), tokenRE.FindAllString(after, -1))
+
+	// Changes which are close together are shown as one change (up to about maxChangeLength)
+	var changes []string
+	var change strings.Builder
+	for i := 0; i < len(ops); i++ {
+		op := ops[i]
+		switch {
+		case op.op != opKeep:
+			if change.Len() == 0 && i > 0 {
+				change.WriteString(visible(truncateLeft(ops[i-1].text, traceContext)))
+			}
+			if op.op == opDelete {
+				change.WriteString("[-" + visible(op.text) + "-]")
+			} else {
+				change.WriteString("{+" + visible(op.text) + "+}")
+			}
+		case change.Len() == 0:
+			// Unchanged text before the first change
+		case i+1 < len(ops) && len(op.text) <= 2*traceContext && change.Len() < maxChangeLength:
+			change.WriteString(visible(op.text))
+		default:
+			change.WriteString(visible(truncate(op.text, traceContext)))
+			changes = append(changes, change.String())
+			change.Reset()
+		}
+	}
+	if change.Len() > 0 {
+		changes = append(changes, change.String())
+	}
+	return changes
+}
+
+// visible shows the newlines, carriage returns, and tabs of the text
+func visible(s string) string {
+	return visibleReplacer.Replace(s)
+}
+
+// diffTokens returns the operations to change the tokens a to the tokens b, with consecutive tokens of the same
+// operation joined. The common prefix and suffix are kept, and the tokens between them are compared with
+// the Myers diff algorithm (or all replaced, when there are more than maxDiffEdits edits).
+func diffTokens(a []string, b []string) []diffOp {
+	var prefix, suffix int
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	var ops []diffOp
+	add := func(op byte, tokens ...string) {
+		text := strings.Join(tokens, "")
+		if text == "" {
+			return
+		}
+		if len(ops) > 0 && ops[len(ops)-1].op == op {
+			ops[len(ops)-1].text += text
+			return
+		}
+		// Show a deletion before an insertion
+		if op == opDelete && len(ops) > 0 && ops[len(ops)-1].op == opInsert {
+			last := ops[len(ops)-1]
+			if len(ops) > 1 && ops[len(ops)-2].op == opDelete {
+				ops[len(ops)-2].text += text
+				return
+			}
+			ops[len(ops)-1] = diffOp{op: op, text: text}
+			ops = append(ops, last)
+			return
+		}
+		ops = append(ops, diffOp{op: op, text: text})
+	}
+
+	add(opKeep, a[:prefix]...)
+	middleA, middleB := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	if edits, ok := myersDiff(middleA, middleB); ok {
+		for _, e := range edits {
+			add(e.op, e.text)
+		}
+	} else {
+		add(opDelete, middleA...)
+		add(opInsert, middleB...)
+	}
+	add(opKeep, a[len(a)-suffix:]...)
+	return ops
+}
+
+// myersDiff returns the operations (for each token) of the shortest edit from a to b,
+// or false when it has more than maxDiffEdits edits.
+func myersDiff(a []string, b []string) ([]diffOp, bool) {
+	n, m := len(a), len(b)
+	max := n + m
+	offset := max + 1
+	v := make([]int, 2*max+3)
+	// trace[d] is v (for the diagonals -d to d) after d edits
+	var trace [][]int
+	d := 0
+	for ; ; d++ {
+		if d > maxDiffEdits {
+			return nil, false
+		}
+		done := false
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				done = true
+			}
+		}
+		trace = append(trace, append([]int(nil), v[offset-d:offset+d+1]...))
+		if done {
+			break
+		}
+	}
+
+	// Backtrack from the end to find the edits
+	var reversed []diffOp
+	x, y := n, m
+	for ; d > 0; d-- {
+		previous := trace[d-1]
+		get := func(k int) int { return previous[k+d-1] }
+		k := x - y
+		var previousK int
+		if k == -d || (k != d && get(k-1) < get(k+1)) {
+			previousK = k + 1
+		} else {
+			previousK = k - 1
+		}
+		previousX := get(previousK)
+		previousY := previousX - previousK
+		for x > previousX && y > previousY {
+			reversed = append(reversed, diffOp{op: opKeep, text: a[x-1]})
+			x--
+			y--
+		}
+		if x == previousX {
+			reversed = append(reversed, diffOp{op: opInsert, text: b[previousY]})
+		} else {
+			reversed = append(reversed, diffOp{op: opDelete, text: a[previousX]})
+		}
+		x, y = previousX, previousY
+	}
+	for ; x > 0; x-- {
+		reversed = append(reversed, diffOp{op: opKeep, text: a[x-1]})
+	}
+
+	ops := make([]diffOp, len(reversed))
+	for i, op := range reversed {
+		ops[len(ops)-1-i] = op
+	}
+	return ops, true
+}
@@ -0,0 +1,121 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package debugger
+
+import (
+	"bytes"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/CycloneDX/license-scanner/normalizer"
+)
+
+func Test_diffTokens(t *testing.T) {
+	t.Parallel()
+	tcs := []struct {
+		name string
+		a    string
+		b    string
+		want []diffOp
+	}{
+		{
+			name: "same",
+			a:    "as is",
+			b:    "as is",
+			want: []diffOp{{opKeep, "as is"}},
+		},
+		{
+			name: "replaced word",
+			a:    "The Software -- as is",
+			b:    "The Software - as is",
+			want: []diffOp{{opKeep, "The Software -"}, {opDelete, "-"}, {opKeep, " as is"}},
+		},
+		{
+			name: "deletion before insertion",
+			a:    "free of Charge, to any",
+			b:    "free of charge,to any",
+			want: []diffOp{{opKeep, "free of "}, {opDelete, "Charge"}, {opInsert, "charge"}, {opKeep, ","}, {opDelete, " "}, {opKeep, "to any"}},
+		},
+		{
+			name: "empty",
+			a:    "",
+			b:    "mit",
+			want: []diffOp{{opInsert, "mit"}},
+		},
+	}
+	for _, tc := range tcs {
+		got := diffTokens(tokenRE.FindAllString(tc.a, -1), tokenRE.FindAllString(tc.b, -1))
+		if d := cmp.Diff(tc.want, got, cmp.AllowUnexported(diffOp{})); d != "" {
+			t.Errorf("%v: diffTokens() (-want, +got): %v", tc.name, d)
+		}
+	}
+}
+
+func Test_myersDiff(t *testing.T) {
+	t.Parallel()
+	words := []string{"a", "b", "c", " "}
+	random := rand.New(rand.NewSource(1))
+	randomTokens := func() []string {
+		tokens := make([]string, random.Intn(20))
+		for i := range tokens {
+			tokens[i] = words[random.Intn(len(words))]
+		}
+		return tokens
+	}
+	for i := 0; i < 1000; i++ {
+		a, b := randomTokens(), randomTokens()
+		ops, ok := myersDiff(a, b)
+		if !ok {
+			t.Fatalf("myersDiff(%q, %q) has too many edits", a, b)
+		}
+		var gotA, gotB []string
+		for _, op := range ops {
+			if op.op != opInsert {
+				gotA = append(gotA, op.text)
+			}
+			if op.op != opDelete {
+				gotB = append(gotB, op.text)
+			}
+		}
+		if strings.Join(gotA, "") != strings.Join(a, "") || strings.Join(gotB, "") != strings.Join(b, "") {
+			t.Fatalf("myersDiff(%q, %q) = %v", a, b, ops)
+		}
+	}
+}
+
+func TestWriteTrace(t *testing.T) {
+	t.Parallel()
+	n, err := Trace("The Software — is provided\n\"AS IS\".\n", false, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(n.TraceSteps) != len(normalizer.DefaultSteps)+1 {
+		t.Errorf("expected a trace step for the Unicode normalization and each default step, got %v", len(n.TraceSteps))
+	}
+
+	var b bytes.Buffer
+	WriteTrace(&b, n)
+	got := b.String()
+	for _, want := range []string{
+		" 1. normalizeUnicode (1 change, index map ",
+		"      [-The-]{+the+} [-Software-]{+software+} — is provided␊\"[-AS-]{+as+} [-IS-]{+is+}\".␊\n",
+		" 2. extractComments (unchanged)\n",
+		" 3. removeNoteTags (unchanged)\n",
+		"10. replaceDashLikeCharacters (1 change, index map ",
+		"      the software [-—-]{+-+} is provided␊\"as is\".␊\n",
+		"11. replaceQuoteLikeCharacters (1 change, index map ",
+		"      the software - is provided␊[-\"-]{+'+}as is[-\"-]{+'+}.␊\n",
+		"19. replaceWhitespace (1 change, index map ",
+		"      the software - is provided[-␊-]{+ +}'as is'.[-␊-]\n",
+		"\nNormalized text:\nthe software - is provided 'as is'.\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected output containing %q, got:\n%v", want, got)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package identifier
+
+import (
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Kinds of attribution statements found by FlagAuthors
+const (
+	AuthorKindWrittenBy   = "WRITTEN_BY"
+	AuthorKindAuthor      = "AUTHOR"
+	AuthorKindJavadoc     = "JAVADOC_AUTHOR"
+	AuthorKindContributor = "CONTRIBUTOR"
+	AuthorKindAuthorsFile = "AUTHORS_FILE"
+)
+
+const (
+	WrittenByPattern       = `(?im)^[^a-z0-9\n]*(?:(?:originally\s+)?(?:written|created|developed|maintained|contributed|authored|coded)\s+by)\s+([^\n\r]+)`
+	AuthorPattern          = `(?im)^[^a-z0-9@\n]*(?:authors?|original\s+authors?)\s*:\s*([^\n\r]+)`
+	JavadocAuthorPattern   = `(?i)@author\s+([^\n\r]+)`
+	ContributorsPattern    = `(?im)^[^a-z0-9\n]*(?:contributors?|credits|maintainers?|thanks\s+to)\s*:\s*([^\n\r]+)`
+	AuthorsFileLinePattern = `(?m)^[ \t]*([^#\s][^\n\r]*)`
+)
+
+type authorPattern struct {
+	kind string
+	re   *regexp.Regexp
+}
+
+// authorPatterns are applied in order, so the more specific statements are labeled first
+var authorPatterns = []authorPattern{
+	{AuthorKindJavadoc, regexp.MustCompile(JavadocAuthorPattern)},
+	{AuthorKindWrittenBy, regexp.MustCompile(WrittenByPattern)},
+	{AuthorKindAuthor, regexp.MustCompile(AuthorPattern)},
+	{AuthorKindContributor, regexp.MustCompile(ContributorsPattern)},
+}
+
+var (
+	AuthorsFileLineRegexp = regexp.MustCompile(AuthorsFileLinePattern)
+	authorsFileNameRegexp = regexp.MustCompile(`(?i)^(?:authors|contributors|credits|maintainers)(?:\.(?:txt|md|rst))?$`)
+	authorSeparatorRegexp = regexp.MustCompile(`\s*(?:[,;]|\s&\s|\sand\s)\s*`)
+)
+
+// Author is one attributed person (or organization) and the statement it was found in
+type Author struct {
+	Kind   string
+	Name   string
+	Email  string
+	Text   string
+	Begins int
+	Ends   int
+	Start  Position
+	End    Position
+}
+
+// IsAuthorsFile returns true for files such as AUTHORS, CONTRIBUTORS.md, or CREDITS.txt where every line is an attribution
+func IsAuthorsFile(filePath string) bool {
+	return authorsFileNameRegexp.MatchString(filepath.Base(filePath))
+}
+
+func flagAuthors(licenseResults *IdentifierResults, authorsFile bool) {
+	if licenseResults == nil {
+		return
+	}
+
+	patterns := authorPatterns
+	if authorsFile {
+		patterns = append(patterns[:len(patterns):len(patterns)], authorPattern{AuthorKindAuthorsFile, AuthorsFileLineRegexp})
+	}
+
+	for _, p := range patterns {
+		for _, pm := range identifyPatternInBlocks(licenseResults, p.re, "AUTHOR") {
+			licenseResults.AuthorStatements = append(licenseResults.AuthorStatements, pm)
+			licenseResults.Authors = append(licenseResults.Authors, parseAuthors(p.kind, p.re, pm)...)
+		}
+	}
+
+	// Report in the order found in the text rather than the order of the patterns
+	sort.SliceStable(licenseResults.AuthorStatements, func(i, j int) bool {
+		return licenseResults.AuthorStatements[i].Begins < licenseResults.AuthorStatements[j].Begins
+	})
+	sort.SliceStable(licenseResults.Authors, func(i, j int) bool {
+		return licenseResults.Authors[i].Begins < licenseResults.Authors[j].Begins
+	})
+}
+
+// parseAuthors splits the names in an attribution statement into one Author per name
+func parseAuthors(kind string, re *regexp.Regexp, pm PatternMatch) []Author {
+	submatch := re.FindStringSubmatch(pm.Text)
+	if len(submatch) < 2 {
+		return nil
+	}
+	names := strings.TrimSpace(submatch[1])
+
+	// Javadoc and AUTHORS file lines name one author, but statements may list several
+	var parts []string
+	if kind == AuthorKindJavadoc || kind == AuthorKindAuthorsFile {
+		parts = []string{names}
+	} else {
+		parts = authorSeparatorRegexp.Split(names, -1)
+	}
+
+	var authors []Author
+	for _, part := range parts {
+		var email string
+		if e := CopyrightEmailRegexp.FindString(part); e != "" {
+			email = strings.Trim(e, "<>")
+			part = CopyrightEmailRegexp.ReplaceAllString(part, " ")
+		}
+		name := cleanHolder(part)
+		if name == "" && email == "" {
+			continue
+		}
+		authors = append(authors, Author{
+			Kind:   kind,
+			Name:   name,
+			Email:  email,
+			Text:   pm.Text,
+			Begins: pm.Begins,
+			Ends:   pm.Ends,
+		})
+	}
+	return authors
+}
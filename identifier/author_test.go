@@ -0,0 +1,76 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package identifier
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_flagAuthors(t *testing.T) {
+	tests := []struct {
+		name        string
+		text        string
+		authorsFile bool
+		want        []Author
+	}{
+		{
+			name: "should classify javadoc, written by, author, and contributors statements",
+			text: "/**\n * @author Jane Doe <jane@example.com>\n */\n# Written by John Smith and Ann Lee\nAuthor: Bob\nContributors: Carol, Dave",
+			want: []Author{
+				{Kind: AuthorKindJavadoc, Name: "Jane Doe", Email: "jane@example.com", Text: "@author Jane Doe <jane@example.com>", Begins: 7, Ends: 41},
+				{Kind: AuthorKindWrittenBy, Name: "John Smith", Text: "# Written by John Smith and Ann Lee", Begins: 47, Ends: 81},
+				{Kind: AuthorKindWrittenBy, Name: "Ann Lee", Text: "# Written by John Smith and Ann Lee", Begins: 47, Ends: 81},
+				{Kind: AuthorKindAuthor, Name: "Bob", Text: "Author: Bob", Begins: 83, Ends: 93},
+				{Kind: AuthorKindContributor, Name: "Carol", Text: "Contributors: Carol, Dave", Begins: 95, Ends: 119},
+				{Kind: AuthorKindContributor, Name: "Dave", Text: "Contributors: Carol, Dave", Begins: 95, Ends: 119},
+			},
+		},
+		{
+			name:        "should treat every line of an AUTHORS file as an author",
+			text:        "# Names should be added to this file as\n#   Name <email address>\nJane Doe <jane@example.com>\nExample Corp\n",
+			authorsFile: true,
+			want: []Author{
+				{Kind: AuthorKindAuthorsFile, Name: "Jane Doe", Email: "jane@example.com", Text: "Jane Doe <jane@example.com>", Begins: 65, Ends: 91},
+				{Kind: AuthorKindAuthorsFile, Name: "Example Corp", Text: "Example Corp", Begins: 93, Ends: 104},
+			},
+		},
+		{
+			name: "should not find authors in plain text",
+			text: "Permission is hereby granted, free of charge, to any person obtaining a copy",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			licenseResults := &IdentifierResults{Blocks: []Block{{Text: tt.text}}}
+			flagAuthors(licenseResults, tt.authorsFile)
+			if diff := cmp.Diff(tt.want, licenseResults.Authors); diff != "" {
+				t.Errorf("flagAuthors() (-want, +got): %+v", diff)
+			}
+			for _, a := range licenseResults.Authors {
+				if got := tt.text[a.Begins : a.Ends+1]; got != a.Text {
+					t.Errorf("Author offsets do not match the text: %q != %q", got, a.Text)
+				}
+			}
+		})
+	}
+}
+
+func TestIsAuthorsFile(t *testing.T) {
+	for path, want := range map[string]bool{
+		"vendor/foo/AUTHORS":      true,
+		"CONTRIBUTORS.md":         true,
+		"credits.txt":             true,
+		"vendor/foo/LICENSE":      false,
+		"src/authors.go":          false,
+		"docs/AUTHORS/README.txt": false,
+	} {
+		if got := IsAuthorsFile(path); got != want {
+			t.Errorf("IsAuthorsFile(%v) = %v, want %v", path, got, want)
+		}
+	}
+}
@@ -0,0 +1,258 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package identifier
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	CopyrightTokenPattern     = `(?i)(?:copyright|copr\.?|\(c\)|\x{00A9})`
+	AllRightsReservedPattern  = `(?i)all\s+rights\s+reserved\.?`
+	CopyrightEmailPattern     = `<?[a-zA-Z0-9._%+-]+@[a-zA-Z0-9-]+(?:\.[a-zA-Z0-9-]+)+>?`
+	CopyrightYearRangePattern = `(?i)\b((?:19|20)\d{2})(?:\s*(?:-|\x{2013}|\x{2014}|to)\s*((?:19|20)?\d{2}|present|now)\b)?`
+)
+
+var (
+	CopyrightTokenRegexp     = regexp.MustCompile(CopyrightTokenPattern)
+	AllRightsReservedRegexp  = regexp.MustCompile(AllRightsReservedPattern)
+	CopyrightEmailRegexp     = regexp.MustCompile(CopyrightEmailPattern)
+	CopyrightYearRangeRegexp = regexp.MustCompile(CopyrightYearRangePattern)
+
+	// holderPrefixRegexp trims leading punctuation, comment markers, and "by" from what remains of a holder
+	holderPrefixRegexp = regexp.MustCompile(`(?i)^[^a-z0-9]*(?:by\s+)?`)
+	// holderTheRegexp is used to compare "the X authors" and "X authors" as the same holder
+	holderTheRegexp = regexp.MustCompile(`(?i)^the\s+`)
+	// continuationRegexp recognizes statements which continue on the next line (e.g. "Copyright 2019, 2020,")
+	continuationRegexp = regexp.MustCompile(`(?i)(?:,|\band|&)\s*$`)
+	whitespaceRegexp   = regexp.MustCompile(`\s+`)
+)
+
+// YearRange is an inclusive range of years. Single years have Start == End.
+type YearRange struct {
+	Start int
+	End   int
+	// Ongoing is set for a range which is still active (e.g. 2015-present), which includes any year after End
+	Ongoing bool
+}
+
+// CopyrightStatement holds the structured data parsed from a copyright statement
+type CopyrightStatement struct {
+	// Text is the original text of the statement (including any continuation line)
+	Text   string
+	Begins int
+	Ends   int
+	Start  Position
+	End    Position
+	// Holder is the copyright holder as written (without years, emails, and symbols)
+	Holder            string
+	Years             []YearRange
+	Emails            []string
+	AllRightsReserved bool
+}
+
+// CopyrightHolder merges the copyright statements for one holder across blocks and files
+type CopyrightHolder struct {
+	Holder            string
+	Years             []YearRange
+	Emails            []string
+	AllRightsReserved bool
+	Files             []string
+	Statements        []string
+}
+
+// parseCopyrights converts the COPYRIGHT pattern matches into structured statements
+func parseCopyrights(licenseResults *IdentifierResults) {
+	if licenseResults == nil {
+		return
+	}
+	var statements []CopyrightStatement
+	for _, pm := range licenseResults.CopyRightStatements {
+		text := pm.Text
+		ends := pm.Ends
+
+		// A statement with no holder (or a dangling separator) continues on the next line
+		s := ParseCopyrightStatement(text)
+		if s.Holder == "" || continuationRegexp.MatchString(text) {
+			if next, nextEnds, ok := nextLine(licenseResults.OriginalText, ends+1); ok {
+				text = text + "\n" + next
+				ends = nextEnds
+				s = ParseCopyrightStatement(text)
+			}
+		}
+
+		s.Begins = pm.Begins
+		s.Ends = ends
+		statements = append(statements, s)
+	}
+	licenseResults.Copyrights = statements
+}
+
+// nextLine returns the line following offset (skipping the line break) unless it is blank or another copyright
+func nextLine(text string, offset int) (line string, ends int, ok bool) {
+	if offset >= len(text) {
+		return "", -1, false
+	}
+	// Move past the rest of the current line and the line break
+	nl := strings.IndexByte(text[offset:], '\n')
+	if nl < 0 {
+		return "", -1, false
+	}
+	begins := offset + nl + 1
+	line = text[begins:]
+	if end := strings.IndexByte(line, '\n'); end >= 0 {
+		line = line[:end]
+	}
+	line = strings.TrimRight(line, "\r")
+	if strings.TrimSpace(line) == "" || CopyrightTokenRegexp.MatchString(line) {
+		return "", -1, false
+	}
+	return line, begins + len(line) - 1, true
+}
+
+// ParseCopyrightStatement extracts the holder, years, emails, and "All rights reserved" from a copyright statement
+func ParseCopyrightStatement(text string) CopyrightStatement {
+	s := CopyrightStatement{Text: text}
+	rest := text
+
+	if AllRightsReservedRegexp.MatchString(rest) {
+		s.AllRightsReserved = true
+		rest = AllRightsReservedRegexp.ReplaceAllString(rest, " ")
+	}
+
+	for _, email := range CopyrightEmailRegexp.FindAllString(rest, -1) {
+		s.Emails = appendUnique(s.Emails, strings.Trim(email, "<>"))
+	}
+	rest = CopyrightEmailRegexp.ReplaceAllString(rest, " ")
+
+	rest = CopyrightTokenRegexp.ReplaceAllString(rest, " ")
+
+	for _, m := range CopyrightYearRangeRegexp.FindAllStringSubmatch(rest, -1) {
+		s.Years = append(s.Years, parseYearRange(m[1], m[2]))
+	}
+	s.Years = mergeYearRanges(s.Years)
+	rest = CopyrightYearRangeRegexp.ReplaceAllString(rest, " ")
+
+	s.Holder = cleanHolder(rest)
+	return s
+}
+
+func parseYearRange(start string, end string) YearRange {
+	s, _ := strconv.Atoi(start)
+	r := YearRange{Start: s, End: s}
+	switch strings.ToLower(end) {
+	case "":
+		return r
+	case "present", "now":
+		r.Ongoing = true
+		return r
+	}
+	e, _ := strconv.Atoi(end)
+	if len(end) == 2 {
+		// Abbreviated end year (e.g. 2015-17) uses the century of the start year
+		e += s - s%100
+	}
+	if e >= s {
+		r.End = e
+	}
+	return r
+}
+
+// cleanHolder removes separators and punctuation left over from the parsed statement
+func cleanHolder(rest string) string {
+	rest = whitespaceRegexp.ReplaceAllString(rest, " ")
+	rest = holderPrefixRegexp.ReplaceAllString(rest, "")
+	for {
+		trimmed := strings.TrimSpace(strings.TrimRight(strings.TrimSpace(rest), ",.;:-*/#"))
+		trimmed = strings.TrimSuffix(trimmed, " and")
+		if trimmed == rest {
+			break
+		}
+		rest = trimmed
+	}
+	// Empty parens or brackets left behind by removed emails or years
+	rest = strings.NewReplacer("()", "", "<>", "", "[]", "").Replace(rest)
+	return strings.TrimSpace(whitespaceRegexp.ReplaceAllString(rest, " "))
+}
+
+// holderKey is used to compare holders, so "The Go Authors" and "the Go authors." are the same holder
+func holderKey(holder string) string {
+	return holderTheRegexp.ReplaceAllString(strings.TrimRight(strings.ToLower(holder), "."), "")
+}
+
+// MergeCopyrightHolders deduplicates the copyright holders found in the results of one or more files
+func MergeCopyrightHolders(results []IdentifierResults) []CopyrightHolder {
+	byKey := make(map[string]*CopyrightHolder)
+	var keys []string
+	for _, result := range results {
+		for _, statement := range result.Copyrights {
+			if statement.Holder == "" {
+				continue
+			}
+			key := holderKey(statement.Holder)
+			h, ok := byKey[key]
+			if !ok {
+				h = &CopyrightHolder{Holder: statement.Holder}
+				byKey[key] = h
+				keys = append(keys, key)
+			}
+			h.Years = mergeYearRanges(append(h.Years, statement.Years...))
+			for _, email := range statement.Emails {
+				h.Emails = appendUnique(h.Emails, email)
+			}
+			h.AllRightsReserved = h.AllRightsReserved || statement.AllRightsReserved
+			if result.File != "" {
+				h.Files = appendUnique(h.Files, result.File)
+			}
+			h.Statements = appendUnique(h.Statements, statement.Text)
+		}
+	}
+
+	sort.Strings(keys)
+	var holders []CopyrightHolder
+	for _, key := range keys {
+		holders = append(holders, *byKey[key])
+	}
+	return holders
+}
+
+// mergeYearRanges sorts the ranges and combines any which overlap or are adjacent (or follow an ongoing range)
+func mergeYearRanges(years []YearRange) []YearRange {
+	if len(years) < 2 {
+		return years
+	}
+	sorted := make([]YearRange, len(years))
+	copy(sorted, years)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Start != sorted[j].Start {
+			return sorted[i].Start < sorted[j].Start
+		}
+		return sorted[i].End < sorted[j].End
+	})
+
+	merged := []YearRange{sorted[0]}
+	for _, r := range sorted[1:] {
+		last := &merged[len(merged)-1]
+		if last.Ongoing || r.Start <= last.End+1 {
+			if r.End > last.End {
+				last.End = r.End
+			}
+			last.Ongoing = last.Ongoing || r.Ongoing
+			continue
+		}
+		merged = append(merged, r)
+	}
+	return merged
+}
+
+func appendUnique(ss []string, s string) []string {
+	for i := range ss {
+		if ss[i] == s {
+			return ss
+		}
+	}
+	return append(ss, s)
+}
@@ -0,0 +1,132 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package identifier
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseCopyrightStatement(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want CopyrightStatement
+	}{
+		{
+			name: "simple holder and year",
+			text: "Copyright (c) 2017 James Tanner",
+			want: CopyrightStatement{Holder: "James Tanner", Years: []YearRange{{Start: 2017, End: 2017}}},
+		},
+		{
+			name: "year range, list, email and all rights reserved",
+			text: "// Copyright © 2010-2012, 2014, 2015-17 Jane Doe <jane@example.com>. All rights reserved.",
+			want: CopyrightStatement{
+				Holder:            "Jane Doe",
+				Years:             []YearRange{{Start: 2010, End: 2012}, {Start: 2014, End: 2017}},
+				Emails:            []string{"jane@example.com"},
+				AllRightsReserved: true,
+			},
+		},
+		{
+			name: "the X authors phrasing",
+			text: "Copyright 2009 The Go Authors.",
+			want: CopyrightStatement{Holder: "The Go Authors", Years: []YearRange{{Start: 2009, End: 2009}}},
+		},
+		{
+			name: "present and by",
+			text: "Copyright (C) 2019 - present by Example Corp",
+			want: CopyrightStatement{Holder: "Example Corp", Years: []YearRange{{Start: 2019, End: 2019, Ongoing: true}}},
+		},
+		{
+			name: "no years",
+			text: "Copyright IBM Corp.",
+			want: CopyrightStatement{Holder: "IBM Corp"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			tt.want.Text = tt.text
+			got := ParseCopyrightStatement(tt.text)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("ParseCopyrightStatement() (-want, +got): %+v", diff)
+			}
+		})
+	}
+}
+
+func Test_mergeYearRanges(t *testing.T) {
+	years := []YearRange{{Start: 2018, End: 2018}, {Start: 2015, End: 2015, Ongoing: true}, {Start: 2010, End: 2012}, {Start: 2013, End: 2013}}
+	want := []YearRange{{Start: 2010, End: 2013}, {Start: 2015, End: 2018, Ongoing: true}}
+	if diff := cmp.Diff(want, mergeYearRanges(years)); diff != "" {
+		t.Errorf("mergeYearRanges() (-want, +got): %+v", diff)
+	}
+}
+
+func Test_parseCopyrights(t *testing.T) {
+	text := "/*\n * Copyright (c) 2017, 2018,\n *   James Tanner\n * Copyright (c) 2019 IBM\n */"
+	licenseResults := &IdentifierResults{
+		OriginalText: text,
+		Blocks:       []Block{{Text: text}},
+	}
+	flagCopyrights(licenseResults)
+	parseCopyrights(licenseResults)
+
+	want := []CopyrightStatement{
+		{
+			Text:   " * Copyright (c) 2017, 2018,\n *   James Tanner",
+			Begins: 3, Ends: 48,
+			Holder: "James Tanner",
+			Years:  []YearRange{{Start: 2017, End: 2018}},
+		},
+		{
+			Text:   " * Copyright (c) 2019 IBM",
+			Begins: 50, Ends: 74,
+			Holder: "IBM",
+			Years:  []YearRange{{Start: 2019, End: 2019}},
+		},
+	}
+	if diff := cmp.Diff(want, licenseResults.Copyrights); diff != "" {
+		t.Errorf("parseCopyrights() (-want, +got): %+v", diff)
+	}
+}
+
+func TestMergeCopyrightHolders(t *testing.T) {
+	results := []IdentifierResults{
+		{
+			File: "a/LICENSE",
+			Copyrights: []CopyrightStatement{
+				{Text: "Copyright 2009 The Go Authors.", Holder: "The Go Authors", Years: []YearRange{{Start: 2009, End: 2009}}},
+			},
+		},
+		{
+			File: "b/LICENSE",
+			Copyrights: []CopyrightStatement{
+				{Text: "Copyright 2010-2012 the Go authors", Holder: "the Go authors", Years: []YearRange{{Start: 2010, End: 2012}}, AllRightsReserved: true},
+				{Text: "Copyright Jane Doe <jane@example.com>", Holder: "Jane Doe", Emails: []string{"jane@example.com"}},
+			},
+		},
+	}
+	want := []CopyrightHolder{
+		{
+			Holder:            "The Go Authors",
+			Years:             []YearRange{{Start: 2009, End: 2012}},
+			AllRightsReserved: true,
+			Files:             []string{"a/LICENSE", "b/LICENSE"},
+			Statements:        []string{"Copyright 2009 The Go Authors.", "Copyright 2010-2012 the Go authors"},
+		},
+		{
+			Holder:     "Jane Doe",
+			Emails:     []string{"jane@example.com"},
+			Files:      []string{"b/LICENSE"},
+			Statements: []string{"Copyright Jane Doe <jane@example.com>"},
+		},
+	}
+	if diff := cmp.Diff(want, MergeCopyrightHolders(results)); diff != "" {
+		t.Errorf("MergeCopyrightHolders() (-want, +got): %+v", diff)
+	}
+}
@@ -4,7 +4,7 @@ package identifier
 
 import (
 	"regexp"
-	"strings"
+	"sync"
 
 	"github.com/CycloneDX/license-scanner/licenses"
 )
@@ -14,8 +14,11 @@ type Enhancements struct {
 	AddTextBlocks  bool
 	FlagAcceptable bool
 	FlagCopyrights bool
+	FlagAuthors    bool
 	FlagKeywords   bool
 	// FlagKeywords   []string  // TODO: JavaScript used this as a bool and later as a list
+	// AuthorsFile treats every line as an attribution (e.g. for AUTHORS or CONTRIBUTORS files) when FlagAuthors is set
+	AuthorsFile bool
 }
 
 const AlphaNumericPattern = `/[a-zA-Z0-9]+/`
@@ -30,12 +33,40 @@ var (
 	AlphaNumericRegexp = regexp.MustCompile(AlphaNumericPattern)
 )
 
-var DefaultKeywordList = []string{
-	`public domain`,
-	`Affero`,
-	`[a-z]*gpl`,
-	`[a-z-]*commercial[a-z]*`,
-	`[a-z-]*licen[cs][a-z]*`,
+// DefaultKeywords are flagged when no keywords are configured for the license library
+var DefaultKeywords = []licenses.Keyword{
+	{Pattern: `public domain`, Category: "public-domain"},
+	{Pattern: `Affero`, Category: "copyleft"},
+	{Pattern: `[a-z]*gpl`, Category: "copyleft"},
+	{Pattern: `[a-z-]*commercial[a-z]*`, Category: "commercial"},
+	{Pattern: `[a-z-]*licen[cs][a-z]*`, Category: "license"},
+}
+
+// DefaultKeywordList is the list of DefaultKeywords patterns
+var DefaultKeywordList = func() []string {
+	var list []string
+	for _, k := range DefaultKeywords {
+		list = append(list, k.Pattern)
+	}
+	return list
+}()
+
+var (
+	defaultKeywordsOnce     sync.Once
+	defaultKeywordsCompiled *licenses.Keywords
+	defaultKeywordsErr      error
+)
+
+// KeywordMatch is a flagged keyword with the category and severity of the keyword that matched
+type KeywordMatch struct {
+	Text     string
+	Begins   int
+	Ends     int
+	Start    Position
+	End      Position
+	Keyword  string
+	Category string
+	Severity string
 }
 
 // FromOptions adds enabled enhancements (in order)
@@ -46,12 +77,16 @@ func FromOptions(licenseResults *IdentifierResults, enhancements Enhancements, l
 
 	if enhancements.FlagCopyrights {
 		flagCopyrights(licenseResults)
+		parseCopyrights(licenseResults)
+	}
+	if enhancements.FlagAuthors {
+		flagAuthors(licenseResults, enhancements.AuthorsFile)
 	}
 	if enhancements.FlagAcceptable {
 		flagAcceptable(licenseResults, licenseLibrary)
 	}
 	if enhancements.FlagKeywords {
-		if err := flagKeywords(licenseResults); err != nil {
+		if err := flagKeywords(licenseResults, licenseLibrary); err != nil {
 			return err
 		}
 	}
@@ -83,21 +118,39 @@ func flagAcceptable(licenseResults *IdentifierResults, licenseLibrary *licenses.
 	}
 }
 
-func flagKeywords(licenseResults *IdentifierResults) error {
+func flagKeywords(licenseResults *IdentifierResults, licenseLibrary *licenses.LicenseLibrary) error {
 	if licenseResults == nil {
 		return nil
 	}
-	keywords := DefaultKeywordList
 
-	re, err := regexp.Compile(`(?i)\b` + strings.Join(keywords, "|"+`\b`))
+	keywords, err := keywordsFor(licenseLibrary)
 	if err != nil {
 		return err
 	}
-	licenseResults.KeywordMatches = identifyPatternInBlocks(licenseResults, re, "KEYWORD")
+
+	licenseResults.KeywordMatches = identifyPatternInBlocks(licenseResults, keywords.Regexp, "KEYWORD")
+	for _, pm := range licenseResults.KeywordMatches {
+		km := KeywordMatch{Text: pm.Text, Begins: pm.Begins, Ends: pm.Ends}
+		if k, ok := keywords.Classify(pm.Text); ok {
+			km.Keyword, km.Category, km.Severity = k.Pattern, k.Category, k.Severity
+		}
+		licenseResults.Keywords = append(licenseResults.Keywords, km)
+	}
 
 	return nil
 }
 
+// keywordsFor returns the keywords configured in the license library, or the DefaultKeywords (compiled once)
+func keywordsFor(licenseLibrary *licenses.LicenseLibrary) (*licenses.Keywords, error) {
+	if licenseLibrary != nil && licenseLibrary.Keywords != nil {
+		return licenseLibrary.Keywords, nil
+	}
+	defaultKeywordsOnce.Do(func() {
+		defaultKeywordsCompiled, defaultKeywordsErr = licenses.NewKeywords(DefaultKeywords)
+	})
+	return defaultKeywordsCompiled, defaultKeywordsErr
+}
+
 func flagEmptyBlocks(licenseResults *IdentifierResults) {
 	if licenseResults == nil || len(licenseResults.Blocks) == 0 {
 		return
@@ -84,6 +84,7 @@ func TestFromOptions(t *testing.T) {
 			want: &IdentifierResults{
 				Blocks:         []Block{{Text: "This is a "}, {Text: "xxxlicensxxx", Matches: []string{"KEYWORD"}}, {Text: " test"}},
 				KeywordMatches: []PatternMatch{{Text: "xxxlicensxxx", Begins: 10, Ends: 21}},
+				Keywords:       []KeywordMatch{{Text: "xxxlicensxxx", Begins: 10, Ends: 21, Keyword: `[a-z-]*licen[cs][a-z]*`, Category: "license", Severity: "info"}},
 			},
 			wantErr: false,
 		},
@@ -102,6 +103,7 @@ func TestFromOptions(t *testing.T) {
 			want: &IdentifierResults{
 				Blocks:         []Block{{Text: "This is a "}, {Text: "xxxlicensxxx", Matches: []string{"KEYWORD"}}, {Text: " test"}},
 				KeywordMatches: []PatternMatch{{Text: "xxxlicensxxx", Begins: 10, Ends: 21}},
+				Keywords:       []KeywordMatch{{Text: "xxxlicensxxx", Begins: 10, Ends: 21, Keyword: `[a-z-]*licen[cs][a-z]*`, Category: "license", Severity: "info"}},
 				Notes:          "A different test note",
 			},
 			wantErr: false,
@@ -306,6 +308,7 @@ func Test_flagKeywords(t *testing.T) {
 			want: &IdentifierResults{
 				Blocks:         []Block{{Text: "This is a "}, {Text: "xxxlicensxxx", Matches: []string{"KEYWORD"}}, {Text: " test"}},
 				KeywordMatches: []PatternMatch{{Text: "xxxlicensxxx", Begins: 10, Ends: 21}},
+				Keywords:       []KeywordMatch{{Text: "xxxlicensxxx", Begins: 10, Ends: 21, Keyword: `[a-z-]*licen[cs][a-z]*`, Category: "license", Severity: "info"}},
 			},
 			wantErr: false,
 		},
@@ -330,13 +333,44 @@ func Test_flagKeywords(t *testing.T) {
 					{Text: "public domain", Begins: 11, Ends: 23},
 					{Text: "license", Begins: 25, Ends: 31},
 				},
+				Keywords: []KeywordMatch{
+					{Text: "public domain", Begins: 11, Ends: 23, Keyword: `public domain`, Category: "public-domain", Severity: "info"},
+					{Text: "license", Begins: 25, Ends: 31, Keyword: `[a-z-]*licen[cs][a-z]*`, Category: "license", Severity: "info"},
+				},
+			},
+		},
+		{
+			name: "should flag the keywords configured in the license library",
+			args: args{
+				licenseResults: &IdentifierResults{
+					Blocks: []Block{{
+						Text: "Confidential: do not distribute this license.",
+					}},
+				},
+				licenseLibrary: customKeywordsLibrary(t),
+			},
+			want: &IdentifierResults{
+				Blocks: []Block{
+					{Text: "Confidential", Matches: []string{"KEYWORD"}},
+					{Text: ": "},
+					{Text: "do not distribute", Matches: []string{"KEYWORD"}},
+					{Text: " this license."},
+				},
+				KeywordMatches: []PatternMatch{
+					{Text: "Confidential", Begins: 0, Ends: 11},
+					{Text: "do not distribute", Begins: 14, Ends: 30},
+				},
+				Keywords: []KeywordMatch{
+					{Text: "Confidential", Begins: 0, Ends: 11, Keyword: "confidential", Category: "proprietary", Severity: "high"},
+					{Text: "do not distribute", Begins: 14, Ends: 30, Keyword: `do not (?:re)?distribute`, Category: "distribution", Severity: "info"},
+				},
 			},
 		},
 	}
 	for _, tt := range tests {
 		tt := tt
 		t.Run(tt.name, func(t *testing.T) {
-			if err := flagKeywords(tt.args.licenseResults); (err != nil) != tt.wantErr {
+			if err := flagKeywords(tt.args.licenseResults, tt.args.licenseLibrary); (err != nil) != tt.wantErr {
 				t.Errorf("flagKeywords() error = %v, wantErr %v", err, tt.wantErr)
 			}
 			if tt.want == nil {
@@ -350,6 +384,22 @@ func Test_flagKeywords(t *testing.T) {
 	}
 }
 
+func customKeywordsLibrary(t *testing.T) *licenses.LicenseLibrary {
+	t.Helper()
+	ll, err := licenses.NewLicenseLibrary(nil)
+	if err != nil {
+		t.Fatalf("NewLicenseLibrary() error = %v", err)
+	}
+	ll.Keywords, err = licenses.NewKeywords([]licenses.Keyword{
+		{Pattern: "confidential", Category: "proprietary", Severity: "high"},
+		{Pattern: `do not (?:re)?distribute`, Category: "distribution"},
+	})
+	if err != nil {
+		t.Fatalf("NewKeywords() error = %v", err)
+	}
+	return ll
+}
+
 func Test_identifyPatternInBlocks(t *testing.T) {
 	type args struct {
 		licenseResults *IdentifierResults
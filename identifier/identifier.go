@@ -12,24 +12,40 @@ import (
 	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/CycloneDX/sbom-utility/log"
 	"golang.org/x/exp/slices"
 	"golang.org/x/sync/errgroup"
 
 	"github.com/CycloneDX/license-scanner/licenses"
+	"github.com/CycloneDX/license-scanner/metrics"
 	"github.com/CycloneDX/license-scanner/normalizer"
 )
 
 var (
 	Logger     = log.NewLogger(log.INFO)
 	nonAlphaRE = regexp.MustCompile(`^[^A-Za-z0-9]*$`)
+
+	filesScanned = metrics.NewCounter("license_scanner_files_scanned_total",
+		"Files scanned for licenses, by result (scanned, too_large, or error).", "result")
+	identifySeconds = metrics.NewHistogram("license_scanner_identify_duration_seconds",
+		"Time to identify the licenses in a normalized text.", metrics.DefBuckets)
+	licenseSeconds = metrics.NewHistogram("license_scanner_license_evaluation_seconds",
+		"Time to evaluate the patterns, aliases, and URLs of one license against a normalized text.",
+		metrics.ExponentialBuckets(.00001, 4, 10), "license")
+	prechecks = metrics.NewCounter("license_scanner_prechecks_total",
+		"Static block prechecks of license patterns, by result (pass or fail).", "result")
+	precheckPassed = prechecks.With("pass")
+	precheckFailed = prechecks.With("fail")
 )
 
 type Options struct {
 	ForceResult  bool
 	OmitBlocks   bool
 	Enhancements Enhancements
+	// Language of the source file (see normalizer.DetectLanguage), whose comments are matched instead of all the text
+	Language *normalizer.Language
 }
 
 type licenseMatch struct {
@@ -37,28 +53,43 @@ type licenseMatch struct {
 	Match     Match
 }
 
+// Match is the range of a match in the original text. Begins and Ends are byte offsets, and Ends is inclusive.
+// Start and End are the line and column of the first rune and after the last rune (End is exclusive).
 type Match struct {
 	Begins int
 	Ends   int
+	Start  Position
+	End    Position
 }
 
 type PatternMatch struct {
 	Text   string
 	Begins int
 	Ends   int
+	Start  Position
+	End    Position
 }
 
 type IdentifierResults struct {
-	Matches                  map[string][]Match
-	Blocks                   []Block
-	File                     string
-	OriginalText             string
-	NormalizedText           string
+	Matches        map[string][]Match
+	Blocks         []Block
+	File           string
+	OriginalText   string
+	NormalizedText string
+	// Encoding of the original bytes (when identified from bytes). The original text is decoded to UTF-8,
+	// but the offsets of the matches are in the original bytes.
+	Encoding string
+	// Language of a source file, when only its comments were matched
+	Language                 string
 	Hash                     normalizer.Digest
 	Notes                    string
 	AcceptablePatternMatches []PatternMatch
 	KeywordMatches           []PatternMatch
+	Keywords                 []KeywordMatch
 	CopyRightStatements      []PatternMatch
+	Copyrights               []CopyrightStatement
+	AuthorStatements         []PatternMatch
+	Authors                  []Author
 }
 
 type Block struct {
@@ -67,6 +98,8 @@ type Block struct {
 }
 
 func Identify(options Options, licenseLibrary *licenses.LicenseLibrary, normalizedData normalizer.NormalizationData) (IdentifierResults, error) {
+	defer identifySeconds.ObserveSince(time.Now())
+
 	// find the licenses in the normalized text and return a list of SPDX IDs
 	// in case of an error, return as much as we have along with an error
 	licenseResults, err := findAllLicensesInNormalizedData(licenseLibrary, normalizedData)
@@ -82,6 +115,8 @@ func Identify(options Options, licenseLibrary *licenses.LicenseLibrary, normaliz
 		return IdentifierResults{}, err
 	}
 
+	licenseResults.addPositions(normalizedData.OriginalText)
+
 	if options.OmitBlocks {
 		licenseResults.Blocks = []Block{}
 	}
@@ -93,6 +128,8 @@ func IdentifyLicensesInString(input string, options Options, licenseLibrary *lic
 	// instantiate normalizedData with the input license text
 	normalizedData := normalizer.NormalizationData{
 		OriginalText: input,
+		Language:     options.Language,
+		Pipeline:     licenseLibrary.Pipeline,
 	}
 
 	// normalize the input license text
@@ -100,7 +137,53 @@ func IdentifyLicensesInString(input string, options Options, licenseLibrary *lic
 		return IdentifierResults{}, err
 	}
 
-	return Identify(options, licenseLibrary, normalizedData)
+	results, err := Identify(options, licenseLibrary, normalizedData)
+	if err == nil && options.Language != nil {
+		results.Language = options.Language.Name
+	}
+	return results, err
+}
+
+// IdentifyLicensesInBytes decodes the bytes (e.g. UTF-16 or windows-1252) to UTF-8 and identifies the licenses.
+// The offsets of the results are in the original bytes.
+func IdentifyLicensesInBytes(b []byte, options Options, licenseLibrary *licenses.LicenseLibrary) (IdentifierResults, error) {
+	return identifyLicensesInDecoded(normalizer.Decode(b), options, licenseLibrary)
+}
+
+// identifyLicensesInDecoded identifies the licenses in the decoded text, with the offsets in the original bytes
+func identifyLicensesInDecoded(decoded normalizer.DecodedText, options Options, licenseLibrary *licenses.LicenseLibrary) (IdentifierResults, error) {
+	result, err := IdentifyLicensesInString(decoded.Text, options, licenseLibrary)
+	if err != nil {
+		return result, err
+	}
+	result.Encoding = decoded.Encoding
+	if decoded.Offsets != nil {
+		result.mapOffsets(decoded)
+	}
+	return result, nil
+}
+
+// mapOffsets maps the offsets in the decoded text to the offsets in the original bytes
+func (r *IdentifierResults) mapOffsets(decoded normalizer.DecodedText) {
+	for _, matches := range r.Matches {
+		for i := range matches {
+			matches[i].Begins, matches[i].Ends = decoded.Begin(matches[i].Begins), decoded.End(matches[i].Ends)
+		}
+	}
+	for _, pms := range [][]PatternMatch{r.AcceptablePatternMatches, r.KeywordMatches, r.CopyRightStatements, r.AuthorStatements} {
+		for i := range pms {
+			pms[i].Begins, pms[i].Ends = decoded.Begin(pms[i].Begins), decoded.End(pms[i].Ends)
+		}
+	}
+	for i := range r.Keywords {
+		r.Keywords[i].Begins, r.Keywords[i].Ends = decoded.Begin(r.Keywords[i].Begins), decoded.End(r.Keywords[i].Ends)
+	}
+	for i := range r.Copyrights {
+		r.Copyrights[i].Begins, r.Copyrights[i].Ends = decoded.Begin(r.Copyrights[i].Begins), decoded.End(r.Copyrights[i].Ends)
+	}
+	for i := range r.Authors {
+		r.Authors[i].Begins, r.Authors[i].Ends = decoded.Begin(r.Authors[i].Begins), decoded.End(r.Authors[i].Ends)
+	}
 }
 
 func IdentifyLicensesInFile(filePath string, options Options, licenseLibrary *licenses.LicenseLibrary) (IdentifierResults, error) {
@@ -110,21 +193,36 @@ func IdentifyLicensesInFile(filePath string, options Options, licenseLibrary *li
 	}
 	if fi.Size() > 1000000 {
 		Logger.Errorf("file too large (%v > 1000000)", fi.Size()) // log error, but return nil
+		filesScanned.With("too_large").Inc()
 		return IdentifierResults{}, nil
 	}
 
 	b, err := ioutil.ReadFile(filePath)
 	if err != nil {
+		filesScanned.With("error").Inc()
 		return IdentifierResults{}, err
 	}
-	input := string(b)
 
-	result, err := IdentifyLicensesInString(input, options, licenseLibrary)
+	if options.Enhancements.FlagAuthors && IsAuthorsFile(filePath) {
+		options.Enhancements.AuthorsFile = true
+	}
+	// The language is detected in the decoded text (e.g. the #! line of a UTF-16 script)
+	decoded := normalizer.Decode(b)
+	if options.Language == nil {
+		options.Language = normalizer.DetectLanguage(filePath, decoded.Text)
+	}
+
+	result, err := identifyLicensesInDecoded(decoded, options, licenseLibrary)
+	if err != nil {
+		filesScanned.With("error").Inc()
+	} else {
+		filesScanned.With("scanned").Inc()
+	}
 	result.File = filePath
 	return result, err
 }
 
-func IdentifyLicensesInDirectory(dirPath string, options Options, licenseLibrary *licenses.LicenseLibrary) (ret []IdentifierResults, err error) {
+func IdentifyLicensesInDirectory(dirPath string, options Options, licenseLibrary *licenses.LicenseLibrary) ([]IdentifierResults, error) {
 	var lfs []string
 
 	if err := filepath.WalkDir(dirPath, func(path string, d fs.DirEntry, err error) error {
@@ -144,44 +242,43 @@ func IdentifyLicensesInDirectory(dirPath string, options Options, licenseLibrary
 		return nil, err
 	}
 
+	return IdentifyLicensesInFiles(lfs, options, licenseLibrary)
+}
+
+// RelativePath returns the path of the file (e.g. in the results of a directory scan) relative to the root
+// directory, with forward slashes. The path is not changed (except for the slashes) when the root is empty.
+func RelativePath(root string, file string) string {
+	if root != "" {
+		if rel, err := filepath.Rel(root, file); err == nil {
+			return filepath.ToSlash(rel)
+		}
+	}
+	return filepath.ToSlash(file)
+}
+
+// IdentifyLicensesInFiles identifies the licenses in each file in parallel (using one license library).
+// The results are in the same order as the file paths.
+func IdentifyLicensesInFiles(filePaths []string, options Options, licenseLibrary *licenses.LicenseLibrary) ([]IdentifierResults, error) {
+	ret := make([]IdentifierResults, len(filePaths))
+
 	// errGroup to do the work in parallel until error
 	workers := errgroup.Group{}
 	workers.SetLimit(10)
-	ch := make(chan IdentifierResults, 10)
 
-	// WaitGroup to know when we have all the results
-	waitForResults := sync.WaitGroup{}
-	waitForResults.Add(1)
-
-	// Start receiving the results until channel closes
-	go func() {
-		for ir := range ch {
-			ret = append(ret, ir)
-		}
-		waitForResults.Done()
-	}()
-
-	// Loop using a worker to send results to a channel
-	for _, lf := range lfs {
-		lf := lf
+	// Each worker sets the results at its own index
+	for i, fp := range filePaths {
+		i, fp := i, fp
 		workers.Go(func() error {
-			ir, err := IdentifyLicensesInFile(lf, options, licenseLibrary)
-			if err == nil {
-				ch <- ir
-			}
+			ir, err := IdentifyLicensesInFile(fp, options, licenseLibrary)
+			ret[i] = ir
 			return err
 		})
 	}
 
-	// Close the channel when done or error
-	go func() {
-		err = workers.Wait()
-		close(ch)
-	}()
-
-	// Make sure we got all the results
-	waitForResults.Wait()
-	return ret, err
+	if err := workers.Wait(); err != nil {
+		return nil, err
+	}
+	return ret, nil
 }
 
 func findAllLicensesInNormalizedData(licenseLibrary *licenses.LicenseLibrary, normalizedData normalizer.NormalizationData) (IdentifierResults, error) {
@@ -198,7 +295,9 @@ func findAllLicensesInNormalizedData(licenseLibrary *licenses.LicenseLibrary, no
 	var licensesMatched []licenseMatch
 
 	for id, lic := range licenseLibrary.LicenseMap {
+		start := time.Now()
 		matches, err := findLicenseInNormalizedData(lic, normalizedData, licenseLibrary)
+		licenseSeconds.With(id).ObserveSince(start)
 		if err != nil {
 			return ret, err
 		}
@@ -241,12 +340,12 @@ func findLicenseInNormalizedData(lic licenses.License, normalizedData normalizer
 
 	// If we don't already have a more interesting match, then see if there is an alias hit
 	if len(licenseMatches) == 0 {
-		licenseMatches = findAnyAlias(lic.Aliases, normalizedData, licenseMatches)
+		licenseMatches = findAnyAlias(lic.Aliases, &normalizedData, licenseMatches)
 	}
 
 	// If we don't already have a more interesting match, then see if there is a URL hit
 	if len(licenseMatches) == 0 {
-		licenseMatches = findAnyURL(lic.URLs, normalizedData, licenseMatches)
+		licenseMatches = findAnyURL(lic.URLs, &normalizedData, licenseMatches)
 	}
 
 	// If there were no results, return null.
@@ -259,7 +358,7 @@ func findLicenseInNormalizedData(lic licenses.License, normalizedData normalizer
 }
 
 // findAny finds one matching string which meets word boundary conditions (and url conditions)
-func findAny(ss []string, normalized normalizer.NormalizationData, isURL bool, licenseMatches []Match) []Match {
+func findAny(ss []string, normalized *normalizer.NormalizationData, isURL bool, licenseMatches []Match) []Match {
 	for _, s := range ss {
 		next := 0
 		for i := strings.Index(normalized.NormalizedText, s); i > -1; i = strings.Index(normalized.NormalizedText[next:], s) {
@@ -275,7 +374,7 @@ func findAny(ss []string, normalized normalizer.NormalizationData, isURL bool, l
 	return licenseMatches
 }
 
-func findBoundaries(start int, s string, nd normalizer.NormalizationData, isURL bool) (begin int, end int, ok bool) {
+func findBoundaries(start int, s string, nd *normalizer.NormalizationData, isURL bool) (begin int, end int, ok bool) {
 	begin, ok = findBeginBoundary(start, nd, isURL)
 	if !ok {
 		return -1, -1, false
@@ -289,7 +388,7 @@ func findBoundaries(start int, s string, nd normalizer.NormalizationData, isURL
 	return begin, end, true
 }
 
-func findBeginBoundary(start int, nd normalizer.NormalizationData, isURL bool) (begin int, ok bool) {
+func findBeginBoundary(start int, nd *normalizer.NormalizationData, isURL bool) (begin int, ok bool) {
 	// Starting at position zero is always an ok boundary
 	if start == 0 {
 		return 0, true
@@ -328,7 +427,7 @@ func findBeginBoundary(start int, nd normalizer.NormalizationData, isURL bool) (
 	return begin, true // Space-paren word boundary
 }
 
-func findEndBoundary(start int, s string, nd normalizer.NormalizationData, isURL bool) (end int, ok bool) {
+func findEndBoundary(start int, s string, nd *normalizer.NormalizationData, isURL bool) (end int, ok bool) {
 	end = start + len(s)
 	max := len(nd.NormalizedText)
 
@@ -372,7 +471,7 @@ func findEndBoundary(start int, s string, nd normalizer.NormalizationData, isURL
 	return end, true // found an ok boundary
 }
 
-func includeURLPrefix(begin int, nd normalizer.NormalizationData) int {
+func includeURLPrefix(begin int, nd *normalizer.NormalizationData) int {
 	wwwDot := "www."
 	length := len(wwwDot)
 	if begin >= length && wwwDot == nd.NormalizedText[begin-length:begin] {
@@ -389,24 +488,44 @@ func includeURLPrefix(begin int, nd normalizer.NormalizationData) int {
 	return begin
 }
 
-func appendIndexMappedMatch(begin int, end int, normalizedData normalizer.NormalizationData, licenseMatches []Match) []Match {
-	indexMapLen := len(normalizedData.IndexMap)
+func appendIndexMappedMatch(begin int, end int, normalizedData *normalizer.NormalizationData, licenseMatches []Match) []Match {
+	indexMapLen := normalizedData.IndexMap.Len()
 	if end < indexMapLen {
-		return append(licenseMatches, Match{Begins: normalizedData.IndexMap[begin], Ends: normalizedData.IndexMap[end]})
+		return append(licenseMatches, Match{Begins: normalizedData.IndexMap.At(begin), Ends: normalizedData.IndexMap.At(end)})
 	} else {
 		// End of map is out of range, so use the last index in the map
-		return append(licenseMatches, Match{Begins: normalizedData.IndexMap[begin], Ends: normalizedData.IndexMap[indexMapLen-1]})
+		return append(licenseMatches, Match{Begins: normalizedData.IndexMap.At(begin), Ends: normalizedData.IndexMap.At(indexMapLen - 1)})
 	}
 }
 
-func findAnyAlias(urls []string, normalized normalizer.NormalizationData, licenseMatches []Match) []Match {
+func findAnyAlias(urls []string, normalized *normalizer.NormalizationData, licenseMatches []Match) []Match {
 	return findAny(urls, normalized, false, licenseMatches)
 }
 
-func findAnyURL(urls []string, normalized normalizer.NormalizationData, licenseMatches []Match) []Match {
+func findAnyURL(urls []string, normalized *normalizer.NormalizationData, licenseMatches []Match) []Match {
 	return findAny(urls, normalized, true, licenseMatches)
 }
 
+// FindAlias finds the alias (a license name or ID) with word boundaries in the normalized text.
+// The aliases of a license are only used when none of its primary patterns match.
+func FindAlias(alias string, normalizedData *normalizer.NormalizationData) (Match, bool) {
+	matches := findAnyAlias([]string{alias}, normalizedData, nil)
+	if len(matches) == 0 {
+		return Match{}, false
+	}
+	return matches[0], true
+}
+
+// FindURL finds the license URL (with or without the scheme and www.) in the normalized text.
+// The URLs of a license are only used when none of its primary patterns or aliases match.
+func FindURL(url string, normalizedData *normalizer.NormalizationData) (Match, bool) {
+	matches := findAnyURL([]string{url}, normalizedData, nil)
+	if len(matches) == 0 {
+		return Match{}, false
+	}
+	return matches[0], true
+}
+
 func findPatterns(patterns []*licenses.PrimaryPatterns, normalizedData normalizer.NormalizationData, licenseMatches []Match, ll *licenses.LicenseLibrary) ([]Match, error) {
 	// errGroup to do the work in parallel until error
 	workers := errgroup.Group{}
@@ -468,11 +587,11 @@ func FindMatchingPatternInNormalizedData(matchingPattern *licenses.PrimaryPatter
 	matches := re.FindAllStringIndex(normalized.NormalizedText, -1)
 	for _, match := range matches {
 		// Create the result object, with the start and end points in the original text.
-		if match[1] < len(normalized.IndexMap) {
-			results = append(results, Match{Begins: normalized.IndexMap[match[0]], Ends: normalized.IndexMap[match[1]-1]})
+		if match[1] < normalized.IndexMap.Len() {
+			results = append(results, Match{Begins: normalized.IndexMap.At(match[0]), Ends: normalized.IndexMap.At(match[1] - 1)})
 		} else {
 			// End of map is out of range, so use the last index in the map
-			results = append(results, Match{Begins: normalized.IndexMap[match[0]], Ends: normalized.IndexMap[len(normalized.IndexMap)-1]})
+			results = append(results, Match{Begins: normalized.IndexMap.At(match[0]), Ends: normalized.IndexMap.At(normalized.IndexMap.Len() - 1)})
 		}
 	}
 
@@ -484,9 +603,11 @@ func PassedStaticBlocksChecks(staticBlocks []string, nd normalizer.Normalization
 	for i := range staticBlocks {
 		// If the input does not contain a static block, stop immediately and return false.
 		if !strings.Contains(nd.NormalizedText, staticBlocks[i]) {
+			precheckFailed.Inc()
 			return false
 		}
 	}
+	precheckPassed.Inc()
 	return true
 }
 
@@ -798,7 +919,7 @@ func recalculateMatchesFromBlocks(licenseResults IdentifierResults) map[string][
 		for _, licenseId := range blockMatches {
 			// If matches are null, copyright, keyword, or acceptable skip them.
 			switch licenseId {
-			case "", "COPYRIGHT", "KEYWORD", "ACCEPTABLE":
+			case "", "COPYRIGHT", "AUTHOR", "KEYWORD", "ACCEPTABLE":
 				continue
 			default:
 				newMatches[licenseId] = append(newMatches[licenseId], Match{Begins: begins, Ends: ends})
@@ -126,3 +126,41 @@ func Test_identifyLicensesInSPDXTestDataFiles(t *testing.T) {
 		})
 	}
 }
+
+func Test_identifyLicensesInFiles(t *testing.T) {
+	t.Parallel()
+	licenseLibrary, err := licenses.NewLicenseLibrary(nil)
+	if err != nil {
+		t.Fatalf("NewLicenseLibrary() error = %v", err)
+	}
+	if err := licenseLibrary.AddAllSPDX(); err != nil {
+		t.Fatalf("licenseLibrary.AddAllSPDX() error = %v", err)
+	}
+
+	ids := []string{"MIT", "0BSD", "Apache-2.0", "BSD-3-Clause", "ISC", "Zlib", "MIT"}
+	var files []string
+	for _, id := range ids {
+		files = append(files, path.Join(testDataDir, id+".txt"))
+	}
+
+	results, err := IdentifyLicensesInFiles(files, options, licenseLibrary)
+	if err != nil {
+		t.Fatalf("IdentifyLicensesInFiles() err = %v", err)
+	}
+	if len(results) != len(files) {
+		t.Fatalf("IdentifyLicensesInFiles() len(results) expected %v actual: %v", len(files), len(results))
+	}
+	// The results are in the same order as the files
+	for i, result := range results {
+		if result.File != files[i] {
+			t.Errorf("IdentifyLicensesInFiles() results[%v].File expected %v actual: %v", i, files[i], result.File)
+		}
+		if _, ok := result.Matches[ids[i]]; !ok {
+			t.Errorf("IdentifyLicensesInFiles() results[%v] expected %v actual: %v", i, ids[i], result.Matches)
+		}
+	}
+
+	if _, err := IdentifyLicensesInFiles(append(files, "bogus.txt"), options, licenseLibrary); err == nil {
+		t.Error("IdentifyLicensesInFiles() expected error for file not found")
+	}
+}
@@ -6,16 +6,27 @@ package identifier
 
 import (
 	_ "embed"
+	"os"
+	"path/filepath"
 	"reflect"
+	"strings"
 	"testing"
+	"unicode/utf16"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 
 	"github.com/CycloneDX/license-scanner/configurer"
 	"github.com/CycloneDX/license-scanner/licenses"
 	"github.com/CycloneDX/license-scanner/normalizer"
 )
 
+// ignorePositions compares the offsets of matches (the positions are tested by Test_identifyLicensesInStringPositions)
+var ignorePositions = cmp.Options{
+	cmpopts.IgnoreFields(Match{}, "Start", "End"),
+	cmpopts.IgnoreFields(PatternMatch{}, "Start", "End"),
+}
+
 func Test_generateTextBlocks(t *testing.T) {
 	match1 := []int{10, 15}
 	type args struct {
@@ -514,9 +525,9 @@ func Test_identifyLicensesInString(t *testing.T) {
 			got, err := IdentifyLicensesInString(tt.args.input, options, licenseLibrary)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("identifyLicensesInString() error = %v, wantErr %v", err, tt.wantErr)
-			} else if d := cmp.Diff(tt.want.Matches, got.Matches, cmp.AllowUnexported(Match{})); d != "" {
+			} else if d := cmp.Diff(tt.want.Matches, got.Matches, ignorePositions); d != "" {
 				t.Errorf("Didn't get expected result: (-want, +got): %v", d)
-			} else if d := cmp.Diff(tt.want.CopyRightStatements, got.CopyRightStatements); d != "" {
+			} else if d := cmp.Diff(tt.want.CopyRightStatements, got.CopyRightStatements, ignorePositions); d != "" {
 				t.Errorf("Didn't get expected result: (-want, +got): %v", d)
 			} else if d := cmp.Diff(tt.want.Blocks, got.Blocks); d != "" {
 				t.Errorf("Didn't get expected result: (-want, +got): %v", d)
@@ -527,6 +538,167 @@ func Test_identifyLicensesInString(t *testing.T) {
 	}
 }
 
+func Test_identifyLicensesInStringHTMLEntities(t *testing.T) {
+	licenseLibrary, err := licenses.NewLicenseLibrary(nil)
+	if err != nil {
+		t.Fatalf("NewLicenseLibrary() error = %v", err)
+	}
+	if err := licenseLibrary.AddAll(); err != nil {
+		t.Errorf("AddAll() error = %v", err)
+	}
+
+	// The MIT text of wcwidth, as it would be scraped from an HTML page
+	mit := strings.NewReplacer(
+		`"Software"`, "&ldquo;Software&#x201D;",
+		"and/or", "and&#47;or",
+		"in all\n", "in&nbsp;all\n",
+		"Software.", "Software&#46;",
+	).Replace(wcwidth[311:872])
+	input := "&copy; 2012 Jun&nbsp;Woong &amp; others\n\n" + mit
+
+	got, err := IdentifyLicensesInString(input, defaultOptions(), licenseLibrary)
+	if err != nil {
+		t.Fatalf("IdentifyLicensesInString() error = %v", err)
+	}
+	// The match begins at "Permission" and ends on the last character of the decoded entity &#46; (its "&")
+	want := map[string][]Match{"MIT": {{Begins: strings.Index(input, "Permission"), Ends: strings.LastIndex(input, "&#46;")}}}
+	if d := cmp.Diff(want, got.Matches, ignorePositions); d != "" {
+		t.Errorf("Didn't get expected result: (-want, +got): %v", d)
+	}
+}
+
+func Test_identifyLicensesInFileEncodings(t *testing.T) {
+	licenseLibrary, err := licenses.NewLicenseLibrary(nil)
+	if err != nil {
+		t.Fatalf("NewLicenseLibrary() error = %v", err)
+	}
+	if err := licenseLibrary.AddAll(); err != nil {
+		t.Errorf("AddAll() error = %v", err)
+	}
+
+	utf16le := []byte{0xFF, 0xFE}
+	for _, r := range utf16.Encode([]rune(wcwidth)) {
+		utf16le = append(utf16le, byte(r), byte(r>>8))
+	}
+	windows1252 := []byte(strings.ReplaceAll(wcwidth, `"Software"`, "\x93Software\x94"))
+
+	tests := []struct {
+		name     string
+		b        []byte
+		encoding string
+		want     Match
+	}{
+		// The MIT text of wcwidth is at 311-871 (see Test_identifyLicensesInString), which is lines 9-17 in any encoding
+		{name: "utf-16le", b: utf16le, encoding: normalizer.UTF16LE, want: Match{Begins: 2 + 311*2, Ends: 2 + 871*2 + 1, Start: Position{Line: 9, Column: 1}, End: Position{Line: 18, Column: 1}}},
+		{name: "windows-1252", b: windows1252, encoding: normalizer.Windows1252, want: Match{Begins: 311, Ends: 871, Start: Position{Line: 9, Column: 1}, End: Position{Line: 18, Column: 1}}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			f := filepath.Join(t.TempDir(), "LICENSE")
+			if err := os.WriteFile(f, tt.b, 0o600); err != nil {
+				t.Fatal(err)
+			}
+			got, err := IdentifyLicensesInFile(f, defaultOptions(), licenseLibrary)
+			if err != nil {
+				t.Fatalf("IdentifyLicensesInFile() error = %v", err)
+			}
+			if got.Encoding != tt.encoding {
+				t.Errorf("Encoding = %v, want %v", got.Encoding, tt.encoding)
+			}
+			if d := cmp.Diff(map[string][]Match{"MIT": {tt.want}}, got.Matches, cmp.AllowUnexported(Match{})); d != "" {
+				t.Errorf("Didn't get expected result: (-want, +got): %v", d)
+			}
+			if len(got.CopyRightStatements) > 0 {
+				c := got.CopyRightStatements[0]
+				if text := normalizer.Decode(tt.b[c.Begins : c.Ends+1]).Text; text != c.Text {
+					t.Errorf("copyright at %v-%v of the original bytes is %q, want %q", c.Begins, c.Ends, text, c.Text)
+				}
+			}
+		})
+	}
+}
+
+func Test_identifyLicensesInFileComments(t *testing.T) {
+	licenseLibrary, err := licenses.NewLicenseLibrary(nil)
+	if err != nil {
+		t.Fatalf("NewLicenseLibrary() error = %v", err)
+	}
+	if err := licenseLibrary.AddAll(); err != nil {
+		t.Errorf("AddAll() error = %v", err)
+	}
+
+	tests := []struct {
+		file     string
+		language string
+	}{
+		{file: "mit.c", language: "C"},
+		{file: "mit.py", language: "Python"},
+		{file: "mit.f90", language: "Fortran"},
+		{file: "mit.hs", language: "Haskell"},
+		{file: "mit.vb", language: "Visual Basic"},
+		{file: "mit.m", language: "MATLAB"},
+		{file: "mit-script", language: "Shell"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.file, func(t *testing.T) {
+			t.Parallel()
+			got, err := IdentifyLicensesInFile(filepath.Join("../testdata/comments", tt.file), defaultOptions(), licenseLibrary)
+			if err != nil {
+				t.Fatalf("IdentifyLicensesInFile() error = %v", err)
+			}
+			if got.Language != tt.language {
+				t.Errorf("Language = %v, want %v", got.Language, tt.language)
+			}
+			if len(got.Matches) != 1 || len(got.Matches["MIT"]) == 0 {
+				t.Fatalf("expected MIT matches, got %v", got.Matches)
+			}
+			// The match of the whole license is from the copyright to the end of the license in the original text
+			m := got.Matches["MIT"][0]
+			if text := got.OriginalText[m.Begins : m.Ends+1]; !strings.HasPrefix(text, "Copyright (c) 2023") || !strings.HasSuffix(text, "SOFTWARE.") {
+				t.Errorf("expected the match from the copyright to the end of the license, got %q", text)
+			}
+		})
+	}
+}
+
+func Test_identifyLicensesInFileCommentsUTF16(t *testing.T) {
+	licenseLibrary, err := licenses.NewLicenseLibrary(nil)
+	if err != nil {
+		t.Fatalf("NewLicenseLibrary() error = %v", err)
+	}
+	if err := licenseLibrary.AddAll(); err != nil {
+		t.Errorf("AddAll() error = %v", err)
+	}
+
+	// A script without an extension is detected by its #! line, after it is decoded
+	b, err := os.ReadFile("../testdata/comments/mit-script")
+	if err != nil {
+		t.Fatal(err)
+	}
+	utf16le := []byte{0xFF, 0xFE}
+	for _, r := range utf16.Encode([]rune(string(b))) {
+		utf16le = append(utf16le, byte(r), byte(r>>8))
+	}
+	file := filepath.Join(t.TempDir(), "mit-script")
+	if err := os.WriteFile(file, utf16le, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := IdentifyLicensesInFile(file, defaultOptions(), licenseLibrary)
+	if err != nil {
+		t.Fatalf("IdentifyLicensesInFile() error = %v", err)
+	}
+	if got.Language != "Shell" || got.Encoding != normalizer.UTF16LE {
+		t.Errorf("Language = %v, Encoding = %v, want Shell and %v", got.Language, got.Encoding, normalizer.UTF16LE)
+	}
+	if len(got.Matches["MIT"]) == 0 {
+		t.Errorf("expected MIT matches, got %v", got.Matches)
+	}
+}
+
 func Test_identifyLicensesInStringPreChecks(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -607,7 +779,7 @@ func Test_identifyLicensesInStringPreChecks(t *testing.T) {
 			got, err := IdentifyLicensesInString(tt.input, options, ll)
 			if err != nil {
 				t.Errorf("identifyLicensesInString() error = %v", err)
-			} else if d := cmp.Diff(tt.want.Matches, got.Matches, cmp.AllowUnexported(Match{})); d != "" {
+			} else if d := cmp.Diff(tt.want.Matches, got.Matches, ignorePositions); d != "" {
 				t.Errorf("Didn't get expected result: (-want, +got): %v", d)
 			} else if d := cmp.Diff(tt.want.Blocks, got.Blocks); d != "" {
 				t.Errorf("Didn't get expected result: (-want, +got): %v", d)
@@ -0,0 +1,91 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package identifier
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Position is a line and column of the original text. Lines and columns start at 1, and columns count runes (not bytes).
+type Position struct {
+	Line   int
+	Column int
+}
+
+func (p Position) String() string {
+	return fmt.Sprintf("%v:%v", p.Line, p.Column)
+}
+
+// Lines finds the positions of byte offsets in a text. The beginning of each line is found once.
+type Lines struct {
+	text   string
+	starts []int
+}
+
+// NewLines returns the lines of the text
+func NewLines(text string) *Lines {
+	starts := make([]int, 1, strings.Count(text, "\n")+1)
+	for i := strings.IndexByte(text, '\n'); i >= 0; {
+		starts = append(starts, i+1)
+		next := strings.IndexByte(text[i+1:], '\n')
+		if next < 0 {
+			break
+		}
+		i += next + 1
+	}
+	return &Lines{text: text, starts: starts}
+}
+
+// Position returns the position of the rune at the byte offset (which may be the end of the text)
+func (l *Lines) Position(offset int) Position {
+	if offset < 0 {
+		offset = 0
+	}
+	if offset > len(l.text) {
+		offset = len(l.text)
+	}
+	for offset > 0 && offset < len(l.text) && !utf8.RuneStart(l.text[offset]) {
+		offset--
+	}
+	line := sort.Search(len(l.starts), func(i int) bool { return l.starts[i] > offset })
+	return Position{Line: line, Column: utf8.RuneCountInString(l.text[l.starts[line-1]:offset]) + 1}
+}
+
+// Range returns the position of the first rune (at the begins offset) and the position after the last rune
+// (at the inclusive ends offset). Unlike the ends offset, the end position is exclusive: a match of one line has
+// end.Column - start.Column runes.
+func (l *Lines) Range(begins int, ends int) (start Position, end Position) {
+	after := ends + 1
+	for after > 0 && after < len(l.text) && !utf8.RuneStart(l.text[after]) {
+		after++
+	}
+	return l.Position(begins), l.Position(after)
+}
+
+// addPositions sets the line and column range of the matches, copyrights, authors, and keywords from their offsets
+// in the text
+func (r *IdentifierResults) addPositions(text string) {
+	lines := NewLines(text)
+	for _, matches := range r.Matches {
+		for i := range matches {
+			matches[i].Start, matches[i].End = lines.Range(matches[i].Begins, matches[i].Ends)
+		}
+	}
+	for _, pms := range [][]PatternMatch{r.AcceptablePatternMatches, r.KeywordMatches, r.CopyRightStatements, r.AuthorStatements} {
+		for i := range pms {
+			pms[i].Start, pms[i].End = lines.Range(pms[i].Begins, pms[i].Ends)
+		}
+	}
+	for i := range r.Keywords {
+		r.Keywords[i].Start, r.Keywords[i].End = lines.Range(r.Keywords[i].Begins, r.Keywords[i].Ends)
+	}
+	for i := range r.Copyrights {
+		r.Copyrights[i].Start, r.Copyrights[i].End = lines.Range(r.Copyrights[i].Begins, r.Copyrights[i].Ends)
+	}
+	for i := range r.Authors {
+		r.Authors[i].Start, r.Authors[i].End = lines.Range(r.Authors[i].Begins, r.Authors[i].Ends)
+	}
+}
@@ -0,0 +1,116 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package identifier
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/CycloneDX/license-scanner/licenses"
+)
+
+func TestLines_Range(t *testing.T) {
+	tests := []struct {
+		name   string
+		text   string
+		begins int
+		ends   int
+		start  Position
+		end    Position
+	}{
+		{
+			name: "one line", text: "MIT License", begins: 0, ends: 2,
+			start: Position{Line: 1, Column: 1}, end: Position{Line: 1, Column: 4},
+		},
+		{
+			name: "end of the text", text: "MIT License", begins: 4, ends: 10,
+			start: Position{Line: 1, Column: 5}, end: Position{Line: 1, Column: 12},
+		},
+		{
+			name: "second line", text: "line one\nline two", begins: 14, ends: 16,
+			start: Position{Line: 2, Column: 6}, end: Position{Line: 2, Column: 9},
+		},
+		{
+			name: "ends with a newline", text: "line one\nline two", begins: 0, ends: 8,
+			start: Position{Line: 1, Column: 1}, end: Position{Line: 2, Column: 1},
+		},
+		{
+			name: "columns count runes", text: "© Jürgen Müller", begins: 3, ends: 17,
+			start: Position{Line: 1, Column: 3}, end: Position{Line: 1, Column: 16},
+		},
+		{
+			name: "ends in the middle of a rune", text: "© Jürgen Müller", begins: 1, ends: 14,
+			start: Position{Line: 1, Column: 1}, end: Position{Line: 1, Column: 13},
+		},
+		{
+			name: "CRLF", text: "line one\r\nline two\r\n", begins: 10, ends: 17,
+			start: Position{Line: 2, Column: 1}, end: Position{Line: 2, Column: 9},
+		},
+		{
+			name: "blank lines", text: "\n\n\nx", begins: 3, ends: 3,
+			start: Position{Line: 4, Column: 1}, end: Position{Line: 4, Column: 2},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			start, end := NewLines(tt.text).Range(tt.begins, tt.ends)
+			if start != tt.start || end != tt.end {
+				t.Errorf("Range(%v, %v) = %v-%v, want %v-%v", tt.begins, tt.ends, start, end, tt.start, tt.end)
+			}
+		})
+	}
+}
+
+func Test_identifyLicensesInStringPositions(t *testing.T) {
+	licenseLibrary, err := licenses.NewLicenseLibrary(nil)
+	if err != nil {
+		t.Fatalf("NewLicenseLibrary() error = %v", err)
+	}
+	if err := licenseLibrary.AddAll(); err != nil {
+		t.Fatalf("AddAll() error = %v", err)
+	}
+
+	input := "Überblick\r\n\r\n  Copyright © 2020 Jürgen Müller\r\n\r\nLizenz – Apache-2.0\r\n"
+	options := defaultOptions()
+	options.Enhancements.FlagKeywords = true
+	got, err := IdentifyLicensesInString(input, options, licenseLibrary)
+	if err != nil {
+		t.Fatalf("IdentifyLicensesInString() error = %v", err)
+	}
+
+	// Each position is checked against the line and column of the text at its offsets
+	lines := strings.Split(input, "\n")
+	check := func(kind string, begins int, ends int, start Position, end Position) {
+		t.Helper()
+		text := input[begins : ends+1]
+		if start.Line != end.Line {
+			t.Errorf("%v %q: %v-%v is not one line", kind, text, start, end)
+			return
+		}
+		line := []rune(lines[start.Line-1])
+		if start.Column < 1 || end.Column > len(line)+1 || string(line[start.Column-1:end.Column-1]) != text {
+			t.Errorf("%v %q: %v-%v is not the text", kind, text, start, end)
+		}
+	}
+
+	if len(got.Matches["Apache-2.0"]) == 0 {
+		t.Fatalf("Apache-2.0 not matched: %v", got.Matches)
+	}
+	for _, m := range got.Matches["Apache-2.0"] {
+		check("match", m.Begins, m.Ends, m.Start, m.End)
+	}
+	if len(got.Copyrights) != 1 {
+		t.Fatalf("got %v copyrights, want 1", len(got.Copyrights))
+	}
+	c := got.Copyrights[0]
+	check("copyright", c.Begins, c.Ends, c.Start, c.End)
+	if want := (Position{Line: 3, Column: 33}); c.End != want {
+		t.Errorf("copyright ends at %v, want %v", c.End, want)
+	}
+	for _, k := range got.Keywords {
+		check("keyword", k.Begins, k.Ends, k.Start, k.End)
+	}
+}
@@ -21,8 +21,8 @@ import (
 var Logger = log.NewLogger(log.INFO)
 
 // Import validates, preprocesses, and copies templates into resources (or into external paths)
-// This implements --addAll (which probably be changed to license-scanner import ...)
-// The --addAll <string> value is the input dir. Output is determined by spdxPath/spdx/customPath/custom flags.
+// This implements "license-scanner import <dir>" (and the deprecated --addAll <dir>)
+// The <dir> argument (or --addAll value) is the input dir. Output is determined by spdxPath/spdx/customPath/custom flags.
 func Import(cfg *viper.Viper) error {
 	input := cfg.GetString(configurer.AddAllFlag)
 	if input == "" {
@@ -42,7 +42,7 @@ func Import(cfg *viper.Viper) error {
 }
 
 // Update validates, preprocesses, and updates preprocessed prechecks in resources (or into external paths)
-// This implements --updateAll (which probably be changed to license-scanner update ...)
+// This implements "license-scanner update" (and the deprecated --updateAll)
 // The args spdxPath/spdx/customPath/custom are used to determine which resources (or external dir) are updated in-place.
 func Update(cfg *viper.Viper) error {
 	doUpdate := cfg.GetBool(configurer.UpdateAllFlag)
@@ -289,7 +289,7 @@ func importSPDXResource(input *resources.Resources, output *resources.Resources,
 	}
 	staticBlocks, err := validate(id, templateBytes, textBytes, templateFile)
 	if err != nil {
-		_ = Logger.Errorf("template ID %v is not valid", id)
+		_ = Logger.Errorf("template ID %v is not valid: %v", id, err)
 		writeInvalidSPDXFiles(output, id, templateBytes, textBytes)
 		return err
 	}
@@ -362,14 +362,10 @@ func updateCustom(r *resources.Resources) error {
 
 // normalizeAndRegex verifies that we can normalize the template text and create a regex and returns useful stuff
 func normalizeAndRegex(s string) (*normalizer.NormalizationData, error) {
-	normalizedData := normalizer.NewNormalizationData(s, true)
-	if err := normalizedData.NormalizeText(); err != nil {
-		return nil, fmt.Errorf("cannot normalize text: %w", err)
-	}
-	// Generate regex just to make sure there isn't a regex compile error with this input
-	_, err := licenses.GenerateRegexFromNormalizedText(normalizedData.NormalizedText)
+	// Compile the regex just to make sure there isn't a problem with the markup or a regex compile error with this input
+	_, normalizedData, err := licenses.CompileTemplate(s, nil)
 	if err != nil {
-		return nil, fmt.Errorf("cannot generate re: %w", err)
+		return nil, fmt.Errorf("cannot compile template: %w", err)
 	}
 	return normalizedData, nil
 }
@@ -0,0 +1,123 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package licenses
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/CycloneDX/license-scanner/configurer"
+)
+
+const (
+	DefaultKeywordCategory = "keyword"
+	DefaultKeywordSeverity = "info"
+)
+
+// Keyword is a word, phrase, or regex to flag in text which did not match a license
+type Keyword struct {
+	Pattern  string `json:"pattern" mapstructure:"pattern"`
+	Category string `json:"category" mapstructure:"category"`
+	Severity string `json:"severity" mapstructure:"severity"`
+}
+
+// Keywords is a list of keywords compiled (once) for matching and classifying matches
+type Keywords struct {
+	List []Keyword
+	// Regexp matches any of the keywords (case-insensitive, starting at a word boundary)
+	Regexp *regexp.Regexp
+	// exact holds the whole-text regexp for each keyword to classify a match
+	exact []*regexp.Regexp
+}
+
+// NewKeywords compiles a list of keywords
+func NewKeywords(list []Keyword) (*Keywords, error) {
+	k := Keywords{List: make([]Keyword, 0, len(list))}
+	var patterns []string
+	for _, kw := range list {
+		kw.Pattern = strings.TrimSpace(kw.Pattern)
+		if kw.Pattern == "" {
+			return nil, fmt.Errorf("invalid keyword with empty pattern: %+v", kw)
+		}
+		if kw.Category == "" {
+			kw.Category = DefaultKeywordCategory
+		}
+		if kw.Severity == "" {
+			kw.Severity = DefaultKeywordSeverity
+		}
+		exact, err := regexp.Compile(`(?i)^(?:` + kw.Pattern + `)$`)
+		if err != nil {
+			return nil, fmt.Errorf("invalid keyword pattern %q: %w", kw.Pattern, err)
+		}
+		k.List = append(k.List, kw)
+		k.exact = append(k.exact, exact)
+		// Each pattern is a group, so that the word boundary applies to all of its alternatives
+		patterns = append(patterns, `\b(?:`+kw.Pattern+`)`)
+	}
+
+	re, err := regexp.Compile(`(?i)` + strings.Join(patterns, "|"))
+	if err != nil {
+		return nil, err
+	}
+	k.Regexp = re
+	return &k, nil
+}
+
+// Classify returns the first keyword which matches all of the text
+func (k *Keywords) Classify(text string) (Keyword, bool) {
+	for i, re := range k.exact {
+		if re.MatchString(text) {
+			return k.List[i], true
+		}
+	}
+	return Keyword{}, false
+}
+
+// ReadKeywordsJSON unmarshalls the json bytes into a list of keywords
+func ReadKeywordsJSON(fileContents []byte) ([]Keyword, error) {
+	var list []Keyword
+	if err := json.Unmarshal(fileContents, &list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// addKeywordsFromConfig compiles the keywordList from the config and the keywords in the keywordsPath file, if any.
+// Without any configured keywords, Keywords is left nil and the identifier defaults are used.
+func (ll *LicenseLibrary) addKeywordsFromConfig() error {
+	if ll.Config == nil {
+		return nil
+	}
+
+	var list []Keyword
+	if err := ll.Config.UnmarshalKey(configurer.KeywordListFlag, &list); err != nil {
+		return fmt.Errorf("invalid %v in config: %w", configurer.KeywordListFlag, err)
+	}
+
+	if keywordsPath := ll.Config.GetString(configurer.KeywordsPathFlag); keywordsPath != "" {
+		fileContents, err := os.ReadFile(keywordsPath)
+		if err != nil {
+			return err
+		}
+		fromFile, err := ReadKeywordsJSON(fileContents)
+		if err != nil {
+			return fmt.Errorf("invalid keywords in %v: %w", keywordsPath, err)
+		}
+		list = append(list, fromFile...)
+	}
+
+	if len(list) == 0 {
+		return nil
+	}
+
+	keywords, err := NewKeywords(list)
+	if err != nil {
+		return err
+	}
+	ll.Keywords = keywords
+	Logger.Debugf("Loaded %v keywords", len(keywords.List))
+	return nil
+}
@@ -0,0 +1,81 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package licenses
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/CycloneDX/license-scanner/configurer"
+)
+
+func TestLicenseLibrary_addKeywordsFromConfig(t *testing.T) {
+	flags := configurer.NewDefaultFlags()
+	if err := flags.Set(configurer.ConfigPathFlag, "../testdata/keywords/"); err != nil {
+		t.Fatal(err)
+	}
+	config, err := configurer.InitConfig(flags)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ll, err := NewLicenseLibrary(config)
+	if err != nil {
+		t.Fatalf("NewLicenseLibrary() error = %v", err)
+	}
+	if err := ll.addKeywordsFromConfig(); err != nil {
+		t.Fatalf("addKeywordsFromConfig() error = %v", err)
+	}
+
+	want := []Keyword{
+		{Pattern: "proprietary", Category: "proprietary", Severity: "high"},
+		{Pattern: "confidential", Category: "proprietary", Severity: "high"},
+		{Pattern: "do not (?:re)?distribute", Category: "distribution", Severity: "high"},
+		{Pattern: "export controll?ed", Category: "export", Severity: "medium"},
+		{Pattern: "patents?", Category: "patent", Severity: DefaultKeywordSeverity},
+	}
+	if diff := cmp.Diff(want, ll.Keywords.List); diff != "" {
+		t.Errorf("Didn't get expected keywords (-want, +got): %+v", diff)
+	}
+
+	tests := map[string]string{
+		"Export Controlled":   "export",
+		"Do not redistribute": "distribution",
+		"PATENTS":             "patent",
+	}
+	for text, category := range tests {
+		if !ll.Keywords.Regexp.MatchString("This is " + text) {
+			t.Errorf("Expected keywords regexp to match %q", text)
+		}
+		k, ok := ll.Keywords.Classify(text)
+		if !ok || k.Category != category {
+			t.Errorf("Classify(%q) = %+v, %v; want category %v", text, k, ok, category)
+		}
+	}
+}
+
+func TestNewKeywords_invalid(t *testing.T) {
+	for _, list := range [][]Keyword{
+		{{Pattern: ""}},
+		{{Pattern: "unbalanced("}},
+	} {
+		if _, err := NewKeywords(list); err == nil {
+			t.Errorf("NewKeywords(%+v) expected error", list)
+		}
+	}
+}
+
+func TestNewKeywords_alternation(t *testing.T) {
+	k, err := NewKeywords([]Keyword{{Pattern: "confidential"}, {Pattern: "gpl|agpl"}})
+	if err != nil {
+		t.Fatalf("NewKeywords() error = %v", err)
+	}
+	// Each alternative of a pattern starts at a word boundary
+	got := k.Regexp.FindAllString("Magpl and AGPL are not GPLv2 or confidential", -1)
+	want := []string{"AGPL", "GPL", "confidential"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Regexp.FindAllString() (-want, +got): %v", diff)
+	}
+}
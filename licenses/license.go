@@ -14,10 +14,13 @@ import (
 	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/CycloneDX/license-scanner/configurer"
+	"github.com/CycloneDX/license-scanner/metrics"
 	"github.com/CycloneDX/license-scanner/normalizer"
 	"github.com/CycloneDX/license-scanner/resources"
+	"github.com/CycloneDX/license-scanner/template"
 	"github.com/CycloneDX/sbom-utility/log"
 	"github.com/spf13/viper"
 )
@@ -31,33 +34,25 @@ const (
 	AcceptablePatterns = "acceptable_patterns"
 )
 
-var (
-	Logger                 = log.NewLogger(log.INFO)
-	pointyBracketSegmentRE = regexp.MustCompile(` *<<(.*?)>> *`)
-	RegexUnsafePattern     = regexp.MustCompile(`([\\.*+?^${}()|[\]])`)
-	spaceTagReplacer       = strings.NewReplacer(
-		" <<", "<<",
-		">> ", ">>",
-	)
-	tagReplacer = strings.NewReplacer(
-		"<<omitable>>", "BEGIN_OMITABLE",
-		"<</omitable>>", "END_OMITABLE",
-		"<<copyright>>", "COPYRIGHT",
-	)
-	tokenReplacer = strings.NewReplacer(
-		"BEGIN_OMITABLE", " *(?:",
-		"END_OMITABLE", " *)?",
-		"COPYRIGHT", ".*",
-	)
-)
+var Logger = log.NewLogger(log.INFO)
+
+var patternCompileSeconds = metrics.NewHistogram("license_scanner_pattern_compile_duration_seconds",
+	"Time to normalize a license template and compile its regular expression (once per template).", metrics.DefBuckets)
 
 type LicenseLibrary struct {
 	SPDXVersion               string
 	LicenseMap                LicenseMap
 	PrimaryPatternPreCheckMap PrimaryPatternPreCheckMap
 	AcceptablePatternsMap     PatternsMap
-	Config                    *viper.Viper
-	Resources                 *resources.Resources
+	Keywords                  *Keywords
+	// Pipeline normalizes the templates and input text (the DefaultPipeline unless configured)
+	Pipeline  *normalizer.Pipeline
+	Config    *viper.Viper
+	Resources *resources.Resources
+
+	// cache holds the values built from the licenses by other packages (see Cached)
+	cacheMu sync.Mutex
+	cache   map[interface{}]interface{}
 }
 
 type LicensePreChecks struct {
@@ -103,9 +98,29 @@ func NewLicenseLibrary(config *viper.Viper) (*LicenseLibrary, error) {
 		Resources:                 resources.NewResources(config),
 	}
 
+	if err := ll.addPipelineFromConfig(); err != nil {
+		return nil, err
+	}
+
 	return &ll, nil
 }
 
+// Cached returns the value of the key, which is built from the licenses of the library when it is first used
+// (e.g. a lookup by license name), and then shared by the later callers. The licenses must be added before.
+func (ll *LicenseLibrary) Cached(key interface{}, build func() interface{}) interface{} {
+	ll.cacheMu.Lock()
+	defer ll.cacheMu.Unlock()
+	if v, ok := ll.cache[key]; ok {
+		return v
+	}
+	if ll.cache == nil {
+		ll.cache = make(map[interface{}]interface{})
+	}
+	v := build()
+	ll.cache[key] = v
+	return v
+}
+
 type LicenseMap map[string]License
 
 // License holds the specification of each license
@@ -133,6 +148,8 @@ type PrimaryPatterns struct {
 	re            *regexp.Regexp
 	CaptureGroups []*normalizer.CaptureGroup
 	FileName      string
+	// Pipeline normalizes the pattern (nil for the DefaultPipeline)
+	Pipeline *normalizer.Pipeline
 }
 
 type PrimaryPatternsSources struct {
@@ -285,6 +302,7 @@ func (ll *LicenseLibrary) AddAllSPDX() error {
 		l.LicenseInfo.IsDeprecated = sl.IsDeprecatedLicenseID
 		l.LicenseInfo.OSIApproved = sl.IsOSIApproved
 		l.LicenseInfo.IsFSFLibre = sl.IsFSFLibre
+		ll.usePipeline(&l)
 		ll.LicenseMap[id] = l
 
 		if err := addSPDXPreCheck(id, f, sl.IsDeprecatedLicenseID, ll); err != nil {
@@ -312,6 +330,7 @@ func (ll *LicenseLibrary) AddAllSPDX() error {
 		l.LicenseInfo.SPDXStandard = true
 		l.LicenseInfo.SPDXException = true
 		l.LicenseInfo.IsDeprecated = se.IsDeprecatedLicenseID
+		ll.usePipeline(&l)
 		ll.LicenseMap[id] = l
 
 		if err := addSPDXPreCheck(id, f, se.IsDeprecatedLicenseID, ll); err != nil {
@@ -355,6 +374,10 @@ func (ll *LicenseLibrary) AddAllCustom() error {
 	}
 	Logger.Debugf("Loaded %v acceptable patterns", len(ll.AcceptablePatternsMap))
 
+	if err := ll.addKeywordsFromConfig(); err != nil {
+		return err
+	}
+
 	if err := ll.AddCustomLicenses(); err != nil {
 		return err
 	}
@@ -560,11 +583,16 @@ func AddLicense(id string, ll *LicenseLibrary) error {
 			Logger.Info(fmt.Sprintf("found an invalid file name %s", filePath))
 		}
 	}
+	ll.usePipeline(&l)
 	ll.LicenseMap[id] = l
 	return nil
 }
 
 func addPreChecks(fileContents []byte, templatePath string, ll *LicenseLibrary) error {
+	if !ll.Pipeline.IsDefault() {
+		// The prechecks were generated with the default pipeline, so they may not be in text normalized by this one
+		return nil
+	}
 	readPreChecks := &LicensePreChecks{}
 	err := json.Unmarshal(fileContents, readPreChecks)
 	if err != nil {
@@ -596,67 +624,46 @@ func AddPrimaryPatternAndSource(fileContents string, filePath string, l *License
 func GenerateMatchingPatternFromSourceText(pp *PrimaryPatterns) (*regexp.Regexp, error) {
 	var err error
 	pp.doOnce.Do(func() {
-		// Normalize the input text.
-		normalizedData := normalizer.NewNormalizationData(pp.Text, true)
-		err = normalizedData.NormalizeText()
-		if err == nil {
-			var re *regexp.Regexp
-			re, err = GenerateRegexFromNormalizedText(normalizedData.NormalizedText)
-			if err == nil {
-				pp.re = re
-				pp.CaptureGroups = normalizedData.CaptureGroups
-			} else {
-				err = fmt.Errorf("cannot generate re: %v", err)
-			}
+		defer patternCompileSeconds.ObserveSince(time.Now())
+		var normalizedData *normalizer.NormalizationData
+		pp.re, normalizedData, err = CompileTemplate(pp.Text, pp.Pipeline)
+		if err != nil {
+			err = fmt.Errorf("cannot compile template %v: %w", pp.FileName, err)
+			return
 		}
+		pp.CaptureGroups = normalizedData.CaptureGroups
 	})
 	return pp.re, err
 }
 
-func GenerateRegexFromNormalizedText(normalizedText string) (*regexp.Regexp, error) {
-	// Eat optional single space before "<<" and after ">>" (just refactoring what was in regex)
-	text := spaceTagReplacer.Replace(normalizedText)
-	// Replace simple tags with tokens, so we can attack the not-simple tags which might be nested in these
-	text = tagReplacer.Replace(text)
-
-	// Replace matched <<segment>> with ` ?(?:(`+segment+`) ?)`
-	// Escape regex-unsafe characters outside of tags.
-	// Then put the segments back together
-	matches := pointyBracketSegmentRE.FindAllStringSubmatchIndex(text, -1)
-
-	var segments []string
-	prev := 0
-	for _, ii := range matches {
-
-		start := ii[0]
-		end := ii[1]
-
-		if start > prev {
-			// Handle pre-match characters
-			// Escape unsafe characters in the text elements.
-			segment := text[prev:start]
-			segment = RegexUnsafePattern.ReplaceAllString(segment, `\${1}`)
-			segments = append(segments, segment)
-		}
-
-		// Handle the sub-matched chars (inside the <<>>)
-		submatchStart := ii[2]
-		submatchEnd := ii[3]
-		segment := text[submatchStart:submatchEnd]
-
-		prev = end
-		segments = append(segments, ` *(?:(`+segment+`) *)`)
+// CompileTemplate parses the markup of the template text (see the template package), normalizes the template with the
+// pipeline, and compiles the regex of the normalized template. A problem with the markup (or a match regex) is returned
+// as template.Diagnostics at the lines of the template text.
+func CompileTemplate(text string, pipeline *normalizer.Pipeline) (*regexp.Regexp, *normalizer.NormalizationData, error) {
+	if _, err := template.Parse(text); err != nil {
+		return nil, nil, err
 	}
-	if prev < len(text) {
-		segment := text[prev:]
-		segment = RegexUnsafePattern.ReplaceAllString(segment, `\${1}`)
-		segments = append(segments, segment)
+	normalizedData := normalizer.NewNormalizationData(text, true)
+	normalizedData.Pipeline = pipeline
+	if err := normalizedData.NormalizeText(); err != nil {
+		return nil, nil, err
 	}
+	re, err := GenerateRegexFromNormalizedText(normalizedData.NormalizedText)
+	var diagnostics template.Diagnostics
+	if errors.As(err, &diagnostics) {
+		// Map the offsets of the normalized template to the template text
+		err = diagnostics.Remap(text, normalizedData.IndexMap.At)
+	}
+	return re, normalizedData, err
+}
 
-	// Rejoin segments, replace tokens, compile, and return (*re, err)
-	text = strings.Join(segments, "")
-	text = tokenReplacer.Replace(text)
-	return regexp.Compile(text)
+// GenerateRegexFromNormalizedText compiles the regex of a normalized template from its parsed markup (see the template package)
+func GenerateRegexFromNormalizedText(normalizedText string) (*regexp.Regexp, error) {
+	t, err := template.ParseNormalized(normalizedText)
+	if err != nil {
+		return nil, err
+	}
+	return template.Compile(t)
 }
 
 func List(config *viper.Viper) (lics []Detail, deprecatedLics []Detail, exceptions []Exception, deprecatedExceptions []Exception, spdxVersion string, err error) {
@@ -671,8 +678,12 @@ func List(config *viper.Viper) (lics []Detail, deprecatedLics []Detail, exceptio
 		return
 	}
 
-	spdxVersion = ll.SPDXVersion
+	lics, deprecatedLics, exceptions, deprecatedExceptions = ll.List()
+	return lics, deprecatedLics, exceptions, deprecatedExceptions, ll.SPDXVersion, nil
+}
 
+// List returns the licenses and exceptions in the license library sorted by ID
+func (ll *LicenseLibrary) List() (lics []Detail, deprecatedLics []Detail, exceptions []Exception, deprecatedExceptions []Exception) {
 	lm := ll.LicenseMap
 
 	// Sort by key
@@ -13,6 +13,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 
 	"github.com/CycloneDX/license-scanner/configurer"
+	"github.com/CycloneDX/license-scanner/normalizer"
 )
 
 const (
@@ -408,3 +409,36 @@ func TestLicenseUnmarshal(t *testing.T) {
 		}
 	}
 }
+
+func TestCompileTemplate(t *testing.T) {
+	text := "MIT License\r\n\r\nCopyright (c) <<var;name=\"copyright\";original=\"<year> <owner>\";match=\".{0,5000}\">>\r\n\r\n" +
+		"Permission is granted to <<beginOptional>>any person obtaining a copy of <<endOptional>><<var;name=\"the\";original=\"the\";match=\"the|this\">> software"
+
+	re, normalizedData, err := CompileTemplate(text, nil)
+	if err != nil {
+		t.Fatalf("CompileTemplate() error = %v", err)
+	}
+	if len(normalizedData.CaptureGroups) != 2 {
+		t.Errorf("got %v capture groups, want 2", len(normalizedData.CaptureGroups))
+	}
+	if !re.MatchString("mit license copyright copyright 2023 someone permission is granted to this software") {
+		t.Errorf("CompileTemplate() = %v does not match the license", re)
+	}
+
+	// A problem with a var found after the normalization is at the line of the var in the template text
+	pipeline, err := normalizer.ConfigurePipeline(nil, nil, []normalizer.RegexpStep{{Name: "breakVars", Pattern: `<<the\|this>>`, Replacement: "<<(the|this>>"}})
+	if err != nil {
+		t.Fatalf("ConfigurePipeline() error = %v", err)
+	}
+	_, _, err = CompileTemplate(text, pipeline)
+	want := "5:89: match \"(the|this\" is not supported: error parsing regexp: missing closing ): `(the|this`"
+	if err == nil || err.Error() != want {
+		t.Errorf("CompileTemplate() error = %v, want %v", err, want)
+	}
+
+	_, _, err = CompileTemplate("MIT License<<endOptional>>", nil)
+	want = "1:12: <<endOptional>> without a <<beginOptional>>"
+	if err == nil || err.Error() != want {
+		t.Errorf("CompileTemplate() error = %v, want %v", err, want)
+	}
+}
@@ -0,0 +1,97 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package licenses
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/CycloneDX/license-scanner/configurer"
+	"github.com/CycloneDX/license-scanner/normalizer"
+)
+
+// addPipelineFromConfig sets the normalization pipeline from the normalizationSteps, normalizationSkip,
+// and normalizationRegexpSteps in the config, with the replacement words from the config (see addVarietalWordsFromConfig).
+// Without any of these, the DefaultPipeline is used.
+func (ll *LicenseLibrary) addPipelineFromConfig() error {
+	ll.Pipeline = normalizer.DefaultPipeline()
+	if ll.Config == nil {
+		return nil
+	}
+	if err := ll.addStepsFromConfig(); err != nil {
+		return err
+	}
+	if err := ll.addVarietalWordsFromConfig(); err != nil {
+		return err
+	}
+	if !ll.Pipeline.IsDefault() {
+		Logger.Infof("Using the configured normalization steps %v and replacement words (without the prechecks)", ll.Pipeline.Steps())
+	}
+	return nil
+}
+
+// addStepsFromConfig configures the steps of the pipeline
+func (ll *LicenseLibrary) addStepsFromConfig() error {
+	steps := ll.Config.GetStringSlice(configurer.NormalizationStepsFlag)
+	skip := ll.Config.GetStringSlice(configurer.NormalizationSkipFlag)
+	var regexpSteps []normalizer.RegexpStep
+	if err := ll.Config.UnmarshalKey(configurer.NormalizationRegexpStepsFlag, &regexpSteps); err != nil {
+		return fmt.Errorf("invalid %v in config: %w", configurer.NormalizationRegexpStepsFlag, err)
+	}
+	if len(steps) == 0 && len(skip) == 0 && len(regexpSteps) == 0 {
+		return nil
+	}
+
+	pipeline, err := normalizer.ConfigurePipeline(steps, skip, regexpSteps)
+	if err != nil {
+		return err
+	}
+	ll.Pipeline = pipeline
+	return nil
+}
+
+// addVarietalWordsFromConfig merges the replacementWords in the config and the replacement words in the
+// replacementWordsPath file, if any, with the default varietal word spellings. The merged words are compiled once,
+// and the same pipeline normalizes the templates and the input text.
+func (ll *LicenseLibrary) addVarietalWordsFromConfig() error {
+	var fromConfig map[string]string
+	if err := ll.Config.UnmarshalKey(configurer.ReplacementWordsFlag, &fromConfig); err != nil {
+		return fmt.Errorf("invalid %v in config: %w", configurer.ReplacementWordsFlag, err)
+	}
+
+	var fromFile map[string]string
+	if wordsPath := ll.Config.GetString(configurer.ReplacementWordsPathFlag); wordsPath != "" {
+		fileContents, err := os.ReadFile(wordsPath)
+		if err != nil {
+			return err
+		}
+		if fromFile, err = normalizer.ReadReplacementWordsJSON(fileContents); err != nil {
+			return fmt.Errorf("invalid replacement words in %v: %w", wordsPath, err)
+		}
+	}
+
+	if len(fromConfig) == 0 && len(fromFile) == 0 {
+		return nil
+	}
+
+	words, err := normalizer.NewVarietalWords(fromConfig, fromFile)
+	if err != nil {
+		return err
+	}
+	ll.Pipeline = ll.Pipeline.WithVarietalWords(words)
+	Logger.Debugf("Loaded %v replacement words", words.Len())
+	return nil
+}
+
+// usePipeline sets the library pipeline to normalize the patterns of the license
+func (ll *LicenseLibrary) usePipeline(l *License) {
+	if ll.Pipeline.IsDefault() {
+		return
+	}
+	for _, pp := range l.PrimaryPatterns {
+		pp.Pipeline = ll.Pipeline
+	}
+	for _, pp := range l.AssociatedPatterns {
+		pp.Pipeline = ll.Pipeline
+	}
+}
@@ -0,0 +1,123 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package licenses
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/CycloneDX/license-scanner/configurer"
+	"github.com/CycloneDX/license-scanner/normalizer"
+)
+
+func TestLicenseLibrary_addPipelineFromConfig(t *testing.T) {
+	flags := configurer.NewDefaultFlags()
+	if err := flags.Set(configurer.ConfigPathFlag, "../testdata/pipeline/"); err != nil {
+		t.Fatal(err)
+	}
+	config, err := configurer.InitConfig(flags)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ll, err := NewLicenseLibrary(config)
+	if err != nil {
+		t.Fatalf("NewLicenseLibrary() error = %v", err)
+	}
+
+	var want []string
+	for _, step := range normalizer.DefaultSteps {
+		switch step {
+		case "replaceBulletsAndNumbering":
+			continue
+		case "removeCodeCommentIndicators":
+			want = append(want, step, "removeDoxygenCommands")
+		default:
+			want = append(want, step)
+		}
+	}
+	if d := cmp.Diff(want, ll.Pipeline.Steps()); d != "" {
+		t.Errorf("Didn't get expected steps (-want, +got): %v", d)
+	}
+
+	if err := ll.AddAll(); err != nil {
+		t.Fatalf("AddAll() error = %v", err)
+	}
+	if len(ll.PrimaryPatternPreCheckMap) != 0 {
+		t.Errorf("expected no prechecks with a configured pipeline, got %v", len(ll.PrimaryPatternPreCheckMap))
+	}
+	mit := ll.LicenseMap["MIT"]
+	if len(mit.PrimaryPatterns) == 0 || mit.PrimaryPatterns[0].Pipeline != ll.Pipeline {
+		t.Fatalf("expected the MIT patterns to use the library pipeline")
+	}
+
+	re, err := GenerateMatchingPatternFromSourceText(mit.PrimaryPatterns[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	nd := normalizer.NormalizationData{
+		OriginalText: "/**\n * @file\n * @copyright 2023 Someone\n *\n" +
+			" * Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated documentation files (the \"Software\"), to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions:\n" +
+			" * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.\n" +
+			" * THE SOFTWARE IS PROVIDED \"AS IS\", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.\n */",
+		Pipeline: ll.Pipeline,
+	}
+	if err := nd.NormalizeText(); err != nil {
+		t.Fatal(err)
+	}
+	if !re.MatchString(nd.NormalizedText) {
+		t.Errorf("expected the MIT pattern to match %q", nd.NormalizedText)
+	}
+}
+
+func TestLicenseLibrary_addVarietalWordsFromConfig(t *testing.T) {
+	flags := configurer.NewDefaultFlags()
+	if err := flags.Set(configurer.ConfigPathFlag, "../testdata/replacement_words/"); err != nil {
+		t.Fatal(err)
+	}
+	config, err := configurer.InitConfig(flags)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ll, err := NewLicenseLibrary(config)
+	if err != nil {
+		t.Fatalf("NewLicenseLibrary() error = %v", err)
+	}
+	if ll.Pipeline.IsDefault() {
+		t.Fatalf("expected a pipeline with the configured replacement words")
+	}
+	if d := cmp.Diff(normalizer.DefaultSteps, ll.Pipeline.Steps()); d != "" {
+		t.Errorf("Didn't get expected steps (-want, +got): %v", d)
+	}
+
+	if err := ll.AddAll(); err != nil {
+		t.Fatalf("AddAll() error = %v", err)
+	}
+	if len(ll.PrimaryPatternPreCheckMap) != 0 {
+		t.Errorf("expected no prechecks with configured replacement words, got %v", len(ll.PrimaryPatternPreCheckMap))
+	}
+	mit := ll.LicenseMap["MIT"]
+	if len(mit.PrimaryPatterns) == 0 || mit.PrimaryPatterns[0].Pipeline != ll.Pipeline {
+		t.Fatalf("expected the MIT patterns to use the library pipeline")
+	}
+
+	re, err := GenerateMatchingPatternFromSourceText(mit.PrimaryPatterns[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	nd := normalizer.NormalizationData{
+		OriginalText: "Copyright 2023 Someone\n\n" +
+			"Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated documentation files (the \"Software\"), to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions:\n" +
+			"The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.\n" +
+			"THE SOFTWARE IS PROVIDED \"AS IS\", WITHOUT WARRENTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTIBILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.\n",
+		Pipeline: ll.Pipeline,
+	}
+	if err := nd.NormalizeText(); err != nil {
+		t.Fatal(err)
+	}
+	if !re.MatchString(nd.NormalizedText) {
+		t.Errorf("expected the MIT pattern to match %q", nd.NormalizedText)
+	}
+}
@@ -0,0 +1,285 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package manifest
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Outcome classifies the detected licenses of a package compared with its declared license
+type Outcome string
+
+const (
+	// OutcomeConsistent means every detected license is in the declared license
+	OutcomeConsistent Outcome = "consistent"
+	// OutcomeMorePermissive means licenses were detected which are more permissive than the declared license
+	OutcomeMorePermissive Outcome = "more permissive"
+	// OutcomeMoreRestrictive means licenses were detected which are more restrictive than the declared license (e.g. declared MIT with a GPL LICENSE)
+	OutcomeMoreRestrictive Outcome = "more restrictive"
+	// OutcomeConflicting means licenses were detected which are different, but not more or less restrictive (or the category is not known)
+	OutcomeConflicting Outcome = "conflicting"
+	// OutcomeUnknown means there is no declared license or no detected license to compare
+	OutcomeUnknown Outcome = "unknown"
+)
+
+// License categories from the least to the most restrictive
+const (
+	CategoryPublicDomain    = "public domain"
+	CategoryPermissive      = "permissive"
+	CategoryWeakCopyleft    = "weak copyleft"
+	CategoryStrongCopyleft  = "strong copyleft"
+	CategoryNetworkCopyleft = "network copyleft"
+	CategoryUnknown         = "unknown"
+)
+
+var categoryLevels = map[string]int{
+	CategoryPublicDomain:    0,
+	CategoryPermissive:      1,
+	CategoryWeakCopyleft:    2,
+	CategoryStrongCopyleft:  3,
+	CategoryNetworkCopyleft: 4,
+}
+
+// categoryPatterns are matched against license IDs in order. The first match is the category.
+var categoryPatterns = []struct {
+	re       *regexp.Regexp
+	category string
+}{
+	{regexp.MustCompile(`^(?:AGPL|SSPL|RPL)-`), CategoryNetworkCopyleft},
+	{regexp.MustCompile(`^(?:LGPL|MPL|EPL|CDDL|CPL|CPAL|MS-RL|APSL|ErlPL|SPL|NPL|IPL|CECILL-C|OSL)-|^CC-BY(?:-NC)?-SA-`), CategoryWeakCopyleft},
+	{regexp.MustCompile(`^(?:GPL|EUPL|QPL|CECILL)-|^Sleepycat$`), CategoryStrongCopyleft},
+	{regexp.MustCompile(`^(?:Unlicense|CC0-1\.0|PDDL-1\.0|WTFPL|0BSD|MIT-0)$`), CategoryPublicDomain},
+	{regexp.MustCompile(`^(?:MIT|BSD|Apache|AFL|Artistic-2\.0|BSL-1\.0|CC-BY-\d|ECL|ISC|NCSA|PostgreSQL|PSF|Python|Zlib|X11|Unicode|UPL|W3C|OpenSSL|Ruby|curl|FTL|Libpng|libpng|MS-PL|MulanPSL|BlueOak|Beerware|JSON|ZPL|PHP|OLDAP|Vim|NTP|HPND|ICU|IJG|TCL|bzip2)(?:$|[-.])`), CategoryPermissive},
+}
+
+// Comparison is the declared license of a package compared with the licenses detected in its files
+type Comparison struct {
+	// Declared is the declared license expression
+	Declared string `json:"declared"`
+	// Detected are the license IDs detected in the files of the package
+	Detected []string `json:"detected"`
+	// Undeclared are the detected licenses which are not in the declared license
+	Undeclared []string `json:"undeclared,omitempty"`
+	Outcome    Outcome  `json:"outcome"`
+	// Reason explains the outcome
+	Reason string `json:"reason"`
+}
+
+// CategoryOf returns the category (e.g. permissive or strong copyleft) of a license ID
+func CategoryOf(id string) string {
+	id = strings.TrimSuffix(id, "+")
+	for _, cp := range categoryPatterns {
+		if cp.re.MatchString(id) {
+			return cp.category
+		}
+	}
+	return CategoryUnknown
+}
+
+// baseID is used to compare IDs, so GPL-2.0, GPL-2.0+, GPL-2.0-only, and GPL-2.0-or-later are the same license
+func baseID(id string) string {
+	id = strings.TrimSuffix(id, "+")
+	id = strings.TrimSuffix(id, "-or-later")
+	id = strings.TrimSuffix(id, "-only")
+	return strings.ToLower(id)
+}
+
+// isException is used to ignore detected exceptions (e.g. Classpath-exception-2.0), which modify a license
+func isException(id string) bool {
+	return strings.Contains(strings.ToLower(id), "exception")
+}
+
+// Compare classifies the detected licenses compared with the declared license expression
+func Compare(declared string, detected []string) Comparison {
+	c := Comparison{Declared: declared, Detected: detected}
+	if declared == "" {
+		c.Outcome, c.Reason = OutcomeUnknown, "no declared license"
+		return c
+	}
+
+	declaredIDs := make(map[string]bool)
+	for _, token := range expressionTokens(declared) {
+		declaredIDs[baseID(token)] = true
+	}
+	var compared int
+	for _, id := range detected {
+		if isException(id) {
+			continue
+		}
+		compared++
+		if !declaredIDs[baseID(id)] {
+			c.Undeclared = append(c.Undeclared, id)
+		}
+	}
+	if compared == 0 {
+		c.Outcome, c.Reason = OutcomeUnknown, "no detected license"
+		return c
+	}
+	if len(c.Undeclared) == 0 {
+		c.Outcome, c.Reason = OutcomeConsistent, "the detected licenses are declared"
+		return c
+	}
+
+	declaredLevel, ok := expressionLevel(declared)
+	if !ok {
+		c.Outcome, c.Reason = OutcomeConflicting, fmt.Sprintf("%v were detected and the category of declared %v is unknown", strings.Join(c.Undeclared, ", "), declared)
+		return c
+	}
+
+	detectedLevel := -1
+	mostRestrictive := ""
+	for _, id := range c.Undeclared {
+		level, ok := categoryLevels[CategoryOf(id)]
+		if !ok {
+			c.Outcome, c.Reason = OutcomeConflicting, fmt.Sprintf("%v was detected and its category is unknown", id)
+			return c
+		}
+		if level > detectedLevel {
+			detectedLevel, mostRestrictive = level, id
+		}
+	}
+
+	reason := fmt.Sprintf("%v is %v and declared %v is %v", mostRestrictive, CategoryOf(mostRestrictive), declared, levelCategory(declaredLevel))
+	switch {
+	case detectedLevel > declaredLevel:
+		c.Outcome, c.Reason = OutcomeMoreRestrictive, reason
+	case detectedLevel < declaredLevel:
+		c.Outcome, c.Reason = OutcomeMorePermissive, reason
+	default:
+		c.Outcome, c.Reason = OutcomeConflicting, fmt.Sprintf("%v is a different license than declared %v", strings.Join(c.Undeclared, ", "), declared)
+	}
+	return c
+}
+
+func levelCategory(level int) string {
+	for category, l := range categoryLevels {
+		if l == level {
+			return category
+		}
+	}
+	return CategoryUnknown
+}
+
+// expressionTokens returns the license IDs in an SPDX expression
+func expressionTokens(expression string) []string {
+	var ids []string
+	for _, token := range tokenize(expression) {
+		switch token {
+		case "(", ")", OperatorAND, OperatorOR, "WITH":
+		default:
+			ids = append(ids, token)
+		}
+	}
+	return ids
+}
+
+func tokenize(expression string) []string {
+	var tokens []string
+	for _, word := range strings.Fields(strings.NewReplacer("(", " ( ", ")", " ) ").Replace(expression)) {
+		switch upper := strings.ToUpper(word); upper {
+		case OperatorAND, OperatorOR, "WITH":
+			tokens = append(tokens, upper)
+		default:
+			tokens = append(tokens, word)
+		}
+	}
+	return tokens
+}
+
+// expressionLevel evaluates how restrictive an SPDX expression is. With OR, the least restrictive choice
+// is used. With AND, the most restrictive license is used. Exceptions (WITH) are not considered.
+func expressionLevel(expression string) (int, bool) {
+	p := levelParser{tokens: tokenize(expression)}
+	level, ok := p.or()
+	return level, ok && p.pos == len(p.tokens)
+}
+
+type levelParser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *levelParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *levelParser) or() (int, bool) {
+	level, ok := p.and()
+	for ok && p.peek() == OperatorOR {
+		p.pos++
+		var next int
+		next, ok = p.and()
+		if next < level {
+			level = next
+		}
+	}
+	return level, ok
+}
+
+func (p *levelParser) and() (int, bool) {
+	level, ok := p.atom()
+	for ok && p.peek() == OperatorAND {
+		p.pos++
+		var next int
+		next, ok = p.atom()
+		if next > level {
+			level = next
+		}
+	}
+	return level, ok
+}
+
+func (p *levelParser) atom() (int, bool) {
+	token := p.peek()
+	p.pos++
+	switch token {
+	case "(":
+		level, ok := p.or()
+		if p.peek() != ")" {
+			return level, false
+		}
+		p.pos++
+		return level, ok
+	case "", ")", OperatorAND, OperatorOR, "WITH":
+		return -1, false
+	}
+	if p.peek() == "WITH" {
+		p.pos += 2 // Skip the exception
+	}
+	level, ok := categoryLevels[CategoryOf(token)]
+	return level, ok
+}
+
+// DeclaredExpression combines the declared licenses of the package (with AND, if there is more than one).
+// When the package has caller-supplied declared licenses, the manifests are not used.
+func (p Package) DeclaredExpression() string {
+	var parts []string
+	for _, d := range p.Declared {
+		if p.Supplied && d.File != "" {
+			continue
+		}
+		if d.Expression != "" {
+			parts = appendUnique(parts, d.Expression)
+		}
+	}
+	sort.Strings(parts)
+	if len(parts) > 1 {
+		for i := range parts {
+			if strings.Contains(parts[i], " ") {
+				parts[i] = "(" + parts[i] + ")"
+			}
+		}
+	}
+	return strings.Join(parts, " "+OperatorAND+" ")
+}
+
+// Compare classifies the detected licenses of the package compared with its declared license
+func (p Package) Compare() Comparison {
+	return Compare(p.DeclaredExpression(), p.Detected)
+}
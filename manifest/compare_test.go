@@ -0,0 +1,110 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package manifest
+
+import (
+	"path"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/CycloneDX/license-scanner/identifier"
+)
+
+func TestCompare(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name           string
+		declared       string
+		detected       []string
+		wantOutcome    Outcome
+		wantUndeclared []string
+	}{
+		{name: "declared", declared: "MIT", detected: []string{"MIT"}, wantOutcome: OutcomeConsistent},
+		{name: "one of the choices", declared: "Apache-2.0 OR MIT", detected: []string{"Apache-2.0"}, wantOutcome: OutcomeConsistent},
+		{name: "or later", declared: "GPL-2.0-or-later", detected: []string{"GPL-2.0-only", "GPL-2.0+"}, wantOutcome: OutcomeConsistent},
+		{name: "exception ignored", declared: "GPL-2.0-only", detected: []string{"GPL-2.0-only", "Classpath-exception-2.0"}, wantOutcome: OutcomeConsistent},
+		{name: "MIT with GPL", declared: "MIT", detected: []string{"GPL-3.0-only"}, wantOutcome: OutcomeMoreRestrictive, wantUndeclared: []string{"GPL-3.0-only"}},
+		{name: "least restrictive choice", declared: "MIT OR GPL-2.0-only", detected: []string{"LGPL-2.1-only"}, wantOutcome: OutcomeMoreRestrictive, wantUndeclared: []string{"LGPL-2.1-only"}},
+		{name: "most restrictive of AND", declared: "(MIT AND GPL-3.0-only)", detected: []string{"LGPL-2.1-only"}, wantOutcome: OutcomeMorePermissive, wantUndeclared: []string{"LGPL-2.1-only"}},
+		{name: "GPL with MIT", declared: "GPL-3.0-only", detected: []string{"MIT", "GPL-3.0-only"}, wantOutcome: OutcomeMorePermissive, wantUndeclared: []string{"MIT"}},
+		{name: "same category", declared: "MIT", detected: []string{"BSD-3-Clause"}, wantOutcome: OutcomeConflicting, wantUndeclared: []string{"BSD-3-Clause"}},
+		{name: "unknown detected category", declared: "MIT", detected: []string{"LicenseRef-Proprietary"}, wantOutcome: OutcomeConflicting, wantUndeclared: []string{"LicenseRef-Proprietary"}},
+		{name: "unknown declared category", declared: "LicenseRef-Proprietary", detected: []string{"MIT"}, wantOutcome: OutcomeConflicting, wantUndeclared: []string{"MIT"}},
+		{name: "no declared license", detected: []string{"MIT"}, wantOutcome: OutcomeUnknown},
+		{name: "no detected license", declared: "MIT", wantOutcome: OutcomeUnknown},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := Compare(tt.declared, tt.detected)
+			if got.Outcome != tt.wantOutcome {
+				t.Errorf("Compare() outcome = %q, want %q (%v)", got.Outcome, tt.wantOutcome, got.Reason)
+			}
+			if d := cmp.Diff(tt.wantUndeclared, got.Undeclared); d != "" {
+				t.Errorf("Compare() undeclared (-want, +got): %v", d)
+			}
+		})
+	}
+}
+
+func TestCategoryOf(t *testing.T) {
+	t.Parallel()
+	tests := map[string]string{
+		"MIT":               CategoryPermissive,
+		"Apache-2.0":        CategoryPermissive,
+		"BSD-3-Clause":      CategoryPermissive,
+		"CC0-1.0":           CategoryPublicDomain,
+		"LGPL-2.1-or-later": CategoryWeakCopyleft,
+		"MPL-2.0":           CategoryWeakCopyleft,
+		"GPL-2.0+":          CategoryStrongCopyleft,
+		"AGPL-3.0-only":     CategoryNetworkCopyleft,
+		"Proprietary":       CategoryUnknown,
+	}
+	for id, want := range tests {
+		if got := CategoryOf(id); got != want {
+			t.Errorf("CategoryOf(%q) = %q, want %q", id, got, want)
+		}
+	}
+}
+
+func TestPackagesWithDeclared(t *testing.T) {
+	t.Parallel()
+	licenseLibrary := testLicenseLibrary(t)
+
+	dir := path.Join(testDataDir, "cargo")
+	results, err := identifier.IdentifyLicensesInDirectory(dir, identifier.Options{}, licenseLibrary)
+	if err != nil {
+		t.Fatalf("IdentifyLicensesInDirectory() error = %v", err)
+	}
+
+	packages, err := Packages(results, licenseLibrary)
+	if err != nil {
+		t.Fatalf("Packages() error = %v", err)
+	}
+	if len(packages) != 1 {
+		t.Fatalf("Packages() got %v packages, want 1", len(packages))
+	}
+	// Cargo.toml declares MIT/Apache-2.0, but the COPYING file is GPL
+	if got := packages[0].Compare(); got.Outcome != OutcomeMoreRestrictive {
+		t.Errorf("Compare() outcome = %q, want %q (%v)", got.Outcome, OutcomeMoreRestrictive, got.Reason)
+	}
+
+	// The supplied license is used instead of Cargo.toml
+	packages, err = PackagesWithDeclared(results, map[string]string{dir + "/": "GNU General Public License v2.0 or later"}, licenseLibrary)
+	if err != nil {
+		t.Fatalf("PackagesWithDeclared() error = %v", err)
+	}
+	if len(packages) != 1 || !packages[0].Supplied {
+		t.Fatalf("PackagesWithDeclared() got %+v, want 1 supplied package", packages)
+	}
+	if got := packages[0].DeclaredExpression(); got != "GPL-2.0-or-later" {
+		t.Errorf("DeclaredExpression() = %q, want %q", got, "GPL-2.0-or-later")
+	}
+	if got := packages[0].Compare(); got.Outcome != OutcomeConsistent {
+		t.Errorf("Compare() outcome = %q, want %q (%v)", got.Outcome, OutcomeConsistent, got.Reason)
+	}
+}
@@ -0,0 +1,291 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package manifest
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/CycloneDX/sbom-utility/log"
+
+	"github.com/CycloneDX/license-scanner/identifier"
+	"github.com/CycloneDX/license-scanner/licenses"
+)
+
+var Logger = log.NewLogger(log.INFO)
+
+// Package types (the package manager, using the package URL type names)
+const (
+	TypeNPM      = "npm"
+	TypeMaven    = "maven"
+	TypeGem      = "gem"
+	TypeCargo    = "cargo"
+	TypePyPI     = "pypi"
+	TypeComposer = "composer"
+	TypeNuGet    = "nuget"
+	TypeGolang   = "golang"
+)
+
+// Operators used to combine multiple declared licenses into one expression
+const (
+	OperatorAND = "AND"
+	OperatorOR  = "OR"
+)
+
+// licenseFileRegexp recognizes the license files used as the declared license (e.g. next to go.mod)
+var licenseFileRegexp = regexp.MustCompile(`(?i)^(?:licen[cs]e|copying)(?:[-_.].*)?$`)
+
+// Declared holds the license information declared in a package manifest
+type Declared struct {
+	// File is the path of the manifest
+	File string
+	// Type is the package manager (e.g. npm, maven, pypi)
+	Type    string
+	Name    string
+	Version string
+	// Licenses are the declared license values as written in the manifest
+	Licenses []string
+	// LicenseFiles are files (relative to the manifest) which declare the license, instead of (or in addition to) Licenses
+	LicenseFiles []string
+	// Operator combines multiple Licenses into one expression (e.g. a list of licenses in package.json means OR)
+	Operator string
+	// Expression is the SPDX license expression for the declared licenses. It is only set when all the licenses were resolved.
+	Expression string
+	// IDs are the license IDs resolved from the declared licenses
+	IDs []string
+	// Unresolved are the declared licenses which could not be resolved to a license ID
+	Unresolved []string
+}
+
+// Package holds the declared and detected licenses of a package (a directory containing manifests)
+type Package struct {
+	Dir      string
+	Declared []Declared
+	// Supplied is true when the declared license was supplied by the caller, instead of by the manifests
+	Supplied bool
+	// Detected are the license IDs detected in the files of the package (but not in nested packages)
+	Detected []string
+	// Files are the files of the package in which licenses were detected
+	Files []string
+}
+
+// parser extracts the declared licenses from the contents of a manifest
+type parser func(b []byte) (Declared, error)
+
+// TypeOf returns the package type for a manifest file name, or "" if the file is not a supported manifest
+func TypeOf(path string) string {
+	name := filepath.Base(path)
+	switch {
+	case name == "package.json":
+		return TypeNPM
+	case name == "pom.xml":
+		return TypeMaven
+	case strings.HasSuffix(name, ".gemspec"):
+		return TypeGem
+	case name == "Cargo.toml":
+		return TypeCargo
+	case name == "pyproject.toml", name == "setup.cfg", name == "PKG-INFO", name == "METADATA":
+		return TypePyPI
+	case name == "composer.json":
+		return TypeComposer
+	case strings.HasSuffix(name, ".nuspec"):
+		return TypeNuGet
+	case name == "go.mod":
+		return TypeGolang
+	}
+	return ""
+}
+
+func parserFor(path string) parser {
+	name := filepath.Base(path)
+	switch TypeOf(path) {
+	case TypeNPM:
+		return parsePackageJSON
+	case TypeMaven:
+		return parsePOM
+	case TypeGem:
+		return parseGemspec
+	case TypeCargo:
+		return parseCargoTOML
+	case TypePyPI:
+		switch name {
+		case "pyproject.toml":
+			return parsePyprojectTOML
+		case "setup.cfg":
+			return parseSetupCfg
+		}
+		return parsePythonMetadata
+	case TypeComposer:
+		return parseComposerJSON
+	case TypeNuGet:
+		return parseNuspec
+	case TypeGolang:
+		return parseGoMod
+	}
+	return nil
+}
+
+// ParseFile reads the declared licenses from a manifest. It returns nil if the file is not a supported manifest.
+func ParseFile(path string) (*Declared, error) {
+	p := parserFor(path)
+	if p == nil {
+		return nil, nil
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	d, err := p(b)
+	if err != nil {
+		return nil, err
+	}
+	d.File = path
+	d.Type = TypeOf(path)
+	return &d, nil
+}
+
+// Packages finds the manifests in the results of a directory scan and reports the declared licenses
+// next to the licenses detected in the files of each package. Each file belongs to the package in
+// the nearest directory (at or above the file) with a manifest.
+func Packages(results []identifier.IdentifierResults, licenseLibrary *licenses.LicenseLibrary) ([]Package, error) {
+	return PackagesWithDeclared(results, nil, licenseLibrary)
+}
+
+// PackagesWithDeclared is Packages with caller-supplied declared licenses. The supplied map has the declared
+// license (e.g. an SPDX expression or license name) of a directory, which is then a package even without a
+// manifest. The supplied license is compared with the detected licenses instead of the manifests in the directory.
+// A manifest which cannot be parsed is logged and skipped.
+func PackagesWithDeclared(results []identifier.IdentifierResults, supplied map[string]string, licenseLibrary *licenses.LicenseLibrary) ([]Package, error) {
+	r := resolverFor(licenseLibrary)
+
+	packages := make(map[string]*Package)
+	for dir, license := range supplied {
+		dir = filepath.Clean(dir)
+		packages[dir] = &Package{
+			Dir:      dir,
+			Declared: []Declared{{Licenses: []string{license}, Operator: OperatorAND}},
+			Supplied: true,
+		}
+	}
+	for _, result := range results {
+		d, err := ParseFile(result.File)
+		if err != nil {
+			// A malformed manifest (e.g. a test fixture) does not declare a license for the other packages
+			Logger.Warningf("Skipping manifest %v: %v", result.File, err)
+			continue
+		}
+		if d == nil {
+			continue
+		}
+		dir := filepath.Dir(result.File)
+		p, ok := packages[dir]
+		if !ok {
+			p = &Package{Dir: dir}
+			packages[dir] = p
+		}
+		p.Declared = append(p.Declared, *d)
+	}
+	if len(packages) == 0 {
+		return nil, nil
+	}
+
+	// detectedIn has the license IDs detected in each file
+	detectedIn := make(map[string][]string)
+	for _, result := range results {
+		if len(result.Matches) == 0 || TypeOf(result.File) != "" {
+			// Skip manifests, which are parsed for declared licenses instead
+			continue
+		}
+		ids := sortedIDs(result.Matches)
+		detectedIn[result.File] = ids
+
+		if p := nearestPackage(packages, filepath.Dir(result.File)); p != nil {
+			p.Files = append(p.Files, result.File)
+			for _, id := range ids {
+				p.Detected = appendUnique(p.Detected, id)
+			}
+		}
+	}
+
+	var dirs []string
+	for dir, p := range packages {
+		dirs = append(dirs, dir)
+		sort.Strings(p.Detected)
+		sort.Strings(p.Files)
+		for i := range p.Declared {
+			d := &p.Declared[i]
+			d.Licenses = append(d.Licenses, licensesFromFiles(*d, detectedIn)...)
+			r.resolve(d)
+		}
+	}
+	sort.Strings(dirs)
+
+	var ret []Package
+	for _, dir := range dirs {
+		ret = append(ret, *packages[dir])
+	}
+	return ret, nil
+}
+
+// nearestPackage returns the package in dir or the nearest parent dir
+func nearestPackage(packages map[string]*Package, dir string) *Package {
+	for {
+		if p, ok := packages[dir]; ok {
+			return p
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil
+		}
+		dir = parent
+	}
+}
+
+// licensesFromFiles returns the licenses detected in the declared license files.
+// Go modules do not declare a license, so the license files next to go.mod are used.
+func licensesFromFiles(d Declared, detectedIn map[string][]string) []string {
+	dir := filepath.Dir(d.File)
+	var ids []string
+	if len(d.LicenseFiles) > 0 {
+		for _, f := range d.LicenseFiles {
+			for _, id := range detectedIn[filepath.Join(dir, f)] {
+				ids = appendUnique(ids, id)
+			}
+		}
+	} else if d.Type == TypeGolang {
+		var files []string
+		for f := range detectedIn {
+			if filepath.Dir(f) == dir && licenseFileRegexp.MatchString(filepath.Base(f)) {
+				files = append(files, f)
+			}
+		}
+		sort.Strings(files)
+		for _, f := range files {
+			for _, id := range detectedIn[f] {
+				ids = appendUnique(ids, id)
+			}
+		}
+	}
+	return ids
+}
+
+func sortedIDs(matches map[string][]identifier.Match) []string {
+	var ids []string
+	for id := range matches {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func appendUnique(ss []string, s string) []string {
+	for i := range ss {
+		if ss[i] == s {
+			return ss
+		}
+	}
+	return append(ss, s)
+}
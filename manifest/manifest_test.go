@@ -0,0 +1,229 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package manifest
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+
+	"github.com/CycloneDX/license-scanner/identifier"
+	"github.com/CycloneDX/license-scanner/licenses"
+)
+
+const testDataDir = "../testdata/manifests"
+
+func testLicenseLibrary(t *testing.T) *licenses.LicenseLibrary {
+	t.Helper()
+	licenseLibrary, err := licenses.NewLicenseLibrary(nil)
+	if err != nil {
+		t.Fatalf("NewLicenseLibrary() error = %v", err)
+	}
+	if err := licenseLibrary.AddAll(); err != nil {
+		t.Fatalf("licenseLibrary.AddAll() error = %v", err)
+	}
+	return licenseLibrary
+}
+
+func TestParseFile(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		file string
+		want *Declared
+	}{
+		{file: "npm/package.json", want: &Declared{Type: TypeNPM, Name: "example", Version: "1.0.0", Licenses: []string{"MIT"}, Operator: OperatorOR}},
+		{file: "npm/node_modules/dep/package.json", want: &Declared{Type: TypeNPM, Name: "dep", Version: "2.1.0", Licenses: []string{"Apache-2.0", "MIT"}, Operator: OperatorOR}},
+		{file: "maven/pom.xml", want: &Declared{Type: TypeMaven, Name: "org.example:example", Version: "3.2.1", Licenses: []string{"The Apache License, Version 2.0"}, Operator: OperatorAND}},
+		{file: "gem/example.gemspec", want: &Declared{Type: TypeGem, Name: "example", Version: "0.3.0", Licenses: []string{"MIT", "Ruby"}, Operator: OperatorOR}},
+		{file: "cargo/Cargo.toml", want: &Declared{Type: TypeCargo, Name: "example", Version: "0.1.0", Licenses: []string{"MIT/Apache-2.0"}}},
+		{file: "pypi/pyproject.toml", want: &Declared{Type: TypePyPI, Name: "example", Version: "1.2.3", Licenses: []string{"BSD-3-Clause"}}},
+		{file: "pypi/setup.cfg", want: &Declared{Type: TypePyPI, Name: "example", Version: "1.2.3", Licenses: []string{"MIT License"}, Operator: OperatorAND}},
+		{file: "pypi/PKG-INFO", want: &Declared{Type: TypePyPI, Name: "example", Version: "1.2.3", Licenses: []string{"MIT License"}, LicenseFiles: []string{"LICENSE"}, Operator: OperatorAND}},
+		{file: "composer/composer.json", want: &Declared{Type: TypeComposer, Name: "example/example", Licenses: []string{"LGPL-2.1-only", "GPL-3.0-or-later"}, Operator: OperatorOR}},
+		{file: "nuget/example.nuspec", want: &Declared{Type: TypeNuGet, Name: "Example", Version: "4.5.6", Licenses: []string{"MIT OR Apache-2.0"}}},
+		{file: "golang/go.mod", want: &Declared{Type: TypeGolang, Name: "github.com/example/example", Operator: OperatorAND}},
+		{file: "golang/LICENSE", want: nil},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.file, func(t *testing.T) {
+			t.Parallel()
+			got, err := ParseFile(path.Join(testDataDir, tt.file))
+			if err != nil {
+				t.Fatalf("ParseFile() error = %v", err)
+			}
+			if d := cmp.Diff(tt.want, got, cmpopts.IgnoreFields(Declared{}, "File")); d != "" {
+				t.Errorf("ParseFile() (-want, +got): %v", d)
+			}
+		})
+	}
+}
+
+func Test_parsers(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name  string
+		parse parser
+		input string
+		want  Declared
+	}{
+		{name: "npm see license in", parse: parsePackageJSON, input: `{"license": "SEE LICENSE IN LICENSE.txt"}`, want: Declared{LicenseFiles: []string{"LICENSE.txt"}, Operator: OperatorOR}},
+		{name: "npm legacy object", parse: parsePackageJSON, input: `{"license": {"type": "ISC"}}`, want: Declared{Licenses: []string{"ISC"}, Operator: OperatorOR}},
+		{name: "composer string", parse: parseComposerJSON, input: `{"license": "MIT"}`, want: Declared{Licenses: []string{"MIT"}, Operator: OperatorOR}},
+		{name: "gemspec license string", parse: parseGemspec, input: `s.license = 'Apache-2.0'`, want: Declared{Licenses: []string{"Apache-2.0"}, Operator: OperatorOR}},
+		{name: "gemspec licenses array", parse: parseGemspec, input: `s.licenses = ["MIT", "GPL-2.0"]`, want: Declared{Licenses: []string{"MIT", "GPL-2.0"}, Operator: OperatorOR}},
+		{name: "cargo license file", parse: parseCargoTOML, input: "[package]\nname = \"x\"\nlicense-file = \"LICENSE.md\"\n", want: Declared{Name: "x", LicenseFiles: []string{"LICENSE.md"}}},
+		{name: "poetry", parse: parsePyprojectTOML, input: "[tool.poetry]\nname = \"x\"\nlicense = \"MIT\"\n", want: Declared{Name: "x", Licenses: []string{"MIT"}}},
+		{name: "pyproject license file", parse: parsePyprojectTOML, input: "[project]\nname = \"x\"\nlicense = {file = \"LICENSE\"}\n", want: Declared{Name: "x", LicenseFiles: []string{"LICENSE"}, Operator: OperatorAND}},
+		{name: "metadata expression", parse: parsePythonMetadata, input: "Name: x\nLicense: MIT License\nLicense-Expression: MIT\n", want: Declared{Name: "x", Licenses: []string{"MIT"}}},
+		{name: "nuspec license file", parse: parseNuspec, input: `<package><metadata><license type="file">LICENSE.txt</license></metadata></package>`, want: Declared{LicenseFiles: []string{"LICENSE.txt"}}},
+		{name: "nuspec license url", parse: parseNuspec, input: `<package><metadata><licenseUrl>https://licenses.nuget.org/MIT</licenseUrl></metadata></package>`, want: Declared{Licenses: []string{"MIT"}}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := tt.parse([]byte(tt.input))
+			if err != nil {
+				t.Fatalf("parse error = %v", err)
+			}
+			if d := cmp.Diff(tt.want, got); d != "" {
+				t.Errorf("parse (-want, +got): %v", d)
+			}
+		})
+	}
+}
+
+func TestResolveLicense(t *testing.T) {
+	t.Parallel()
+	licenseLibrary := testLicenseLibrary(t)
+
+	tests := []struct {
+		license        string
+		wantExpression string
+		wantIDs        []string
+		wantUnresolved []string
+	}{
+		{license: "MIT", wantExpression: "MIT", wantIDs: []string{"MIT"}},
+		{license: "mit", wantExpression: "MIT", wantIDs: []string{"MIT"}},
+		{license: "The MIT License", wantExpression: "MIT", wantIDs: []string{"MIT"}},
+		{license: "Apache License, Version 2.0", wantExpression: "Apache-2.0", wantIDs: []string{"Apache-2.0"}},
+		{license: "https://spdx.org/licenses/BSD-2-Clause.html", wantExpression: "BSD-2-Clause", wantIDs: []string{"BSD-2-Clause"}},
+		{license: "http://www.opensource.org/licenses/mit-license.php", wantExpression: "MIT", wantIDs: []string{"MIT"}},
+		{license: "MIT/Apache-2.0", wantExpression: "MIT OR Apache-2.0", wantIDs: []string{"MIT", "Apache-2.0"}},
+		{license: "(mit or apache-2.0) and bsd-3-clause", wantExpression: "(MIT OR Apache-2.0) AND BSD-3-Clause", wantIDs: []string{"MIT", "Apache-2.0", "BSD-3-Clause"}},
+		{license: "GPL-2.0-or-later WITH Classpath-exception-2.0", wantExpression: "GPL-2.0-or-later WITH Classpath-exception-2.0", wantIDs: []string{"GPL-2.0-or-later", "Classpath-exception-2.0"}},
+		{license: "LicenseRef-Proprietary OR MIT", wantExpression: "LicenseRef-Proprietary OR MIT", wantIDs: []string{"LicenseRef-Proprietary", "MIT"}},
+		{license: "MIT OR Do What You Want", wantIDs: []string{"MIT"}, wantUnresolved: []string{"Do What You Want"}},
+		{license: "UNLICENSED", wantUnresolved: []string{"UNLICENSED"}},
+		{license: ""},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.license, func(t *testing.T) {
+			t.Parallel()
+			gotExpression, gotIDs, gotUnresolved := ResolveLicense(tt.license, licenseLibrary)
+			if gotExpression != tt.wantExpression {
+				t.Errorf("ResolveLicense() expression = %q, want %q", gotExpression, tt.wantExpression)
+			}
+			if d := cmp.Diff(tt.wantIDs, gotIDs); d != "" {
+				t.Errorf("ResolveLicense() IDs (-want, +got): %v", d)
+			}
+			if d := cmp.Diff(tt.wantUnresolved, gotUnresolved); d != "" {
+				t.Errorf("ResolveLicense() unresolved (-want, +got): %v", d)
+			}
+		})
+	}
+}
+
+func TestPackages(t *testing.T) {
+	t.Parallel()
+	licenseLibrary := testLicenseLibrary(t)
+
+	results, err := identifier.IdentifyLicensesInDirectory(testDataDir, identifier.Options{}, licenseLibrary)
+	if err != nil {
+		t.Fatalf("IdentifyLicensesInDirectory() error = %v", err)
+	}
+	packages, err := Packages(results, licenseLibrary)
+	if err != nil {
+		t.Fatalf("Packages() error = %v", err)
+	}
+
+	type summary struct {
+		Dir         string
+		Expressions []string
+		Detected    []string
+	}
+	var got []summary
+	for _, p := range packages {
+		s := summary{Dir: p.Dir, Detected: p.Detected}
+		for _, d := range p.Declared {
+			s.Expressions = append(s.Expressions, d.Expression)
+		}
+		got = append(got, s)
+	}
+
+	want := []summary{
+		{Dir: path.Join(testDataDir, "cargo"), Expressions: []string{"MIT OR Apache-2.0"}, Detected: []string{"GPL-2.0-only", "GPL-2.0-or-later"}},
+		{Dir: path.Join(testDataDir, "composer"), Expressions: []string{"LGPL-2.1-only OR GPL-3.0-or-later"}},
+		{Dir: path.Join(testDataDir, "gem"), Expressions: []string{"MIT OR Ruby"}},
+		// The license is declared by the LICENSE file next to go.mod
+		{Dir: path.Join(testDataDir, "golang"), Expressions: []string{"BSD-3-Clause"}, Detected: []string{"BSD-3-Clause"}},
+		{Dir: path.Join(testDataDir, "maven"), Expressions: []string{"Apache-2.0"}},
+		// The nested package's LICENSE is not detected in the parent package
+		{Dir: path.Join(testDataDir, "npm"), Expressions: []string{"MIT"}, Detected: []string{"MIT"}},
+		{Dir: path.Join(testDataDir, "npm/node_modules/dep"), Expressions: []string{"Apache-2.0 OR MIT"}, Detected: []string{"Apache-2.0"}},
+		{Dir: path.Join(testDataDir, "nuget"), Expressions: []string{"MIT OR Apache-2.0"}},
+		// PKG-INFO declares a LICENSE file, which is not there
+		{Dir: path.Join(testDataDir, "pypi"), Expressions: []string{"MIT", "BSD-3-Clause", "MIT"}},
+	}
+	if d := cmp.Diff(want, got); d != "" {
+		t.Errorf("Packages() (-want, +got): %v", d)
+	}
+}
+
+func TestPackages_malformedManifest(t *testing.T) {
+	t.Parallel()
+	licenseLibrary := testLicenseLibrary(t)
+
+	dir := t.TempDir()
+	good := filepath.Join(dir, "package.json")
+	bad := filepath.Join(dir, "test", "fixture", "package.json")
+	if err := os.MkdirAll(filepath.Dir(bad), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(good, []byte(`{"name": "good", "license": "MIT"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(bad, []byte(`{"name": "bad", "license": `), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	results := []identifier.IdentifierResults{{File: bad}, {File: good}}
+	packages, err := Packages(results, licenseLibrary)
+	if err != nil {
+		t.Fatalf("Packages() error = %v", err)
+	}
+	if len(packages) != 1 || packages[0].Dir != dir || len(packages[0].Declared) != 1 || packages[0].Declared[0].Expression != "MIT" {
+		t.Errorf("Packages() = %+v, want only the MIT package in %v", packages, dir)
+	}
+}
+
+func Test_resolverFor(t *testing.T) {
+	t.Parallel()
+	licenseLibrary := testLicenseLibrary(t)
+
+	r := resolverFor(licenseLibrary)
+	if got := resolverFor(licenseLibrary); got != r {
+		t.Errorf("resolverFor() built another resolver for the same license library")
+	}
+	if id := r.ids["mit"]; id != "MIT" {
+		t.Errorf("resolverFor() ids[mit] = %q, want MIT", id)
+	}
+}
@@ -0,0 +1,396 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package manifest
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"regexp"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/ini.v1"
+)
+
+var (
+	// seeLicenseInRegexp is the npm convention for a license in a file (e.g. "SEE LICENSE IN LICENSE.txt")
+	seeLicenseInRegexp = regexp.MustCompile(`(?i)^see licen[cs]e in\s+(.+)$`)
+
+	gemNameRegexp     = regexp.MustCompile(`\.name\s*=\s*["']([^"']+)["']`)
+	gemVersionRegexp  = regexp.MustCompile(`\.version\s*=\s*["']([^"']+)["']`)
+	gemLicensesRegexp = regexp.MustCompile(`\.licen[cs]es?\s*=\s*(.+)`)
+	gemStringRegexp   = regexp.MustCompile(`["']([^"']+)["']`)
+	gemWordsRegexp    = regexp.MustCompile(`%w[\[(]([^\])]*)[\])]`)
+
+	goModuleRegexp = regexp.MustCompile(`(?m)^module\s+"?([^\s"]+)"?`)
+)
+
+// npmLicense is a license (in package.json or composer.json) which is a string or a legacy {"type": ..., "url": ...} object
+type npmLicense struct {
+	Type string `json:"type"`
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+// jsonLicenses reads a license field, which may be a string, an object, or an array of strings and objects
+func jsonLicenses(raw json.RawMessage) []string {
+	if len(raw) == 0 {
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		if s == "" {
+			return nil
+		}
+		return []string{s}
+	}
+	var l npmLicense
+	if err := json.Unmarshal(raw, &l); err == nil {
+		for _, s := range []string{l.Type, l.Name, l.URL} {
+			if s != "" {
+				return []string{s}
+			}
+		}
+		return nil
+	}
+	var list []json.RawMessage
+	if err := json.Unmarshal(raw, &list); err == nil {
+		var ret []string
+		for _, item := range list {
+			ret = append(ret, jsonLicenses(item)...)
+		}
+		return ret
+	}
+	return nil
+}
+
+// withLicenseFiles moves any "SEE LICENSE IN <file>" values to the license files
+func withLicenseFiles(d Declared) Declared {
+	var declared []string
+	for _, l := range d.Licenses {
+		if m := seeLicenseInRegexp.FindStringSubmatch(strings.TrimSpace(l)); m != nil {
+			d.LicenseFiles = append(d.LicenseFiles, strings.TrimSpace(m[1]))
+		} else {
+			declared = append(declared, l)
+		}
+	}
+	d.Licenses = declared
+	return d
+}
+
+// parsePackageJSON reads "license" (or the deprecated "licenses" list, meaning OR)
+func parsePackageJSON(b []byte) (Declared, error) {
+	var pkg struct {
+		Name     string          `json:"name"`
+		Version  string          `json:"version"`
+		License  json.RawMessage `json:"license"`
+		Licenses json.RawMessage `json:"licenses"`
+	}
+	if err := json.Unmarshal(b, &pkg); err != nil {
+		return Declared{}, err
+	}
+	d := Declared{Name: pkg.Name, Version: pkg.Version, Operator: OperatorOR}
+	d.Licenses = jsonLicenses(pkg.License)
+	if len(d.Licenses) == 0 {
+		d.Licenses = jsonLicenses(pkg.Licenses)
+	}
+	return withLicenseFiles(d), nil
+}
+
+// parseComposerJSON reads "license", which may be a list of licenses (meaning OR)
+func parseComposerJSON(b []byte) (Declared, error) {
+	var pkg struct {
+		Name    string          `json:"name"`
+		Version string          `json:"version"`
+		License json.RawMessage `json:"license"`
+	}
+	if err := json.Unmarshal(b, &pkg); err != nil {
+		return Declared{}, err
+	}
+	return Declared{Name: pkg.Name, Version: pkg.Version, Licenses: jsonLicenses(pkg.License), Operator: OperatorOR}, nil
+}
+
+// parsePOM reads the licenses/license names (or URLs). Multiple licenses are combined with AND.
+func parsePOM(b []byte) (Declared, error) {
+	var pom struct {
+		GroupID    string `xml:"groupId"`
+		ArtifactID string `xml:"artifactId"`
+		Version    string `xml:"version"`
+		Parent     struct {
+			GroupID string `xml:"groupId"`
+			Version string `xml:"version"`
+		} `xml:"parent"`
+		Licenses []struct {
+			Name string `xml:"name"`
+			URL  string `xml:"url"`
+		} `xml:"licenses>license"`
+	}
+	if err := xml.Unmarshal(b, &pom); err != nil {
+		return Declared{}, err
+	}
+
+	groupID := pom.GroupID
+	if groupID == "" {
+		groupID = pom.Parent.GroupID
+	}
+	d := Declared{Version: pom.Version, Operator: OperatorAND}
+	if d.Version == "" {
+		d.Version = pom.Parent.Version
+	}
+	d.Name = pom.ArtifactID
+	if groupID != "" {
+		d.Name = groupID + ":" + pom.ArtifactID
+	}
+	for _, l := range pom.Licenses {
+		if name := strings.TrimSpace(l.Name); name != "" {
+			d.Licenses = append(d.Licenses, name)
+		} else if url := strings.TrimSpace(l.URL); url != "" {
+			d.Licenses = append(d.Licenses, url)
+		}
+	}
+	return d, nil
+}
+
+// parseNuspec reads the license expression (or file) or the deprecated licenseUrl
+func parseNuspec(b []byte) (Declared, error) {
+	var nuspec struct {
+		Metadata struct {
+			ID      string `xml:"id"`
+			Version string `xml:"version"`
+			License struct {
+				Type  string `xml:"type,attr"`
+				Value string `xml:",chardata"`
+			} `xml:"license"`
+			LicenseURL string `xml:"licenseUrl"`
+		} `xml:"metadata"`
+	}
+	if err := xml.Unmarshal(b, &nuspec); err != nil {
+		return Declared{}, err
+	}
+
+	m := nuspec.Metadata
+	d := Declared{Name: m.ID, Version: m.Version}
+	value := strings.TrimSpace(m.License.Value)
+	switch {
+	case value != "" && m.License.Type == "file":
+		d.LicenseFiles = []string{value}
+	case value != "":
+		d.Licenses = []string{value}
+	case m.LicenseURL != "":
+		// licenses.nuget.org URLs end with an expression (e.g. https://licenses.nuget.org/MIT)
+		url := strings.TrimSpace(m.LicenseURL)
+		if _, expression, found := strings.Cut(url, "licenses.nuget.org/"); found && expression != "" {
+			url = expression
+		}
+		d.Licenses = []string{url}
+	}
+	return d, nil
+}
+
+// parseGemspec reads spec.license or spec.licenses (a list meaning OR) from the Ruby source
+func parseGemspec(b []byte) (Declared, error) {
+	d := Declared{Operator: OperatorOR}
+	if m := gemNameRegexp.FindSubmatch(b); m != nil {
+		d.Name = string(m[1])
+	}
+	if m := gemVersionRegexp.FindSubmatch(b); m != nil {
+		d.Version = string(m[1])
+	}
+	for _, m := range gemLicensesRegexp.FindAllSubmatch(b, -1) {
+		value := m[1]
+		if words := gemWordsRegexp.FindSubmatch(value); words != nil {
+			d.Licenses = append(d.Licenses, strings.Fields(string(words[1]))...)
+			continue
+		}
+		for _, s := range gemStringRegexp.FindAllSubmatch(value, -1) {
+			d.Licenses = append(d.Licenses, string(s[1]))
+		}
+	}
+	return d, nil
+}
+
+// parseCargoTOML reads package.license (an SPDX expression, possibly using "/" for OR) or package.license-file
+func parseCargoTOML(b []byte) (Declared, error) {
+	var cargo struct {
+		Package struct {
+			Name        string `toml:"name"`
+			Version     string `toml:"version"`
+			License     string `toml:"license"`
+			LicenseFile string `toml:"license-file"`
+		} `toml:"package"`
+	}
+	if err := toml.Unmarshal(b, &cargo); err != nil {
+		return Declared{}, err
+	}
+
+	p := cargo.Package
+	d := Declared{Name: p.Name, Version: p.Version}
+	if p.License != "" {
+		d.Licenses = []string{p.License}
+	}
+	if p.LicenseFile != "" {
+		d.LicenseFiles = []string{p.LicenseFile}
+	}
+	return d, nil
+}
+
+// pythonLicenses uses the license (expression or short text) if it has one, otherwise the "License ::" classifiers (combined with AND)
+func pythonLicenses(d Declared, license string, classifiers []string) Declared {
+	license = strings.TrimSpace(license)
+	if license != "" && license != "UNKNOWN" && !strings.Contains(license, "\n") {
+		d.Licenses = []string{license}
+		return d
+	}
+	d.Operator = OperatorAND
+	for _, c := range classifiers {
+		if l := classifierLicense(c); l != "" {
+			d.Licenses = append(d.Licenses, l)
+		}
+	}
+	return d
+}
+
+// classifierLicense returns the license name from a trove classifier like "License :: OSI Approved :: MIT License"
+func classifierLicense(classifier string) string {
+	parts := strings.Split(classifier, "::")
+	if len(parts) < 2 || strings.TrimSpace(parts[0]) != "License" {
+		return ""
+	}
+	l := strings.TrimSpace(parts[len(parts)-1])
+	if l == "OSI Approved" {
+		return ""
+	}
+	return l
+}
+
+// parsePyprojectTOML reads project.license (a string expression, or a text or file table), or the tool.poetry license
+func parsePyprojectTOML(b []byte) (Declared, error) {
+	var pyproject struct {
+		Project struct {
+			Name        string      `toml:"name"`
+			Version     string      `toml:"version"`
+			License     interface{} `toml:"license"`
+			Classifiers []string    `toml:"classifiers"`
+		} `toml:"project"`
+		Tool struct {
+			Poetry struct {
+				Name        string   `toml:"name"`
+				Version     string   `toml:"version"`
+				License     string   `toml:"license"`
+				Classifiers []string `toml:"classifiers"`
+			} `toml:"poetry"`
+		} `toml:"tool"`
+	}
+	if err := toml.Unmarshal(b, &pyproject); err != nil {
+		return Declared{}, err
+	}
+
+	p := pyproject.Project
+	if p.Name == "" && pyproject.Tool.Poetry.Name != "" {
+		poetry := pyproject.Tool.Poetry
+		return pythonLicenses(Declared{Name: poetry.Name, Version: poetry.Version}, poetry.License, poetry.Classifiers), nil
+	}
+
+	d := Declared{Name: p.Name, Version: p.Version}
+	var license string
+	switch l := p.License.(type) {
+	case string:
+		license = l
+	case map[string]interface{}:
+		if text, ok := l["text"].(string); ok {
+			license = text
+		} else if file, ok := l["file"].(string); ok {
+			d.LicenseFiles = []string{file}
+		}
+	}
+	return pythonLicenses(d, license, p.Classifiers), nil
+}
+
+// parseSetupCfg reads the metadata section's license, license_files, and classifiers
+func parseSetupCfg(b []byte) (Declared, error) {
+	cfg, err := ini.LoadSources(ini.LoadOptions{AllowPythonMultilineValues: true, Insensitive: true}, b)
+	if err != nil {
+		return Declared{}, err
+	}
+	metadata := cfg.Section("metadata")
+	d := Declared{Name: metadata.Key("name").String(), Version: metadata.Key("version").String()}
+	for _, key := range []string{"license_file", "license_files"} {
+		d.LicenseFiles = append(d.LicenseFiles, multiline(metadata.Key(key).String())...)
+	}
+	return pythonLicenses(d, metadata.Key("license").String(), multiline(metadata.Key("classifiers").String())), nil
+}
+
+// multiline splits a setup.cfg list (one per line or comma separated)
+func multiline(value string) []string {
+	var ret []string
+	for _, line := range strings.Split(value, "\n") {
+		for _, s := range strings.Split(line, ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				ret = append(ret, s)
+			}
+		}
+	}
+	return ret
+}
+
+// parsePythonMetadata reads the headers of the core metadata (PKG-INFO or METADATA) until the body
+func parsePythonMetadata(b []byte) (Declared, error) {
+	var d Declared
+	var license, expression string
+	var classifiers []string
+
+	var key, value string
+	set := func() {
+		switch strings.ToLower(key) {
+		case "name":
+			d.Name = value
+		case "version":
+			d.Version = value
+		case "license":
+			license = value
+		case "license-expression":
+			expression = value
+		case "license-file":
+			d.LicenseFiles = append(d.LicenseFiles, value)
+		case "classifier":
+			classifiers = append(classifiers, value)
+		}
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if line == "" {
+			break // The body (description) follows the headers
+		}
+		if line[0] == ' ' || line[0] == '\t' {
+			// Continuation of a multiline value (e.g. a full license text)
+			value += "\n" + strings.TrimSpace(line)
+			continue
+		}
+		if k, v, found := strings.Cut(line, ":"); found {
+			set()
+			key, value = strings.TrimSpace(k), strings.TrimSpace(v)
+		}
+	}
+	set()
+	if err := scanner.Err(); err != nil {
+		return Declared{}, err
+	}
+
+	if expression != "" {
+		license = expression
+	}
+	return pythonLicenses(d, license, classifiers), nil
+}
+
+// parseGoMod reads the module path. The license is declared by the license files next to go.mod.
+func parseGoMod(b []byte) (Declared, error) {
+	d := Declared{Operator: OperatorAND}
+	if m := goModuleRegexp.FindSubmatch(b); m != nil {
+		d.Name = string(m[1])
+	}
+	return d, nil
+}
@@ -0,0 +1,240 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package manifest
+
+import (
+	"path"
+	"strings"
+
+	"github.com/CycloneDX/license-scanner/licenses"
+)
+
+// LicenseRefPrefix is the SPDX prefix for license IDs which are not on the SPDX license list
+const LicenseRefPrefix = "LicenseRef-"
+
+var (
+	// nameReplacer removes punctuation before comparing license names
+	nameReplacer = strings.NewReplacer(",", " ", ";", " ", "(", " ", ")", " ", `"`, " ", "'", " ")
+	// ignoredNameWords are not used to compare names, so "The Apache License, Version 2.0" is the same as "Apache License 2.0"
+	ignoredNameWords = map[string]bool{"the": true, "license": true, "licence": true, "licensed": true, "version": true, "under": true}
+	// urlIDPrefixes are URLs which end with a license ID (e.g. https://spdx.org/licenses/MIT.html)
+	urlIDPrefixes = []string{"spdx.org/licenses/", "opensource.org/licenses/", "licenses.nuget.org/"}
+)
+
+// resolver resolves declared license names, IDs, and URLs to the IDs in the license library
+type resolver struct {
+	ids   map[string]string // lowercase ID -> ID
+	names map[string]string // name key (of names, aliases, and IDs) -> ID, or "" when ambiguous
+	urls  map[string]string // URL key -> ID
+}
+
+// LoadResolver builds the resolver of the license library, which is otherwise built by the first ResolveLicense
+func LoadResolver(licenseLibrary *licenses.LicenseLibrary) {
+	resolverFor(licenseLibrary)
+}
+
+// resolverKey is the key of the resolver cached by the license library
+type resolverKey struct{}
+
+// resolverFor returns the resolver of the license library, which is built once per library
+func resolverFor(licenseLibrary *licenses.LicenseLibrary) *resolver {
+	if licenseLibrary == nil {
+		return newResolver(nil)
+	}
+	return licenseLibrary.Cached(resolverKey{}, func() interface{} {
+		return newResolver(licenseLibrary)
+	}).(*resolver)
+}
+
+func newResolver(licenseLibrary *licenses.LicenseLibrary) *resolver {
+	r := resolver{ids: make(map[string]string), names: make(map[string]string), urls: make(map[string]string)}
+	if licenseLibrary == nil {
+		return &r
+	}
+
+	deprecated := make(map[string]bool)
+	addName := func(name string, id string) {
+		key := nameKey(name)
+		if key == "" {
+			return
+		}
+		existing, ok := r.names[key]
+		switch {
+		case !ok:
+			r.names[key] = id
+		case existing == id || existing == "":
+		case deprecated[existing] && !deprecated[id]:
+			// Prefer the current ID (e.g. GPL-2.0-only) over a deprecated ID with the same name (e.g. GPL-2.0)
+			r.names[key] = id
+		case !deprecated[existing] && deprecated[id]:
+		default:
+			r.names[key] = "" // ambiguous
+		}
+	}
+
+	for id, l := range licenseLibrary.LicenseMap {
+		deprecated[id] = l.LicenseInfo.IsDeprecated
+	}
+	for id, l := range licenseLibrary.LicenseMap {
+		r.ids[strings.ToLower(id)] = id
+		addName(id, id)
+		addName(l.LicenseInfo.Name, id)
+		for _, alias := range l.Aliases {
+			addName(alias, id)
+		}
+		for _, url := range l.URLs {
+			r.urls[urlKey(url)] = id
+		}
+		for _, url := range l.LicenseInfo.URLs {
+			r.urls[urlKey(url)] = id
+		}
+	}
+	return &r
+}
+
+// ResolveLicense normalizes a declared license (an SPDX expression, license name, alias, or URL) to an
+// SPDX expression using the IDs, names, aliases, and URLs in the license library. The expression is
+// only returned when every license in it was resolved. The license library may be nil.
+func ResolveLicense(license string, licenseLibrary *licenses.LicenseLibrary) (expression string, ids []string, unresolved []string) {
+	return resolverFor(licenseLibrary).resolveExpression(license)
+}
+
+// resolve sets the expression, IDs, and unresolved licenses of the declared licenses
+func (r *resolver) resolve(d *Declared) {
+	d.Expression, d.IDs, d.Unresolved = "", nil, nil
+
+	var parts []string
+	for _, license := range d.Licenses {
+		expression, ids, unresolved := r.resolveExpression(license)
+		for _, id := range ids {
+			d.IDs = appendUnique(d.IDs, id)
+		}
+		d.Unresolved = append(d.Unresolved, unresolved...)
+		if expression == "" {
+			continue
+		}
+		if len(d.Licenses) > 1 && strings.Contains(expression, " ") {
+			expression = "(" + expression + ")"
+		}
+		parts = appendUnique(parts, expression)
+	}
+
+	if len(parts) > 0 && len(d.Unresolved) == 0 {
+		operator := d.Operator
+		if operator == "" {
+			operator = OperatorAND
+		}
+		d.Expression = strings.Join(parts, " "+operator+" ")
+	}
+}
+
+// resolveExpression resolves the whole string as one license, or else each license in the expression
+func (r *resolver) resolveExpression(s string) (expression string, ids []string, unresolved []string) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return "", nil, nil
+	}
+	if id, ok := r.term(s); ok {
+		return id, []string{strings.TrimSuffix(id, "+")}, nil
+	}
+
+	// Cargo (and others) used "/" for OR before SPDX expressions
+	if !strings.Contains(s, "://") {
+		s = strings.ReplaceAll(s, "/", " OR ")
+	}
+	s = strings.NewReplacer("(", " ( ", ")", " ) ").Replace(s)
+
+	var tokens []string
+	var term []string
+	addTerm := func() {
+		if len(term) == 0 {
+			return
+		}
+		t := strings.Join(term, " ")
+		term = nil
+		if id, ok := r.term(t); ok {
+			tokens = append(tokens, id)
+			ids = appendUnique(ids, strings.TrimSuffix(id, "+"))
+		} else {
+			tokens = append(tokens, t)
+			unresolved = append(unresolved, t)
+		}
+	}
+	for _, word := range strings.Fields(s) {
+		switch upper := strings.ToUpper(word); upper {
+		case "(", ")", OperatorAND, OperatorOR, "WITH":
+			addTerm()
+			tokens = append(tokens, upper)
+		default:
+			term = append(term, word)
+		}
+	}
+	addTerm()
+
+	if len(unresolved) > 0 {
+		return "", ids, unresolved
+	}
+	expression = strings.Join(tokens, " ")
+	expression = strings.NewReplacer("( ", "(", " )", ")").Replace(expression)
+	return expression, ids, nil
+}
+
+// term resolves one license (without operators) to an ID
+func (r *resolver) term(t string) (string, bool) {
+	t = strings.TrimSpace(t)
+	if t == "" {
+		return "", false
+	}
+	if strings.HasPrefix(t, LicenseRefPrefix) && !strings.ContainsAny(t, " \t") {
+		return t, true
+	}
+	if id, ok := r.ids[strings.ToLower(t)]; ok {
+		return id, true
+	}
+	if strings.Contains(t, "://") || strings.HasPrefix(strings.ToLower(t), "www.") {
+		key := urlKey(t)
+		if id, ok := r.urls[key]; ok {
+			return id, true
+		}
+		for _, prefix := range urlIDPrefixes {
+			if _, after, found := strings.Cut(key, prefix); found {
+				after = strings.TrimSuffix(strings.TrimSuffix(after, path.Ext(after)), "-license")
+				if id, ok := r.ids[after]; ok {
+					return id, true
+				}
+			}
+		}
+		return "", false
+	}
+	if id := r.names[nameKey(t)]; id != "" {
+		return id, true
+	}
+	// The SPDX "+" operator (e.g. LGPL-2.1+) means "or later"
+	if strings.HasSuffix(t, "+") {
+		if id, ok := r.term(strings.TrimSuffix(t, "+")); ok && !strings.HasSuffix(id, "+") {
+			return id + "+", true
+		}
+	}
+	return "", false
+}
+
+// nameKey is used to compare license names and aliases
+func nameKey(name string) string {
+	var words []string
+	for _, w := range strings.Fields(nameReplacer.Replace(strings.ToLower(name))) {
+		if !ignoredNameWords[w] {
+			words = append(words, w)
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// urlKey is used to compare URLs without the scheme, "www.", or a trailing slash
+func urlKey(url string) string {
+	url = strings.ToLower(strings.TrimSpace(url))
+	if _, after, found := strings.Cut(url, "://"); found {
+		url = after
+	}
+	url = strings.TrimPrefix(url, "www.")
+	return strings.TrimSuffix(url, "/")
+}
@@ -0,0 +1,332 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package metrics has the counters and histograms of the license scanner, written in the Prometheus text format.
+// The metrics are registered in the Default registry, which the service exposes on /metrics and the CLI can write
+// as a snapshot after a scan (--metrics).
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ContentType is the media type of the Prometheus text format
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// labelSeparator joins the label values of a child into its key (it is not valid in UTF-8 text)
+const labelSeparator = "\xff"
+
+var (
+	// Default is the registry of the license scanner metrics
+	Default = NewRegistry()
+
+	// DefBuckets are the default histogram buckets (in seconds), for durations of about a millisecond to 10 seconds
+	DefBuckets = []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+	labelValueReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+)
+
+// ExponentialBuckets returns count buckets, starting at start and each factor times the previous one
+func ExponentialBuckets(start, factor float64, count int) []float64 {
+	buckets := make([]float64, count)
+	for i := range buckets {
+		buckets[i] = start
+		start *= factor
+	}
+	return buckets
+}
+
+// Registry is a set of metrics with unique names
+type Registry struct {
+	mu      sync.Mutex
+	metrics map[string]metric
+}
+
+type metric interface {
+	write(w *bufio.Writer)
+}
+
+// NewRegistry returns an empty registry
+func NewRegistry() *Registry {
+	return &Registry{metrics: make(map[string]metric)}
+}
+
+func (r *Registry) register(name string, m metric) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.metrics[name]; ok {
+		panic(fmt.Sprintf("metric %q is already registered", name))
+	}
+	r.metrics[name] = m
+}
+
+// WriteText writes the metrics (sorted by name) in the Prometheus text format
+func (r *Registry) WriteText(w io.Writer) error {
+	r.mu.Lock()
+	names := sortedKeys(r.metrics)
+	metrics := make([]metric, len(names))
+	for i, name := range names {
+		metrics[i] = r.metrics[name]
+	}
+	r.mu.Unlock()
+
+	bw := bufio.NewWriter(w)
+	for _, m := range metrics {
+		m.write(bw)
+	}
+	return bw.Flush()
+}
+
+// Handler returns the HTTP handler which writes the metrics (e.g. for /metrics)
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", ContentType)
+		_ = r.WriteText(w)
+	})
+}
+
+// desc is the name, help, and label names of a metric
+type desc struct {
+	name   string
+	help   string
+	labels []string
+}
+
+func (d desc) key(labelValues []string) string {
+	if len(labelValues) != len(d.labels) {
+		panic(fmt.Sprintf("metric %q has %v labels, not %v", d.name, len(d.labels), len(labelValues)))
+	}
+	return strings.Join(labelValues, labelSeparator)
+}
+
+func (d desc) writeHeader(w *bufio.Writer, typ string) {
+	fmt.Fprintf(w, "# HELP %v %v\n# TYPE %v %v\n", d.name, d.help, d.name, typ)
+}
+
+// writeSample writes a sample of the metric with the labels of the key and an extra label (e.g. the bucket le)
+func (d desc) writeSample(w *bufio.Writer, suffix string, key string, extraName string, extraValue string, value float64) {
+	w.WriteString(d.name + suffix)
+	var pairs []string
+	if len(d.labels) > 0 {
+		for i, v := range strings.Split(key, labelSeparator) {
+			pairs = append(pairs, d.labels[i]+`="`+labelValueReplacer.Replace(v)+`"`)
+		}
+	}
+	if extraName != "" {
+		pairs = append(pairs, extraName+`="`+extraValue+`"`)
+	}
+	if len(pairs) > 0 {
+		w.WriteString("{" + strings.Join(pairs, ",") + "}")
+	}
+	w.WriteString(" " + formatFloat(value) + "\n")
+}
+
+func formatFloat(f float64) string {
+	if math.IsInf(f, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+// Counter is a metric which only increases, with a value for each set of label values
+type Counter struct {
+	desc
+	mu       sync.RWMutex
+	children map[string]*CounterValue
+}
+
+// CounterValue is the value of a counter for one set of label values
+type CounterValue struct {
+	bits uint64
+}
+
+// NewCounter registers a counter in the Default registry
+func NewCounter(name, help string, labels ...string) *Counter {
+	return Default.NewCounter(name, help, labels...)
+}
+
+// NewCounter registers a counter with the label names
+func (r *Registry) NewCounter(name, help string, labels ...string) *Counter {
+	c := &Counter{desc: desc{name: name, help: help, labels: labels}, children: make(map[string]*CounterValue)}
+	r.register(name, c)
+	return c
+}
+
+// With returns the value for the label values (in the order of the label names).
+// Keep the value to avoid the lookup in hot paths.
+func (c *Counter) With(labelValues ...string) *CounterValue {
+	key := c.key(labelValues)
+	c.mu.RLock()
+	v, ok := c.children[key]
+	c.mu.RUnlock()
+	if ok {
+		return v
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if v, ok = c.children[key]; !ok {
+		v = &CounterValue{}
+		c.children[key] = v
+	}
+	return v
+}
+
+// Inc increments a counter without labels
+func (c *Counter) Inc() {
+	c.With().Inc()
+}
+
+// Add adds to a counter without labels
+func (c *Counter) Add(delta float64) {
+	c.With().Add(delta)
+}
+
+// Inc increments the value by 1
+func (v *CounterValue) Inc() {
+	v.Add(1)
+}
+
+// Add adds the delta (which must not be negative) to the value
+func (v *CounterValue) Add(delta float64) {
+	if delta < 0 {
+		panic("a counter can not decrease")
+	}
+	for {
+		old := atomic.LoadUint64(&v.bits)
+		if atomic.CompareAndSwapUint64(&v.bits, old, math.Float64bits(math.Float64frombits(old)+delta)) {
+			return
+		}
+	}
+}
+
+// Value returns the current value
+func (v *CounterValue) Value() float64 {
+	return math.Float64frombits(atomic.LoadUint64(&v.bits))
+}
+
+func (c *Counter) write(w *bufio.Writer) {
+	c.writeHeader(w, "counter")
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	for _, key := range sortedKeys(c.children) {
+		c.writeSample(w, "", key, "", "", c.children[key].Value())
+	}
+}
+
+// Histogram is a metric which counts observations (e.g. durations) in buckets, with a value for each set of label values
+type Histogram struct {
+	desc
+	buckets  []float64
+	mu       sync.RWMutex
+	children map[string]*HistogramValue
+}
+
+// HistogramValue is the value of a histogram for one set of label values
+type HistogramValue struct {
+	mu      sync.Mutex
+	buckets []float64
+	counts  []uint64
+	count   uint64
+	sum     float64
+}
+
+// NewHistogram registers a histogram in the Default registry
+func NewHistogram(name, help string, buckets []float64, labels ...string) *Histogram {
+	return Default.NewHistogram(name, help, buckets, labels...)
+}
+
+// NewHistogram registers a histogram with the bucket upper bounds (in increasing order) and the label names
+func (r *Registry) NewHistogram(name, help string, buckets []float64, labels ...string) *Histogram {
+	h := &Histogram{desc: desc{name: name, help: help, labels: labels}, buckets: buckets, children: make(map[string]*HistogramValue)}
+	r.register(name, h)
+	return h
+}
+
+// With returns the value for the label values (in the order of the label names)
+func (h *Histogram) With(labelValues ...string) *HistogramValue {
+	key := h.key(labelValues)
+	h.mu.RLock()
+	v, ok := h.children[key]
+	h.mu.RUnlock()
+	if ok {
+		return v
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if v, ok = h.children[key]; !ok {
+		v = &HistogramValue{buckets: h.buckets, counts: make([]uint64, len(h.buckets))}
+		h.children[key] = v
+	}
+	return v
+}
+
+// Observe adds an observation to a histogram without labels
+func (h *Histogram) Observe(value float64) {
+	h.With().Observe(value)
+}
+
+// ObserveSince adds the seconds since the start to a histogram without labels
+func (h *Histogram) ObserveSince(start time.Time) {
+	h.With().ObserveSince(start)
+}
+
+// Observe adds an observation
+func (v *HistogramValue) Observe(value float64) {
+	i := sort.SearchFloat64s(v.buckets, value)
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if i < len(v.counts) {
+		v.counts[i]++
+	}
+	v.count++
+	v.sum += value
+}
+
+// ObserveSince adds the seconds since the start as an observation
+func (v *HistogramValue) ObserveSince(start time.Time) {
+	v.Observe(time.Since(start).Seconds())
+}
+
+// Count returns the number of observations
+func (v *HistogramValue) Count() uint64 {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.count
+}
+
+func (h *Histogram) write(w *bufio.Writer) {
+	h.writeHeader(w, "histogram")
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for _, key := range sortedKeys(h.children) {
+		v := h.children[key]
+		v.mu.Lock()
+		var cumulative uint64
+		for i, upper := range h.buckets {
+			cumulative += v.counts[i]
+			h.writeSample(w, "_bucket", key, "le", formatFloat(upper), float64(cumulative))
+		}
+		h.writeSample(w, "_bucket", key, "le", "+Inf", float64(v.count))
+		h.writeSample(w, "_sum", key, "", "", v.sum)
+		h.writeSample(w, "_count", key, "", "", float64(v.count))
+		v.mu.Unlock()
+	}
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
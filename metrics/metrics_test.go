@@ -0,0 +1,95 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package metrics
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRegistry_WriteText(t *testing.T) {
+	t.Parallel()
+	r := NewRegistry()
+	files := r.NewCounter("test_files_total", "Files.")
+	results := r.NewCounter("test_results_total", "Results by kind.", "kind")
+	durations := r.NewHistogram("test_duration_seconds", "Durations.", []float64{.1, 1}, "license")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			files.Inc()
+		}()
+	}
+	wg.Wait()
+	results.With("hit").Add(2)
+	results.With(`say "hi"`).Inc()
+	durations.With("MIT").Observe(.05)
+	durations.With("MIT").Observe(.5)
+	durations.With("MIT").Observe(5)
+
+	want := `# HELP test_duration_seconds Durations.
+# TYPE test_duration_seconds histogram
+test_duration_seconds_bucket{license="MIT",le="0.1"} 1
+test_duration_seconds_bucket{license="MIT",le="1"} 2
+test_duration_seconds_bucket{license="MIT",le="+Inf"} 3
+test_duration_seconds_sum{license="MIT"} 5.55
+test_duration_seconds_count{license="MIT"} 3
+# HELP test_files_total Files.
+# TYPE test_files_total counter
+test_files_total 10
+# HELP test_results_total Results by kind.
+# TYPE test_results_total counter
+test_results_total{kind="hit"} 2
+test_results_total{kind="say \"hi\""} 1
+`
+	var b bytes.Buffer
+	if err := r.WriteText(&b); err != nil {
+		t.Fatal(err)
+	}
+	if d := cmp.Diff(want, b.String()); d != "" {
+		t.Errorf("WriteText() (-want, +got): %v", d)
+	}
+
+	rec := httptest.NewRecorder()
+	r.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Header().Get("Content-Type") != ContentType || rec.Body.String() != want {
+		t.Errorf("Handler() = %q %q", rec.Header().Get("Content-Type"), rec.Body.String())
+	}
+}
+
+func TestRegistry_Panics(t *testing.T) {
+	t.Parallel()
+	r := NewRegistry()
+	c := r.NewCounter("test_total", "Test.", "kind")
+
+	for name, f := range map[string]func(){
+		"duplicate name":  func() { r.NewCounter("test_total", "Test.") },
+		"missing label":   func() { c.Inc() },
+		"negative change": func() { c.With("x").Add(-1) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%v: expected a panic", name)
+				}
+			}()
+			f()
+		}()
+	}
+}
+
+func TestExponentialBuckets(t *testing.T) {
+	t.Parallel()
+	if d := cmp.Diff([]float64{1, 4, 16}, ExponentialBuckets(1, 4, 3)); d != "" {
+		t.Errorf("ExponentialBuckets() (-want, +got): %v", d)
+	}
+}
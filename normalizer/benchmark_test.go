@@ -0,0 +1,62 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package normalizer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// largeText returns about size bytes of license texts (as a large notice file)
+func largeText(b *testing.B, size int) string {
+	templates, err := filepath.Glob("../resources/spdx/default/template/*.template.txt")
+	if err != nil || len(templates) == 0 {
+		b.Fatalf("expected the templates, got %v", err)
+	}
+	var text strings.Builder
+	for i := 0; text.Len() < size; i++ {
+		t, err := os.ReadFile(templates[i%len(templates)])
+		if err != nil {
+			b.Fatal(err)
+		}
+		text.Write(t)
+		text.WriteString("\n\n")
+	}
+	return text.String()
+}
+
+// BenchmarkNormalizeText normalizes a large text as a whole and in chunks. The index-map-B/byte is the size of
+// the index map for each byte of the text (an []int index map used 8 bytes for each byte).
+// Run with: make bench
+func BenchmarkNormalizeText(b *testing.B) {
+	text := largeText(b, 4<<20)
+	for _, bc := range []struct {
+		name string
+		size int
+	}{
+		{"whole", 0},
+		{"chunks", chunkSize},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			b.SetBytes(int64(len(text)))
+			b.ReportAllocs()
+			var n *NormalizationData
+			for i := 0; i < b.N; i++ {
+				n = normalizeWithChunkSize(b, text, bc.size)
+			}
+			b.ReportMetric(float64(n.IndexMap.Size())/float64(len(text)), "index-map-B/byte")
+		})
+	}
+}
+
+func BenchmarkOffsetMap_At(b *testing.B) {
+	n := normalizeWithChunkSize(b, largeText(b, 1<<20), 0)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		n.IndexMap.At(i % n.IndexMap.Len())
+	}
+}
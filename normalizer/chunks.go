@@ -0,0 +1,89 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package normalizer
+
+import (
+	"regexp"
+	"strings"
+)
+
+// chunkSize is the size after which input text is normalized in chunks (of about this size), so that the
+// steps copy and search one chunk at a time instead of the whole text
+var chunkSize = 1 << 20
+
+// chunkBoundaryRE finds the end of a paragraph: a period, a blank line, and an ASCII letter or digit.
+// No step of the default pipeline changes the text across such a boundary, except to replace the whitespace
+// with a single space. (A chunk is split after the first newline.)
+var chunkBoundaryRE = regexp.MustCompile(`\.[ \t\r]*\n(?:[ \t\r]*\n)+[ \t\r]*[a-z0-9]`)
+
+// tagEndRE matches the character before the > at the end of an HTML tag, which is kept by the steps before removeHTMLTags
+var tagEndRE = regexp.MustCompile(`[a-z0-9"'/]`)
+
+// normalizeInChunks is true when the text is normalized in chunks. Only input text (not templates), which is not
+// a source file (the comments are extracted from the whole text), with the default pipeline, and without
+// a trace (of the whole text) is normalized in chunks.
+func (n *NormalizationData) normalizeInChunks(pipeline *Pipeline) bool {
+	return chunkSize > 0 && len(n.NormalizedText) > chunkSize &&
+		!n.IsTemplate && n.Language == nil && pipeline.IsDefault() && !n.Trace && n.OnStep == nil
+}
+
+// normalizeChunks applies the steps of the pipeline to each chunk of the (Unicode normalized) text, and joins the
+// normalized chunks. The normalized text and index map are the same as when the steps are applied to the whole text.
+func (n *NormalizationData) normalizeChunks(pipeline *Pipeline) {
+	text := n.NormalizedText
+	var normalized strings.Builder
+	var indexMap OffsetMap
+	for begin := 0; begin < len(text); {
+		end := nextChunkEnd(text, begin)
+		chunk := NormalizationData{
+			NormalizedText:    text[begin:end],
+			IsTemplate:        n.IsTemplate,
+			Language:          n.Language,
+			Pipeline:          n.Pipeline,
+			moreChunks:        end < len(text),
+			unicodeNormalized: true,
+		}
+		chunk.IndexMap.appendRange(&n.IndexMap, begin, end)
+		for _, step := range pipeline.steps {
+			step.Apply(&chunk)
+		}
+
+		normalized.WriteString(chunk.NormalizedText)
+		indexMap.appendRange(&chunk.IndexMap, 0, chunk.IndexMap.Len())
+		begin = end
+	}
+	n.NormalizedText = normalized.String()
+	n.IndexMap = indexMap
+}
+
+// nextChunkEnd returns the end of the chunk from begin: the first chunk boundary after chunkSize, or the end of the text
+func nextChunkEnd(text string, begin int) int {
+	for from := begin + chunkSize; from < len(text); {
+		loc := chunkBoundaryRE.FindStringIndex(text[from:])
+		if loc == nil {
+			break
+		}
+		end := from + loc[0] + strings.IndexByte(text[from+loc[0]:], '\n') + 1
+		if closesHTMLTags(text[begin:end]) {
+			return end
+		}
+		from += loc[1]
+	}
+	return len(text)
+}
+
+// closesHTMLTags is true when the last < (which may begin an HTML tag) of the chunk is followed by a > which
+// cannot be removed with the comment indicators (after a letter, digit, quote, or slash), so that no HTML tag
+// continues after the chunk
+func closesHTMLTags(chunk string) bool {
+	lt := strings.LastIndexByte(chunk, '<')
+	if lt < 0 {
+		return true
+	}
+	for i := lt + 2; i < len(chunk); i++ {
+		if chunk[i] == '>' && tagEndRE.MatchString(chunk[i-1:i]) {
+			return true
+		}
+	}
+	return false
+}
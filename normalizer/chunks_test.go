@@ -0,0 +1,82 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package normalizer
+
+import (
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// normalizeWithChunkSize normalizes the text as input text, in chunks of the size (0 for the whole text)
+func normalizeWithChunkSize(t testing.TB, text string, size int) *NormalizationData {
+	defer func(previous int) { chunkSize = previous }(chunkSize)
+	chunkSize = size
+	n := NormalizationData{OriginalText: text}
+	if err := n.NormalizeText(); err != nil {
+		t.Fatal(err)
+	}
+	return &n
+}
+
+func TestNormalizationData_NormalizeText_chunks(t *testing.T) {
+	templates, err := filepath.Glob("../resources/spdx/default/template/*.template.txt")
+	if err != nil || len(templates) == 0 {
+		t.Fatalf("expected the templates, got %v", err)
+	}
+	var texts []string
+	for _, template := range templates[:200] {
+		b, err := os.ReadFile(template)
+		if err != nil {
+			t.Fatal(err)
+		}
+		texts = append(texts, string(b))
+	}
+	text := strings.Join(texts, "\n\n")
+
+	whole := normalizeWithChunkSize(t, text, 0)
+	chunked := normalizeWithChunkSize(t, text, 4096)
+	if whole.NormalizedText != chunked.NormalizedText {
+		t.Fatalf("expected the same normalized text in chunks: %v", cmp.Diff(whole.NormalizedText, chunked.NormalizedText))
+	}
+	if d := cmp.Diff(whole.IndexMap.Ints(), chunked.IndexMap.Ints()); d != "" {
+		t.Fatalf("expected the same index map in chunks (-whole, +chunked): %v", d)
+	}
+	if chunks := strings.Count(text, ".\n\n"); chunks < 10 {
+		t.Fatalf("expected the text to be normalized in many chunks, got %v boundaries", chunks)
+	}
+}
+
+func TestNormalizationData_NormalizeText_randomChunks(t *testing.T) {
+	// Tokens which change across lines, with the paragraph endings of chunk boundaries
+	tokens := []string{
+		"word", "per", "cent", "sub", "license", "copyright", "owner", "(c)", "-", "--", "*", "/*", "*/", "//", ">", "<",
+		"<b>", "<!--", "-->", "&amp;", ",", ":", ".", " ", "\t", "\n", "\n\n", ".\n\n", ". \n \n ", " ", "—",
+		"\"", "'", "1.", "a)", "•", "#", "http://x", "â", "^l", "<<", ">>", "/>", "<br/>", "<<var;name=x;original=y;match=.+>>",
+	}
+	random := rand.New(rand.NewSource(1))
+	for i := 0; i < 5000; i++ {
+		var b strings.Builder
+		for j := random.Intn(60); j >= 0; j-- {
+			b.WriteString(tokens[random.Intn(len(tokens))])
+		}
+		text := b.String()
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+		whole := normalizeWithChunkSize(t, text, 0)
+		chunked := normalizeWithChunkSize(t, text, 1)
+		if whole.NormalizedText != chunked.NormalizedText {
+			t.Fatalf("expected the same normalized text of %q in chunks: %v", text, cmp.Diff(whole.NormalizedText, chunked.NormalizedText))
+		}
+		if d := cmp.Diff(whole.IndexMap.Ints(), chunked.IndexMap.Ints()); d != "" {
+			t.Fatalf("expected the same index map of %q in chunks (-whole, +chunked): %v", text, d)
+		}
+	}
+}
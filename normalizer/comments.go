@@ -0,0 +1,316 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package normalizer
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// Language is the comment syntax of a programming language
+type Language struct {
+	Name string
+	// LineComments start comments which end at the end of the line (e.g. // or #)
+	LineComments []string
+	// BlockComments are the start and end of comments which can span lines (e.g. /* and */)
+	BlockComments [][2]string
+	// Docstrings are the quotes of strings which are extracted like comments (e.g. """ in Python)
+	Docstrings []string
+	// Strings are the quotes of string literals, which can contain comment indicators which are not comments.
+	// A string ends at the end of the line (except for a string in backquotes, which can span lines).
+	Strings []string
+	// DoubledQuotes is true when a quote in a string is escaped by doubling it (instead of with a backslash)
+	DoubledQuotes bool
+}
+
+// Languages with the same comment syntax
+var (
+	cLike      = Language{LineComments: []string{"//"}, BlockComments: [][2]string{{"/*", "*/"}}, Strings: []string{`"`, `'`}}
+	hashLike   = Language{LineComments: []string{"#"}, Strings: []string{`"`, `'`}}
+	pythonLike = Language{LineComments: []string{"#"}, Docstrings: []string{`"""`, `'''`}, Strings: []string{`"`, `'`}}
+	lispLike   = Language{LineComments: []string{";"}, Strings: []string{`"`}}
+)
+
+func language(name string, syntax Language) *Language {
+	syntax.Name = name
+	return &syntax
+}
+
+var (
+	languagesByExtension = map[string]*Language{}
+	languagesByFileName  = map[string]*Language{}
+	languagesByShebang   = map[string]*Language{}
+	objectiveC           = language("Objective-C", cLike)
+)
+
+func init() {
+	add := func(l *Language, extensions []string, fileNames []string, interpreters []string) {
+		for _, e := range extensions {
+			languagesByExtension[e] = l
+		}
+		for _, f := range fileNames {
+			languagesByFileName[f] = l
+		}
+		for _, i := range interpreters {
+			languagesByShebang[i] = l
+		}
+	}
+
+	add(language("C", cLike), []string{".c", ".h"}, nil, nil)
+	add(language("C++", cLike), []string{".cc", ".cpp", ".cxx", ".c++", ".hh", ".hpp", ".hxx", ".ino"}, nil, nil)
+	add(language("C#", cLike), []string{".cs"}, nil, nil)
+	add(language("Java", cLike), []string{".java"}, nil, nil)
+	add(language("Kotlin", cLike), []string{".kt", ".kts"}, nil, nil)
+	add(language("Scala", cLike), []string{".scala", ".sc"}, nil, nil)
+	add(language("Groovy", cLike), []string{".groovy", ".gradle"}, nil, nil)
+	add(language("Swift", cLike), []string{".swift"}, nil, nil)
+	add(language("Dart", cLike), []string{".dart"}, nil, nil)
+	add(objectiveC, []string{".mm"}, nil, nil)
+	add(language("CSS", Language{BlockComments: cLike.BlockComments, Strings: cLike.Strings}), []string{".css", ".scss", ".less"}, nil, nil)
+	add(language("Go", Language{LineComments: cLike.LineComments, BlockComments: cLike.BlockComments, Strings: []string{`"`, `'`, "`"}}), []string{".go"}, nil, nil)
+	add(language("JavaScript", Language{LineComments: cLike.LineComments, BlockComments: cLike.BlockComments, Strings: []string{`"`, `'`, "`"}}),
+		[]string{".js", ".mjs", ".cjs", ".jsx", ".ts", ".mts", ".cts", ".tsx"}, nil, []string{"node", "nodejs", "deno"})
+	// A ' in Rust may be a lifetime, so only " starts a string
+	add(language("Rust", Language{LineComments: cLike.LineComments, BlockComments: cLike.BlockComments, Strings: []string{`"`}}), []string{".rs"}, nil, nil)
+	add(language("Zig", Language{LineComments: []string{"//"}, Strings: []string{`"`, `'`}}), []string{".zig"}, nil, nil)
+	add(language("PHP", Language{LineComments: []string{"//", "#"}, BlockComments: cLike.BlockComments, Strings: cLike.Strings}), []string{".php"}, nil, []string{"php"})
+	add(language("HCL", Language{LineComments: []string{"#", "//"}, BlockComments: cLike.BlockComments, Strings: []string{`"`}}), []string{".tf", ".hcl"}, nil, nil)
+
+	add(language("Python", pythonLike), []string{".py", ".pyi", ".pyw", ".bzl", ".bazel"}, []string{"sconstruct", "sconscript"}, []string{"python"})
+	add(language("Shell", hashLike), []string{".sh", ".bash", ".zsh", ".ksh", ".fish"}, nil, []string{"sh", "bash", "zsh", "ksh", "dash", "ash", "fish", "awk", "gawk"})
+	add(language("Perl", hashLike), []string{".pl", ".pm", ".t"}, nil, []string{"perl"})
+	add(language("Ruby", hashLike), []string{".rb", ".rake", ".gemspec"}, []string{"rakefile", "gemfile", "vagrantfile"}, []string{"ruby"})
+	add(language("R", hashLike), []string{".r"}, nil, []string{"rscript"})
+	add(language("Elixir", hashLike), []string{".ex", ".exs"}, nil, []string{"elixir"})
+	add(language("Nim", hashLike), []string{".nim", ".nims"}, nil, nil)
+	add(language("Tcl", hashLike), []string{".tcl"}, nil, []string{"tclsh", "wish"})
+	add(language("YAML", hashLike), []string{".yaml", ".yml"}, nil, nil)
+	add(language("TOML", hashLike), []string{".toml"}, nil, nil)
+	add(language("Make", hashLike), []string{".mk", ".mak"}, []string{"makefile", "gnumakefile"}, []string{"make"})
+	add(language("CMake", hashLike), []string{".cmake"}, []string{"cmakelists.txt"}, nil)
+	add(language("Dockerfile", hashLike), []string{".dockerfile"}, []string{"dockerfile", "containerfile"}, nil)
+	add(language("PowerShell", Language{LineComments: []string{"#"}, BlockComments: [][2]string{{"<#", "#>"}}, Strings: hashLike.Strings}), []string{".ps1", ".psm1", ".psd1"}, nil, []string{"pwsh", "powershell"})
+	add(language("Julia", Language{LineComments: []string{"#"}, BlockComments: [][2]string{{"#=", "=#"}}, Strings: []string{`"`}}), []string{".jl"}, nil, []string{"julia"})
+	add(language("INI", Language{LineComments: []string{";", "#"}}), []string{".ini"}, nil, nil)
+
+	add(language("Lua", Language{LineComments: []string{"--"}, BlockComments: [][2]string{{"--[[", "]]"}}, Strings: []string{`"`, `'`}}), []string{".lua"}, nil, []string{"lua"})
+	add(language("Haskell", Language{LineComments: []string{"--"}, BlockComments: [][2]string{{"{-", "-}"}}, Strings: []string{`"`}}), []string{".hs", ".lhs"}, nil, []string{"runghc", "runhaskell"})
+	add(language("SQL", Language{LineComments: []string{"--"}, BlockComments: cLike.BlockComments, Strings: []string{`'`}, DoubledQuotes: true}), []string{".sql"}, nil, nil)
+	add(language("Ada", Language{LineComments: []string{"--"}, Strings: []string{`"`}, DoubledQuotes: true}), []string{".ada", ".adb", ".ads"}, nil, nil)
+	add(language("VHDL", Language{LineComments: []string{"--"}, Strings: []string{`"`}, DoubledQuotes: true}), []string{".vhd", ".vhdl"}, nil, nil)
+	add(language("Fortran", Language{LineComments: []string{"!"}, Strings: []string{`"`, `'`}, DoubledQuotes: true}), []string{".f90", ".f95", ".f03", ".f08"}, nil, nil)
+	add(language("Visual Basic", Language{LineComments: []string{"'"}, Strings: []string{`"`}, DoubledQuotes: true}), []string{".vb", ".bas", ".vbs"}, nil, nil)
+	add(language("MATLAB", Language{LineComments: []string{"%"}, BlockComments: [][2]string{{"%{", "%}"}}, Strings: []string{`"`}}), []string{".m"}, nil, []string{"octave"})
+	add(language("Erlang", Language{LineComments: []string{"%"}, Strings: []string{`"`}}), []string{".erl", ".hrl"}, nil, []string{"escript"})
+	add(language("TeX", Language{LineComments: []string{"%"}}), []string{".tex", ".sty", ".bib"}, nil, nil)
+	add(language("Lisp", lispLike), []string{".lisp", ".lsp", ".cl", ".el", ".clj", ".cljs", ".cljc", ".edn"}, nil, []string{"sbcl", "clisp"})
+	add(language("Scheme", Language{LineComments: lispLike.LineComments, BlockComments: [][2]string{{"#|", "|#"}}, Strings: lispLike.Strings}), []string{".scm", ".ss", ".rkt"}, nil, []string{"guile", "racket"})
+	add(language("Assembly", Language{LineComments: []string{";"}}), []string{".asm"}, nil, nil)
+	add(language("OCaml", Language{BlockComments: [][2]string{{"(*", "*)"}}, Strings: []string{`"`}}), []string{".ml", ".mli"}, nil, []string{"ocaml"})
+	add(language("F#", Language{LineComments: []string{"//"}, BlockComments: [][2]string{{"(*", "*)"}}, Strings: []string{`"`}}), []string{".fs", ".fsi", ".fsx"}, nil, nil)
+	add(language("Pascal", Language{LineComments: []string{"//"}, BlockComments: [][2]string{{"{", "}"}, {"(*", "*)"}}, Strings: []string{`'`}, DoubledQuotes: true}), []string{".pas", ".dpr"}, nil, nil)
+}
+
+// DetectLanguage returns the language of the source file from its name (e.g. main.go or Makefile), or from
+// the interpreter of a #! line (e.g. #!/usr/bin/env python3). It returns nil for text files (e.g. LICENSE.txt),
+// which are normalized like the templates, and when the language is unknown.
+func DetectLanguage(path string, text string) *Language {
+	name := strings.ToLower(filepath.Base(path))
+	if l, ok := languagesByFileName[name]; ok {
+		return l
+	}
+	if ext := filepath.Ext(name); ext != "" {
+		if ext == ".m" && (strings.Contains(text, "@interface") || strings.Contains(text, "@implementation") || strings.Contains(text, "#import")) {
+			return objectiveC
+		}
+		if l, ok := languagesByExtension[ext]; ok {
+			return l
+		}
+	}
+	return shebangLanguage(text)
+}
+
+// shebangLanguage returns the language of the interpreter in the #! line of the text (nil if none)
+func shebangLanguage(text string) *Language {
+	if !strings.HasPrefix(text, "#!") {
+		return nil
+	}
+	line := text[2:]
+	if i := strings.IndexByte(line, '\n'); i >= 0 {
+		line = line[:i]
+	}
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return nil
+	}
+	interpreter := filepath.Base(fields[0])
+	if interpreter == "env" {
+		interpreter = ""
+		for _, f := range fields[1:] {
+			if !strings.HasPrefix(f, "-") && !strings.Contains(f, "=") {
+				interpreter = filepath.Base(f)
+				break
+			}
+		}
+	}
+	// e.g. python3.11 is python
+	interpreter = strings.ToLower(strings.TrimRight(interpreter, "0123456789."))
+	return languagesByShebang[interpreter]
+}
+
+// commentRegions returns the start and end of the text of each comment in the source, without the comment indicators,
+// and without the decoration of block comments (e.g. the * at the beginning of each line of a /* */ comment).
+// A #! line is not a comment.
+func (l *Language) commentRegions(src string) [][]int {
+	var regions [][]int
+	i := 0
+	if strings.HasPrefix(src, "#!") {
+		i = indexFrom(src, 0, "\n")
+	}
+	for i < len(src) {
+		if q, ok := hasPrefixAt(src, i, l.Docstrings); ok {
+			start := i + len(q)
+			end := indexFrom(src, start, q)
+			regions = append(regions, []int{start, end})
+			i = end + len(q)
+			continue
+		}
+		if block, ok := blockCommentAt(src, i, l.BlockComments); ok {
+			start := i + len(block[0])
+			end := indexFrom(src, start, block[1])
+			regions = append(regions, blockCommentRegions(src, start, end, block)...)
+			i = end + len(block[1])
+			continue
+		}
+		if marker, ok := hasPrefixAt(src, i, l.LineComments); ok {
+			start := i + len(marker)
+			// Skip repeated indicators (e.g. /// or ###) and the ! of doc comments (e.g. //!)
+			for start < len(src) && src[start] == marker[0] {
+				start++
+			}
+			if start < len(src) && src[start] == '!' {
+				start++
+			}
+			end := indexFrom(src, start, "\n")
+			regions = append(regions, []int{start, end})
+			i = end
+			continue
+		}
+		if q, ok := hasPrefixAt(src, i, l.Strings); ok {
+			i = l.stringEnd(src, i+len(q), q)
+			continue
+		}
+		i++
+	}
+	return regions
+}
+
+// blockCommentRegions returns the regions of each line of the block comment between start and end, without
+// the decoration of comments which begin with * (e.g. /** and * at the beginning and end of each line)
+func blockCommentRegions(src string, start int, end int, block [2]string) [][]int {
+	if !strings.HasSuffix(block[0], "*") {
+		return [][]int{{start, end}}
+	}
+	var regions [][]int
+	for lineStart := start; lineStart < end; {
+		lineEnd := indexFrom(src[:end], lineStart, "\n")
+		s, e := lineStart, lineEnd
+		if s > start {
+			for s < e && (src[s] == ' ' || src[s] == '\t') {
+				s++
+			}
+		}
+		for s < e && src[s] == '*' {
+			s++
+		}
+		for e > s && (src[e-1] == ' ' || src[e-1] == '\t' || src[e-1] == '\r') {
+			e--
+		}
+		for e > s && src[e-1] == '*' {
+			e--
+		}
+		regions = append(regions, []int{s, e})
+		lineStart = lineEnd + 1
+	}
+	return regions
+}
+
+// stringEnd returns the index after the end of the string which starts (after the quote) at i
+func (l *Language) stringEnd(src string, i int, quote string) int {
+	for i < len(src) {
+		switch {
+		case strings.HasPrefix(src[i:], quote):
+			if l.DoubledQuotes && strings.HasPrefix(src[i+len(quote):], quote) {
+				i += 2 * len(quote)
+				continue
+			}
+			return i + len(quote)
+		case src[i] == '\\' && !l.DoubledQuotes:
+			i += 2
+		case src[i] == '\n' && quote != "`":
+			return i
+		default:
+			i++
+		}
+	}
+	return len(src)
+}
+
+// hasPrefixAt returns the first of the prefixes at index i of s
+func hasPrefixAt(s string, i int, prefixes []string) (string, bool) {
+	for _, p := range prefixes {
+		if strings.HasPrefix(s[i:], p) {
+			return p, true
+		}
+	}
+	return "", false
+}
+
+// blockCommentAt returns the block comment which starts at index i of s
+func blockCommentAt(s string, i int, blocks [][2]string) ([2]string, bool) {
+	for _, b := range blocks {
+		if strings.HasPrefix(s[i:], b[0]) {
+			return b, true
+		}
+	}
+	return [2]string{}, false
+}
+
+// indexFrom returns the index of substr in s from index i, or the length of s if not found
+func indexFrom(s string, i int, substr string) int {
+	if j := strings.Index(s[i:], substr); j >= 0 {
+		return i + j
+	}
+	return len(s)
+}
+
+// extractComments keeps only the comments of the text of a source file (when the Language is known), so that
+// license headers in source files are matched without the code. The code and the comment indicators are replaced
+// with spaces (keeping the newlines), and the index map still maps the comments to the original text.
+// Then the generic removeCodeCommentIndicators is skipped.
+func (n *NormalizationData) extractComments() {
+	if n.Language == nil || n.IsTemplate {
+		return
+	}
+	n.initialize()
+	n.commentsExtracted = true
+
+	// Replace the text between the comments, except the newlines
+	var allSubmatchIndex [][]int
+	mask := func(start int, end int) {
+		for start < end {
+			lineEnd := indexFrom(n.NormalizedText[:end], start, "\n")
+			if lineEnd > start {
+				allSubmatchIndex = append(allSubmatchIndex, []int{start, lineEnd})
+			}
+			start = lineEnd + 1
+		}
+	}
+	prev := 0
+	for _, region := range n.Language.commentRegions(n.NormalizedText) {
+		mask(prev, region[0])
+		prev = region[1]
+	}
+	mask(prev, len(n.NormalizedText))
+	n.replaceMatchesWithStringAndUpdateIndexMap(allSubmatchIndex, " ")
+}
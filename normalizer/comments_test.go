@@ -0,0 +1,106 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package normalizer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDetectLanguage(t *testing.T) {
+	t.Parallel()
+	tcs := []struct {
+		path string
+		text string
+		want string
+	}{
+		{path: "src/main.go", want: "Go"},
+		{path: "lib/Header.HPP", want: "C++"},
+		{path: "setup.py", want: "Python"},
+		{path: "Makefile", want: "Make"},
+		{path: "CMakeLists.txt", want: "CMake"},
+		{path: "LICENSE.txt", want: ""},
+		{path: "README.md", want: ""},
+		{path: "solver.m", text: "% solve it\nx = 1;", want: "MATLAB"},
+		{path: "View.m", text: "#import <UIKit/UIKit.h>\n@implementation View", want: "Objective-C"},
+		{path: "bin/tool", text: "#!/usr/bin/env -S python3.11 -u\nprint()", want: "Python"},
+		{path: "configure", text: "#! /bin/sh\n", want: "Shell"},
+		{path: "LICENSE", text: "MIT License", want: ""},
+		{path: "data.bin", text: "#!", want: ""},
+	}
+	for _, tc := range tcs {
+		var got string
+		if l := DetectLanguage(tc.path, tc.text); l != nil {
+			got = l.Name
+		}
+		if got != tc.want {
+			t.Errorf("DetectLanguage(%q) = %q, want %q", tc.path, got, tc.want)
+		}
+	}
+}
+
+func TestNormalizationData_NormalizeText_extractComments(t *testing.T) {
+	t.Parallel()
+	tcs := []struct {
+		name     string
+		path     string
+		text     string
+		expected string
+	}{
+		{
+			name:     "c block and line comments",
+			path:     "a.c",
+			text:     "/***************\n * Licensed under\n * the MIT License *\n ***************/\nint x = 1; // see \"LICENSE\"\nchar *s = \"/* not a comment */\";\n",
+			expected: "licensed under the mit license see 'license'",
+		},
+		{
+			name:     "python docstring and comments",
+			path:     "a.py",
+			text:     "#!/usr/bin/python\n'''Licensed under\nthe MIT License'''\nurl = 'http://example.com/#top'  # comment\n",
+			expected: "licensed under the mit license comment",
+		},
+		{
+			name:     "lua block before line comments",
+			path:     "a.lua",
+			text:     "--[[ Licensed under\n  the MIT License ]]\nlocal s = \"--\" --- comment\n",
+			expected: "licensed under the mit license comment",
+		},
+		{
+			name:     "visual basic with doubled quotes",
+			path:     "a.vb",
+			text:     "Dim s = \"It's \"\"quoted\"\"\" ' Licensed under the MIT License\n",
+			expected: "licensed under the mit license",
+		},
+		{
+			name:     "haskell with comment indicators in a string",
+			path:     "a.hs",
+			text:     "{- Licensed\n   under -}\nx = \"{- no -}\" -- the MIT License\n",
+			expected: "licensed under the mit license",
+		},
+		{
+			name:     "text files use the generic comment indicators (like the templates)",
+			path:     "README.md",
+			text:     "> Licensed under\n-- the MIT License\n",
+			expected: "licensed under the mit license",
+		},
+	}
+	for _, tc := range tcs {
+		n := NormalizationData{OriginalText: tc.text, Language: DetectLanguage(tc.path, tc.text)}
+		if err := n.NormalizeText(); err != nil {
+			t.Fatal(err)
+		}
+		if d := cmp.Diff(tc.expected, n.NormalizedText); d != "" {
+			t.Errorf("%v: Didn't get expected Normalized text: (-want, +got): %v", tc.name, d)
+		}
+
+		// The index map maps the normalized comment text to the original text
+		i := strings.Index(n.NormalizedText, "mit license")
+		if begins, ends := n.IndexMap.At(i), n.IndexMap.At(i+len("mit license")-1); tc.text[begins:ends+1] != "MIT License" {
+			t.Errorf("%v: expected the index map of %q to map to the original text, got %q", tc.name, "mit license", tc.text[begins:ends+1])
+		}
+	}
+}
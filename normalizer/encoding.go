@@ -0,0 +1,164 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package normalizer
+
+import (
+	"bytes"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+// Encodings detected by Decode
+const (
+	UTF8        = "UTF-8"
+	UTF16LE     = "UTF-16LE"
+	UTF16BE     = "UTF-16BE"
+	Latin1      = "ISO-8859-1"
+	Windows1252 = "windows-1252"
+)
+
+var (
+	bomUTF8    = []byte{0xEF, 0xBB, 0xBF}
+	bomUTF16LE = []byte{0xFF, 0xFE}
+	bomUTF16BE = []byte{0xFE, 0xFF}
+)
+
+// DecodedText is text decoded to UTF-8 from the original bytes
+type DecodedText struct {
+	Text     string
+	Encoding string
+	// Offsets are the offsets in the original bytes of the character at each byte of the text (nil for UTF-8,
+	// where the offsets are the same)
+	Offsets []int
+	// size is the number of original bytes
+	size int
+}
+
+// Decode detects the encoding of the bytes (from a byte order mark, or the heuristics below) and decodes them to UTF-8.
+//   - UTF-16 without a byte order mark is detected by the NUL bytes (the high bytes of ASCII characters) in every other byte
+//   - Valid UTF-8 (including ASCII) is UTF-8
+//   - Otherwise, the bytes are windows-1252 when they have any bytes in 0x80-0x9F (e.g. curly quotes in windows-1252,
+//     but control characters in ISO-8859-1), and ISO-8859-1 when not
+func Decode(b []byte) DecodedText {
+	switch {
+	case bytes.HasPrefix(b, bomUTF8):
+		return DecodedText{Text: string(b), Encoding: UTF8, size: len(b)}
+	case bytes.HasPrefix(b, bomUTF16LE):
+		return decodeUTF16(b, len(bomUTF16LE), false)
+	case bytes.HasPrefix(b, bomUTF16BE):
+		return decodeUTF16(b, len(bomUTF16BE), true)
+	}
+
+	if bigEndian, ok := detectUTF16(b); ok {
+		return decodeUTF16(b, 0, bigEndian)
+	}
+	if utf8.Valid(b) {
+		return DecodedText{Text: string(b), Encoding: UTF8, size: len(b)}
+	}
+
+	encoding, cm := Latin1, charmap.ISO8859_1
+	for _, c := range b {
+		if c >= 0x80 && c <= 0x9F {
+			encoding, cm = Windows1252, charmap.Windows1252
+			break
+		}
+	}
+	var text strings.Builder
+	offsets := make([]int, 0, len(b))
+	for i, c := range b {
+		n, _ := text.WriteRune(cm.DecodeByte(c))
+		for j := 0; j < n; j++ {
+			offsets = append(offsets, i)
+		}
+	}
+	return DecodedText{Text: text.String(), Encoding: encoding, Offsets: offsets, size: len(b)}
+}
+
+// detectUTF16 is true when most of the even (big endian) or odd (little endian) bytes are NUL,
+// and less than half as many of the others are
+func detectUTF16(b []byte) (bigEndian bool, ok bool) {
+	if len(b) < 2 || len(b)%2 != 0 {
+		return false, false
+	}
+	var even, odd int
+	for i := 0; i < len(b); i += 2 {
+		if b[i] == 0 {
+			even++
+		}
+		if b[i+1] == 0 {
+			odd++
+		}
+	}
+	pairs := len(b) / 2
+	switch {
+	case odd*2 > pairs && even*2 < odd:
+		return false, true
+	case even*2 > pairs && odd*2 < even:
+		return true, true
+	}
+	return false, false
+}
+
+func decodeUTF16(b []byte, start int, bigEndian bool) DecodedText {
+	encoding := UTF16LE
+	if bigEndian {
+		encoding = UTF16BE
+	}
+	unit := func(i int) uint16 {
+		if bigEndian {
+			return uint16(b[i])<<8 | uint16(b[i+1])
+		}
+		return uint16(b[i+1])<<8 | uint16(b[i])
+	}
+
+	var text strings.Builder
+	offsets := make([]int, 0, len(b)-start)
+	for i := start; i+1 < len(b); {
+		r, size := rune(unit(i)), 2
+		if utf16.IsSurrogate(r) {
+			r = utf8.RuneError
+			if i+3 < len(b) {
+				if pair := utf16.DecodeRune(rune(unit(i)), rune(unit(i+2))); pair != utf8.RuneError {
+					r, size = pair, 4
+				}
+			}
+		}
+		n, _ := text.WriteRune(r)
+		for j := 0; j < n; j++ {
+			offsets = append(offsets, i)
+		}
+		i += size
+	}
+	return DecodedText{Text: text.String(), Encoding: encoding, Offsets: offsets, size: len(b)}
+}
+
+// Begin returns the offset in the original bytes of the character at index i of the text
+func (d DecodedText) Begin(i int) int {
+	if d.Offsets == nil || i < 0 {
+		return i
+	}
+	if i >= len(d.Offsets) {
+		return d.size
+	}
+	return d.Offsets[i]
+}
+
+// End returns the offset in the original bytes of the last byte of the character at index i of the text
+// (as the inclusive end of a match)
+func (d DecodedText) End(i int) int {
+	if d.Offsets == nil || i < 0 {
+		return i
+	}
+	if i >= len(d.Offsets) {
+		return d.size - 1
+	}
+	for next := i + 1; next < len(d.Offsets); next++ {
+		if d.Offsets[next] != d.Offsets[i] {
+			return d.Offsets[next] - 1
+		}
+	}
+	return d.size - 1
+}
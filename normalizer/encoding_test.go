@@ -0,0 +1,101 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package normalizer
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDecode(t *testing.T) {
+	t.Parallel()
+	tcs := []struct {
+		name     string
+		b        []byte
+		text     string
+		encoding string
+		// begins and ends are the expected original offsets for each index of the text
+		begins []int
+		ends   []int
+	}{
+		{
+			name:     "ascii",
+			b:        []byte("mit"),
+			text:     "mit",
+			encoding: UTF8,
+			begins:   []int{0, 1, 2},
+			ends:     []int{0, 1, 2},
+		},
+		{
+			name:     "utf-8 with byte order mark",
+			b:        []byte("\xEF\xBB\xBFé"),
+			text:     "\uFEFFé",
+			encoding: UTF8,
+		},
+		{
+			name:     "utf-16le with byte order mark",
+			b:        []byte{0xFF, 0xFE, 'm', 0, 0xE9, 0},
+			text:     "mé",
+			encoding: UTF16LE,
+			begins:   []int{2, 4, 4},
+			ends:     []int{3, 5, 5},
+		},
+		{
+			name:     "utf-16be without byte order mark",
+			b:        []byte{0, 'm', 0, 'i', 0, 't'},
+			text:     "mit",
+			encoding: UTF16BE,
+			begins:   []int{0, 2, 4},
+			ends:     []int{1, 3, 5},
+		},
+		{
+			name:     "utf-16le without byte order mark with a surrogate pair",
+			b:        []byte{'a', 0, 'b', 0, 0x3D, 0xD8, 0x00, 0xDE, 'c', 0, 'd', 0},
+			text:     "ab\U0001F600cd",
+			encoding: UTF16LE,
+			begins:   []int{0, 2, 4, 4, 4, 4, 8, 10},
+			ends:     []int{1, 3, 7, 7, 7, 7, 9, 11},
+		},
+		{
+			name:     "latin-1",
+			b:        []byte("caf\xE9 \xA9"),
+			text:     "café ©",
+			encoding: Latin1,
+			begins:   []int{0, 1, 2, 3, 3, 4, 5, 5},
+			ends:     []int{0, 1, 2, 3, 3, 4, 5, 5},
+		},
+		{
+			name:     "windows-1252",
+			b:        []byte("\x93as is\x94 \x96 caf\xE9"),
+			text:     "“as is” – café",
+			encoding: Windows1252,
+		},
+	}
+
+	for _, tc := range tcs {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			d := Decode(tc.b)
+			if d.Text != tc.text || d.Encoding != tc.encoding {
+				t.Errorf("Decode() = %q %v, want %q %v", d.Text, d.Encoding, tc.text, tc.encoding)
+			}
+			if tc.begins != nil {
+				var begins, ends []int
+				for i := 0; i < len(d.Text); i++ {
+					begins = append(begins, d.Begin(i))
+					ends = append(ends, d.End(i))
+				}
+				if diff := cmp.Diff(tc.begins, begins); diff != "" {
+					t.Errorf("Begin() (-want, +got): %v", diff)
+				}
+				if diff := cmp.Diff(tc.ends, ends); diff != "" {
+					t.Errorf("End() (-want, +got): %v", diff)
+				}
+			}
+		})
+	}
+}
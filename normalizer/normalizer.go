@@ -6,16 +6,21 @@ import (
 	"crypto/md5" //nolint:gosec
 	"crypto/sha256"
 	"crypto/sha512"
-	_ "embed"
 	"encoding/hex"
-	"encoding/json"
 	"fmt"
+	"html"
 	"regexp"
 	"strings"
 	"sync"
+	"time"
+	"unicode/utf8"
 
 	"github.com/CycloneDX/sbom-utility/log"
 	"golang.org/x/exp/slices"
+	"golang.org/x/text/unicode/norm"
+
+	"github.com/CycloneDX/license-scanner/metrics"
+	"github.com/CycloneDX/license-scanner/template"
 )
 
 const (
@@ -48,11 +53,16 @@ const (
 	LeadingWhitespacePattern   = `^\s`
 	MiddleWhitespacePattern    = "(?:\\s|\u00A0|\u2028|\u00B7)+"
 	TrailingWhitespacePattern  = `\s$`
+	HTMLEntityPattern          = `&(?:#x[0-9a-f]{1,6}|#[0-9]{1,7}|[a-z][a-z0-9]{1,31});`
 )
 
 var (
-	Logger         = log.NewLogger(log.INFO)
-	replacementREs = initVarietalWordSpellings()
+	Logger = log.NewLogger(log.INFO)
+
+	normalizedBytes = metrics.NewCounter("license_scanner_normalized_bytes_total",
+		"Bytes of text normalized, for input text or license templates.", "kind")
+	normalizeSeconds = metrics.NewHistogram("license_scanner_normalize_duration_seconds",
+		"Time to normalize a text, for input text or license templates.", metrics.DefBuckets, "kind")
 
 	NoteTagPatternRE                  = regexp.MustCompile(NoteTagPattern)
 	WildcardMatchingPatternRE         = regexp.MustCompile(WildcardMatchingPattern)
@@ -78,42 +88,36 @@ var (
 	OddCharactersPatternRE            = regexp.MustCompile(OddCharactersPattern)
 	CopyrightRE                       = regexp.MustCompile(Copyright)
 	ControlCharactersRE               = regexp.MustCompile(ControlCharacters)
+	HTMLEntityRE                      = regexp.MustCompile(HTMLEntityPattern)
 )
 
-//go:embed replacement_words.json
-var replacementWordsBytes []byte
-
-func initVarietalWordSpellings() map[string]*regexp.Regexp {
-	REMap := make(map[string]*regexp.Regexp)
-
-	// Unmarshal replacement_words.json, compile REs, save in map (just once)
-	replacementWords := make(map[string]string)
-	if err := json.Unmarshal(replacementWordsBytes, &replacementWords); err != nil {
-		panic(err)
-	} else {
-		for replacement, pattern := range replacementWords {
-			re, err := regexp.Compile(pattern)
-			if err != nil {
-				panic(err)
-			}
-			REMap[replacement] = re
-		}
-	}
-
-	return REMap
-}
-
 // NormalizationData holds the input data and its normalized text
 type NormalizationData struct {
 	// original input text
 	OriginalText string
 	// normalized version of the input text
 	NormalizedText string
-	IndexMap       []int
-	CaptureGroups  []*CaptureGroup
-	Hash           Digest
-	IsTemplate     bool
+	// IndexMap maps the offsets of the normalized text to the offsets of the original text
+	IndexMap      OffsetMap
+	CaptureGroups []*CaptureGroup
+	Hash          Digest
+	IsTemplate    bool
+	// Language of a source file, whose comments are extracted (see DetectLanguage). Nil for unknown files.
+	Language *Language
+	// Pipeline of normalization steps (nil for the DefaultPipeline)
+	Pipeline *Pipeline
+	// OnStep is called after each step of the pipeline (e.g. to trace how the text and index map changed)
+	OnStep func(step string, n *NormalizationData)
+	// Trace is set to record the normalized text after each step in TraceSteps (for debugging)
+	Trace          bool
+	TraceSteps     []TraceStep
 	initializeOnce sync.Once
+	// commentsExtracted is set by extractComments, so that removeCodeCommentIndicators is skipped
+	commentsExtracted bool
+	// moreChunks is set for a chunk of the text which is followed by more chunks, so its trailing whitespace is kept
+	moreChunks bool
+	// unicodeNormalized is set for a chunk of the text, which was already Unicode normalized (see initialize)
+	unicodeNormalized bool
 }
 
 type CaptureGroup struct {
@@ -141,7 +145,7 @@ func NewNormalizationData(originalText string, isTemplate bool) *NormalizationDa
 	return &nd
 }
 
-// NormalizeText normalizes the input text
+// NormalizeText normalizes the input text with the steps of the pipeline (after the Unicode normalization)
 func (n *NormalizationData) NormalizeText() error {
 	// verify that the original text is a string with a length of at least one.
 	if len(n.OriginalText) < 1 {
@@ -161,72 +165,32 @@ func (n *NormalizationData) NormalizeText() error {
 		}
 	}
 
-	// remove note tags
-	n.removeNoteTags()
-
-	// replace the wild card matching pattern <<match=.+>> with the range of the permitted number of characters i.e. 1, 144
-	n.limitWildcardMatching()
-
-	// replace optional wild card matching pattern <<match=.*>> with the range of the permitted number of characters i.e. 0, 144
-	n.limitOptionalWildcardMatching()
-
-	// Capture replaceable text sections. (Guideline 2.1.3)
-	n.captureReplaceableTextSections()
-
-	// Replace the optional tags with <<omitable>> and <</omitable>>. (Guideline 2.1.4)
-	n.standardizeOmitableTags()
-
-	// remove odd characters, such as TM, replacement character ?, etc
-	// NOTE! Remove these before any use of regexp2 because rune chars throw off the index map
-	n.removeOddCharacters()
-
-	// Remove code comment indicators. (Guideline 6.1.1)
-	n.removeCodeCommentIndicators()
-
-	// SPDX matching guideline 5.1.2 (Hyphens, Dashes)
-	// Any hyphen, dash, en dash, em dash, or other variations should be considered equivalent
-	n.replaceDashLikeCharacters()
-
-	// SPDX matching guideline 5.1.3 (Quotes)
-	// Any variation of quotations (single, double, curly, etc.) should be considered equivalent
-	n.replaceQuoteLikeCharacters()
-
-	// SPDX matching guideline 13.1.1 - Standardize to http
-	// To avoid a license mismatch due to a difference in a hyperlink protocol (e.g. http vs. https).
-	// HTTP:// and HTTPS:// should be considered equivalent.
-	// Templates may or may not include markup for this guideline.
-	n.standardizeToHTTP()
-
-	// reconnect split words
-	n.reconnectSplitWords()
-
-	// remove horizontal rules
-	n.removeHorizontalRules()
-
-	// SPDX matching guideline 9.1.1 (Copyright Symbol)
-	// By having a rule regarding the use of “©”, “(c)”, or “copyright”,
-	// we avoid the possibility of a mismatch based on these variations.
-	// “©”, “(c)”, or “Copyright” should be considered equivalent and interchangeable.
-	// Templates do not include markup for this guideline so we replace all of these with `copyright`
-	n.replaceCopyrightSymbols()
-
-	// SPDX matching guideline 7.1.1 (Bullets and Numbering)
-	// * must be after replaceCopyrightSymbols() handle overlapping case (c)
-	n.replaceBulletsAndNumbering()
-
-	// Remove HTML tags
-	n.removeHTMLTags()
-
-	// TODO: Decode HTML entities.
-	// n.decodeHTMLEntities()
+	kind := "input"
+	if n.IsTemplate {
+		kind = "template"
+	}
+	normalizedBytes.With(kind).Add(float64(len(n.OriginalText)))
+	defer normalizeSeconds.With(kind).ObserveSince(time.Now())
 
-	// Replace all whitespace with a single space. (Guideline 3.1.1)
-	// To avoid the possibility of a non-match due to different spacing of words, line breaks, or paragraphs.
-	// All whitespace should be treated as a single blank space.
-	n.replaceWhitespace()
+	// Unicode normalization (NFKC), without zero-width characters, and lower case
+	n.initialize()
+	n.trace(normalizeUnicodeStep)
 
-	// Replace varietal word spelling. (Guideline 8.1.1)
-	n.replaceVarietalWordSpellings()
+	pipeline := n.Pipeline
+	if pipeline == nil {
+		pipeline = DefaultPipeline()
+	}
+	if n.normalizeInChunks(pipeline) {
+		n.normalizeChunks(pipeline)
+	} else {
+		for _, step := range pipeline.steps {
+			step.Apply(n)
+			n.trace(step.Name)
+			if n.OnStep != nil {
+				n.OnStep(step.Name, n)
+			}
+		}
+	}
 
 	// Add Hash Digest
 	// calculate MD5 for the normalized text
@@ -246,19 +210,60 @@ func (n *NormalizationData) NormalizeText() error {
 
 // initializeIndexMap initializes the index map based on the normalized text
 func (n *NormalizationData) initialize() {
-	n.initializeOnce.Do(func() {
-		// Convert the input text to all lower case. (Guideline 4.1.1)
-		// Note: Regex patterns also assume ToLower() was already done to avoid needing case-insensitive match.
-		n.NormalizedText = strings.ToLower(n.OriginalText)
-
-		// generate an index map, to map the normalized text indices back to the respective index in the original text
-		// Note: ToLower() must be done before creating IndexMap because some chars change in length.
-		l := len(n.NormalizedText)
-		n.IndexMap = make([]int, l)
-		for i := 0; i < l; i++ {
-			n.IndexMap[i] = i
+	if n.unicodeNormalized {
+		return
+	}
+	n.initializeOnce.Do(n.normalizeUnicode)
+}
+
+// normalizeUnicode sets the normalized text to the original text with Unicode compatibility normalization (NFKC),
+// without zero-width characters, and in lower case. (Guideline 4.1.1)
+// NFKC replaces compatibility characters such as full-width ASCII and ligatures (e.g. \uFB01 is "fi").
+// Note: Regex patterns also assume ToLower() was already done to avoid needing case-insensitive match.
+// The index map maps the normalized text indices back to the respective index in the original text.
+// Each segment of the original text that changes (even in length) is mapped like a replacement, from its first and last bytes.
+func (n *NormalizationData) normalizeUnicode() {
+	var text strings.Builder
+	text.Grow(len(n.OriginalText))
+	var indexMap OffsetMap
+
+	var it norm.Iter
+	it.InitString(norm.NFKC, n.OriginalText)
+	for !it.Done() {
+		// A decomposition can take more than one Next() before the position in the original text moves
+		begin := it.Pos()
+		segment := string(it.Next())
+		for !it.Done() && it.Pos() == begin {
+			segment += string(it.Next())
+		}
+		end := it.Pos()
+		original := n.OriginalText[begin:end]
+		if len(segment) > 1 && strings.HasPrefix(segment, " ") && !strings.HasPrefix(original, " ") {
+			// Keep spacing diacritics (e.g. \u00B4 is not a space and a combining accent), which are like quotes
+			segment = original
+		}
+		segment = strings.ToLower(strings.Map(removeZeroWidth, segment))
+
+		text.WriteString(segment)
+		if segment == original {
+			indexMap.appendCopy(begin, len(segment))
+		} else {
+			// like a replacement: first and last bytes map to the segment, and the bytes in between are -1
+			indexMap.appendReplacement(begin, end-1, len(segment))
 		}
-	})
+	}
+
+	n.NormalizedText = text.String()
+	n.IndexMap = indexMap
+}
+
+// removeZeroWidth drops the zero-width characters, byte order mark, and soft hyphen (for strings.Map)
+func removeZeroWidth(r rune) rune {
+	switch r {
+	case '\u200B', '\u200C', '\u200D', '\u2060', '\uFEFF', '\u00AD':
+		return -1
+	}
+	return r
 }
 
 func (n *NormalizationData) removeNoteTags() {
@@ -323,17 +328,8 @@ func (n *NormalizationData) captureReplaceableTextSections() {
 			regex = regex[1 : len(regex)-1]
 		}
 
-		// If the regex ends in an unprotected greedy quantifier, make it lazy.
-		if strings.HasSuffix(regex, "+") || strings.HasSuffix(regex, "*") || strings.HasSuffix(regex, "}") {
-			regex += "?"
-		}
-
-		// Special case match=".{0,5000}" is often seen in SPDX templates. We want to treat it like the wildcard case
-		// and limit it.
-		// Someday this should probably be smarter about detecting range >= n, but only the 0,5000 check is needed now.
-		if strings.HasSuffix(regex, "{0,5000}?") {
-			regex = regex[0:len(regex)-len("{0,5000}?")] + "{0,1000}?"
-		}
+		// Limit the repetitions (e.g. .{0,5000}), and make a greedy repetition at the end lazy
+		regex = template.PrepareMatch(regex)
 
 		replacementText := "<<" + regex + ">>"
 		replacements = append(replacements, replacementText)
@@ -358,6 +354,10 @@ func (n *NormalizationData) standardizeOmitableTags() {
 }
 
 func (n *NormalizationData) removeCodeCommentIndicators() {
+	if n.commentsExtracted {
+		return
+	}
+
 	// Remove comment block indicators
 	n.regexpReplacePatternAndUpdateIndexMap(CommentBlockOutsideRE, " ")
 	n.regexpReplacePatternAndUpdateIndexMap(CommentBlockInsideRE, " ")
@@ -390,18 +390,20 @@ func (n *NormalizationData) removeHTMLTags() {
 		}
 
 		// move past contents until forbidden char or end char
-		j := i
-		if textLen > j+1 {
-			j++
-			for ; textLen > j && n.NormalizedText[j] != '<' && n.NormalizedText[j] != '>'; j++ {
-			}
+		if textLen <= i+1 { // a < at the end
+			break
+		}
+		j := i + 1
+		for ; textLen > j && n.NormalizedText[j] != '<' && n.NormalizedText[j] != '>'; j++ {
 		}
 
 		if textLen > j && n.NormalizedText[j] == '<' { // forbidden char. This is not the tag you are looking for.
 			if n.NormalizedText[j-1] == '<' { // this was a <<, so move ahead
 				j++
 			}
-			next = j + 1
+			if next = j + 1; next > textLen { // a << at the end
+				break
+			}
 			continue
 		}
 
@@ -421,6 +423,35 @@ func (n *NormalizationData) removeHTMLTags() {
 	n.replaceMatchesWithStringAndUpdateIndexMap(allSubmatchIndex, replacement)
 }
 
+// decodeHTMLEntities replaces named, decimal, and hex HTML entities with the text they stand for.
+// The steps before this one have already normalized the text, so the decoded text gets the same treatment
+// (e.g. &copy; becomes copyright and &ldquo; becomes a quote). Unknown entities are kept.
+func (n *NormalizationData) decodeHTMLEntities() {
+	n.initialize()
+	var allSubmatchIndex [][]int
+	var replacements []string
+	for _, match := range HTMLEntityRE.FindAllStringIndex(n.NormalizedText, -1) {
+		entity := n.NormalizedText[match[0]:match[1]]
+		decoded := html.UnescapeString(entity)
+		// An entity is at most 2 runes. More means only a prefix was decoded (e.g. &notit; is "¬it;").
+		if decoded == entity || utf8.RuneCountInString(decoded) > 2 {
+			continue
+		}
+		allSubmatchIndex = append(allSubmatchIndex, match)
+		replacements = append(replacements, normalizeDecodedText(decoded))
+	}
+	n.replaceMatchesWithStringsAndUpdateIndexMap(allSubmatchIndex, replacements)
+}
+
+// normalizeDecodedText applies the character replacements of the earlier steps to decoded text
+func normalizeDecodedText(s string) string {
+	s = strings.ToLower(s)
+	s = OddCharactersPatternRE.ReplaceAllString(s, " ")
+	s = DashLikeRE.ReplaceAllString(s, "-")
+	s = QuoteLikeRE.ReplaceAllString(s, "'")
+	return CopyrightRE.ReplaceAllString(s, "copyright")
+}
+
 func (n *NormalizationData) replaceDashLikeCharacters() {
 	n.regexpReplacePatternAndUpdateIndexMap(DashLikeRE, "-")
 }
@@ -457,15 +488,17 @@ func (n *NormalizationData) removeHorizontalRules() {
 	n.regexpReplacePatternAndUpdateIndexMap(HorizontalRulePatternRE, " ")
 }
 
-// replaceVarietalWordSpellings will read replacement words JSON file and replace matches
+// replaceVarietalWordSpellings replaces the varietal word spellings (and equivalent words)
 // to create a consistent representation of words with alternate spellings.
 // The replacement may be a word or regexp pattern.
-// The file is only read once.
-// The patterns are only compiled once.
-// The compiled regexp are kept in a map for reuse.
+// The words are the DefaultVarietalWords, unless the pipeline has its own (see Pipeline.WithVarietalWords).
 func (n *NormalizationData) replaceVarietalWordSpellings() {
-	for replacement, re := range replacementREs {
-		n.regexpReplacePatternAndUpdateIndexMap(re, replacement)
+	words := DefaultVarietalWords()
+	if n.Pipeline != nil && n.Pipeline.words != nil {
+		words = n.Pipeline.words
+	}
+	for i, re := range words.res {
+		n.regexpReplacePatternAndUpdateIndexMap(re, words.replacements[i])
 	}
 }
 
@@ -480,7 +513,9 @@ func (n *NormalizationData) removeOddCharacters() {
 func (n *NormalizationData) replaceWhitespace() {
 	n.regexpReplacePatternAndUpdateIndexMap(MiddleWhitespaceRE, " ")
 	n.regexpRemovePatternAndUpdateIndexMap(LeadingWhitespaceRE)
-	n.regexpRemovePatternAndUpdateIndexMap(TrailingWhitespaceRE)
+	if !n.moreChunks {
+		n.regexpRemovePatternAndUpdateIndexMap(TrailingWhitespaceRE)
+	}
 }
 
 func (n *NormalizationData) replaceMatchesWithStringAndUpdateIndexMap(allSubmatchIndex [][]int, replacement string) []string {
@@ -522,14 +557,6 @@ func substr(text string, from int, to int) string {
 	return text[from:to]
 }
 
-// subset will do thing[from:to] or thing[from:] depending on to >= len(thing)
-func subset[T any](thing []T, from int, to int) []T {
-	if to >= len(thing) {
-		return thing[from:]
-	}
-	return thing[from:to]
-}
-
 // replaceMatchesWithStringAndUpdateIndexMap iterates over matches to:
 // * remove or replace the matched text
 // * build an updated index map
@@ -539,8 +566,10 @@ func (n *NormalizationData) replaceMatchesWithStringsAndUpdateIndexMap(allSubmat
 		return
 	}
 
-	var newText string
-	var newIndex []int
+	var newText strings.Builder
+	newText.Grow(len(n.NormalizedText))
+	newIndex := OffsetMap{segments: make([]offsetSegment, 0, len(n.IndexMap.segments)+len(allSubmatchIndex))}
+	indexMapLen := n.IndexMap.Len()
 
 	prev := 0
 	for i, match := range allSubmatchIndex {
@@ -550,9 +579,9 @@ func (n *NormalizationData) replaceMatchesWithStringsAndUpdateIndexMap(allSubmat
 		replacement := replacements[i]
 
 		// copy the text and index map before (and in between) matches
-		if prev < len(n.IndexMap) && firstIndex > prev {
-			newText += substr(n.NormalizedText, prev, firstIndex)
-			newIndex = append(newIndex, subset(n.IndexMap, prev, firstIndex)...)
+		if prev < indexMapLen && firstIndex > prev {
+			newText.WriteString(substr(n.NormalizedText, prev, firstIndex))
+			newIndex.appendRange(&n.IndexMap, prev, firstIndex)
 		}
 
 		replacementLen := len(replacement)
@@ -561,32 +590,29 @@ func (n *NormalizationData) replaceMatchesWithStringsAndUpdateIndexMap(allSubmat
 			// * The first element should be the first index in the replaced section.
 			// * The last element should be the last index in the replaced section. (Unless there is only a single char)
 			// * Middle elements should be -1, for 'replaced'. A match starting/ending on these indices is invalid.
-			replacementIndex := make([]int, replacementLen)
-			for i := 0; i < cap(replacementIndex); i++ {
-				replacementIndex[i] = -1
-			}
-			if firstIndex < len(n.IndexMap) {
-				replacementIndex[0] = n.IndexMap[firstIndex]
+			first, last := -1, -1
+			if firstIndex < indexMapLen {
+				first = n.IndexMap.At(firstIndex)
 			}
-			if replacementLen > 1 && lastIndex-1 < len(n.IndexMap) {
-				replacementIndex[replacementLen-1] = n.IndexMap[lastIndex-1]
+			if replacementLen > 1 && lastIndex > 0 && lastIndex-1 < indexMapLen {
+				last = n.IndexMap.At(lastIndex - 1)
 			}
 
 			// Append the replacement text and indexes
-			newText += replacement
-			newIndex = append(newIndex, replacementIndex...)
+			newText.WriteString(replacement)
+			newIndex.appendReplacement(first, last, replacementLen)
 		}
 
 		prev = lastIndex
 	}
 
 	// Append the remaining text and indexes, if there are more after the last match
-	if prev < len(n.IndexMap) {
-		newText += n.NormalizedText[prev:]
-		newIndex = append(newIndex, n.IndexMap[prev:]...)
+	if prev < indexMapLen {
+		newText.WriteString(n.NormalizedText[prev:])
+		newIndex.appendRange(&n.IndexMap, prev, indexMapLen)
 	}
 
 	// Set the new text and index map
-	n.NormalizedText = newText
+	n.NormalizedText = newText.String()
 	n.IndexMap = newIndex
 }
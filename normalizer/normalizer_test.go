@@ -66,6 +66,15 @@ func TestNormalizationData_NormalizeText(t *testing.T) {
 				NormalizedText: "quoted match test:<<.{0,1000}?>> any5000",
 			},
 		},
+		{
+			name: "quoted match of escaped braces",
+			n: &NormalizationData{
+				OriginalText: `braces test: <<var;name="year";original="[yyyy]";match="\[\]|\{\}">> braces`,
+			},
+			e: &NormalizationData{
+				NormalizedText: `braces test:<<\[\]|\{\}>> braces`,
+			},
+		},
 		{
 			name: "html tags without runes",
 			n: &NormalizationData{
@@ -117,6 +126,90 @@ func TestNormalizationData_NormalizeText(t *testing.T) {
 	}
 }
 
+func TestNormalizationData_NormalizeText_normalizeUnicode(t *testing.T) {
+	tcs := []struct {
+		name string
+		n    *NormalizationData
+		e    *NormalizationData
+	}{
+		{
+			name: "full-width characters",
+			n: &NormalizationData{
+				OriginalText: "\uFF2D\uFF29\uFF34 License",
+			},
+			e: &NormalizationData{
+				NormalizedText: "mit license",
+				IndexMap:       NewOffsetMap([]int{0, 3, 6, 9, 10, 11, 12, 13, 14, 15, 16}),
+			},
+		},
+		{
+			name: "ligatures map the first and last bytes",
+			n: &NormalizationData{
+				OriginalText: "\uFB01les",
+			},
+			e: &NormalizationData{
+				NormalizedText: "files",
+				IndexMap:       NewOffsetMap([]int{0, 2, 3, 4, 5}),
+			},
+		},
+		{
+			name: "zero-width characters, byte order mark, and soft hyphen are removed",
+			n: &NormalizationData{
+				OriginalText: "\uFEFFsoft\u00ADware\u200B is\u2060 free",
+			},
+			e: &NormalizationData{
+				NormalizedText: "software is free",
+				IndexMap:       NewOffsetMap([]int{3, 4, 5, 6, 9, 10, 11, 12, 16, 17, 18, 22, 23, 24, 25, 26}),
+			},
+		},
+		{
+			name: "lower case that changes the length",
+			n: &NormalizationData{
+				OriginalText: "\u212A and K",
+			},
+			e: &NormalizationData{
+				NormalizedText: "k and k",
+				IndexMap:       NewOffsetMap([]int{0, 3, 4, 5, 6, 7, 8}),
+			},
+		},
+		{
+			name: "spacing diacritics are kept",
+			n: &NormalizationData{
+				OriginalText: "\u00B4as is\u00B4",
+			},
+			e: &NormalizationData{
+				NormalizedText: "'as is'",
+			},
+		},
+		{
+			name: "non-breaking hyphen and ideographic space",
+			n: &NormalizationData{
+				OriginalText: "non\u2011exclusive\u3000license",
+			},
+			e: &NormalizationData{
+				NormalizedText: "non-exclusive license",
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.n.NormalizeText()
+			if err != nil {
+				t.Fatalf("Normalize error: %v", err)
+			}
+			if d := cmp.Diff(tc.e.NormalizedText, tc.n.NormalizedText); d != "" {
+				t.Errorf("Didn't get expected Normalized text: %s", fmt.Sprintf("(-want, +got): %s", d))
+			}
+			if tc.e.IndexMap.Len() > 0 {
+				if d := cmp.Diff(tc.e.IndexMap.Ints(), tc.n.IndexMap.Ints()); d != "" {
+					t.Errorf("Didn't get expected IndexMap: %s", fmt.Sprintf("(-want, +got): %s", d))
+				}
+			}
+		})
+	}
+}
+
 func TestNormalizationData_NormalizeText_removeNoteTag(t *testing.T) {
 	tcs := []struct {
 		name string
@@ -262,7 +355,7 @@ func TestNormalizationData_NormalizeText_removeCodeCommentIndicators(t *testing.
 			},
 			e: &NormalizationData{
 				NormalizedText: "wide block dense block comment html comment python",
-				IndexMap:       []int{7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 25, 26, 27, 28, 29, 30, 31, 32, 33, 34, 35, 36, 43, 44, 45, 46, 47, 48, 49, 50, 56, 57, 58, 59, 60, 61, 62, 63, 64, 65, 66, 67, 68, 75, 76, 77, 78, 79, 80},
+				IndexMap:       NewOffsetMap([]int{7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 25, 26, 27, 28, 29, 30, 31, 32, 33, 34, 35, 36, 43, 44, 45, 46, 47, 48, 49, 50, 56, 57, 58, 59, 60, 61, 62, 63, 64, 65, 66, 67, 68, 75, 76, 77, 78, 79, 80}),
 			},
 		},
 		{
@@ -272,7 +365,7 @@ func TestNormalizationData_NormalizeText_removeCodeCommentIndicators(t *testing.
 			},
 			e: &NormalizationData{
 				NormalizedText: "comment comment comment comment",
-				IndexMap:       []int{7, 8, 9, 10, 11, 12, 13, 14, 22, 23, 24, 25, 26, 27, 28, 29, 36, 37, 38, 39, 40, 41, 42, 43, 49, 50, 51, 52, 53, 54, 55},
+				IndexMap:       NewOffsetMap([]int{7, 8, 9, 10, 11, 12, 13, 14, 22, 23, 24, 25, 26, 27, 28, 29, 36, 37, 38, 39, 40, 41, 42, 43, 49, 50, 51, 52, 53, 54, 55}),
 			},
 		},
 		{
@@ -282,7 +375,7 @@ func TestNormalizationData_NormalizeText_removeCodeCommentIndicators(t *testing.
 			},
 			e: &NormalizationData{
 				NormalizedText: "a b c d e f g",
-				IndexMap:       []int{3, 4, 14, 15, 22, 23, 26, 27, 32, 33, 41, 42, 48},
+				IndexMap:       NewOffsetMap([]int{3, 4, 14, 15, 22, 23, 26, 27, 32, 33, 41, 42, 48}),
 			},
 		},
 		{
@@ -292,7 +385,7 @@ func TestNormalizationData_NormalizeText_removeCodeCommentIndicators(t *testing.
 			},
 			e: &NormalizationData{
 				NormalizedText: "line comment // test test2 // test3",
-				IndexMap:       []int{5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23, 24, 25, 30, 31, 32, 33, 34, 35, 36, 37, 38, 39, 40, 41, 42, 43},
+				IndexMap:       NewOffsetMap([]int{5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23, 24, 25, 30, 31, 32, 33, 34, 35, 36, 37, 38, 39, 40, 41, 42, 43}),
 			},
 		},
 		{
@@ -302,7 +395,7 @@ func TestNormalizationData_NormalizeText_removeCodeCommentIndicators(t *testing.
 			},
 			e: &NormalizationData{
 				NormalizedText: "this is a block comment",
-				IndexMap:       []int{6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23, 24, 25, 26, 27, 28},
+				IndexMap:       NewOffsetMap([]int{6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23, 24, 25, 26, 27, 28}),
 			},
 		},
 		{
@@ -312,7 +405,7 @@ func TestNormalizationData_NormalizeText_removeCodeCommentIndicators(t *testing.
 			},
 			e: &NormalizationData{
 				NormalizedText: "this is /* not */ recognized (start or end)",
-				IndexMap:       []int{2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23, 24, 25, 26, 27, 28, 29, 30, 31, 32, 33, 34, 35, 36, 37, 38, 39, 40, 41, 42, 43, 44}, // etc truncated
+				IndexMap:       NewOffsetMap([]int{2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23, 24, 25, 26, 27, 28, 29, 30, 31, 32, 33, 34, 35, 36, 37, 38, 39, 40, 41, 42, 43, 44}), // etc truncated
 			},
 		},
 		{
@@ -322,7 +415,7 @@ func TestNormalizationData_NormalizeText_removeCodeCommentIndicators(t *testing.
 			},
 			e: &NormalizationData{
 				NormalizedText: "part of a comment",
-				IndexMap:       []int{3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19},
+				IndexMap:       NewOffsetMap([]int{3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19}),
 			},
 		},
 		{
@@ -332,7 +425,7 @@ func TestNormalizationData_NormalizeText_removeCodeCommentIndicators(t *testing.
 			},
 			e: &NormalizationData{
 				NormalizedText: "not sure why but remove this",
-				IndexMap:       []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23, 24, 25, 26, 27}, // etc truncated
+				IndexMap:       NewOffsetMap([]int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23, 24, 25, 26, 27}), // etc truncated
 			},
 		},
 		{
@@ -342,7 +435,7 @@ func TestNormalizationData_NormalizeText_removeCodeCommentIndicators(t *testing.
 			},
 			e: &NormalizationData{
 				NormalizedText: "part of a comment",
-				IndexMap:       []int{3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19},
+				IndexMap:       NewOffsetMap([]int{3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19}),
 			},
 		},
 		{
@@ -352,7 +445,7 @@ func TestNormalizationData_NormalizeText_removeCodeCommentIndicators(t *testing.
 			},
 			e: &NormalizationData{
 				NormalizedText: "but not removing this one#",
-				IndexMap:       []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23, 24, 25},
+				IndexMap:       NewOffsetMap([]int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23, 24, 25}),
 			},
 		},
 	}
@@ -365,7 +458,7 @@ func TestNormalizationData_NormalizeText_removeCodeCommentIndicators(t *testing.
 			if d := cmp.Diff(tc.e.NormalizedText, tc.n.NormalizedText); d != "" {
 				t.Errorf("Didn't get expected Normalized text: %s", fmt.Sprintf("(-want, +got): %s", d))
 			}
-			if d := cmp.Diff(tc.e.IndexMap, tc.n.IndexMap); d != "" {
+			if d := cmp.Diff(tc.e.IndexMap.Ints(), tc.n.IndexMap.Ints()); d != "" {
 				t.Errorf("Didn't get expected IndexMap: %s", fmt.Sprintf("(-want, +got): %s", d))
 			}
 		})
@@ -439,6 +532,81 @@ func TestNormalizationData_NormalizeText_removeHTMLTags(t *testing.T) {
 	}
 }
 
+func TestNormalizationData_NormalizeText_decodeHTMLEntities(t *testing.T) {
+	tcs := []struct {
+		name string
+		n    *NormalizationData
+		e    *NormalizationData
+	}{
+		{
+			name: "named entities",
+			n: &NormalizationData{
+				OriginalText: "a &amp; b &lt;c&gt;",
+			},
+			e: &NormalizationData{
+				NormalizedText: "a & b <c>",
+				IndexMap:       NewOffsetMap([]int{0, 1, 2, 7, 8, 9, 10, 14, 15}),
+			},
+		},
+		{
+			name: "decimal and hex entities with the earlier character replacements",
+			n: &NormalizationData{
+				OriginalText: "&#169; &#x201C;x&#8221; &#8212;",
+			},
+			e: &NormalizationData{
+				NormalizedText: "copyright 'x' -",
+				IndexMap:       NewOffsetMap([]int{0, -1, -1, -1, -1, -1, -1, -1, 5, 6, 7, 15, 16, 23, 24}),
+			},
+		},
+		{
+			name: "multi-byte replacement maps its first and last bytes to the entity",
+			n: &NormalizationData{
+				OriginalText: "caf&eacute;",
+			},
+			e: &NormalizationData{
+				NormalizedText: "café",
+				IndexMap:       NewOffsetMap([]int{0, 1, 2, 3, 10}),
+			},
+		},
+		{
+			name: "unknown and partial entities are kept",
+			n: &NormalizationData{
+				OriginalText: "&unknown; &notit; & &amp",
+			},
+			e: &NormalizationData{
+				NormalizedText: "&unknown; &notit; & &amp",
+			},
+		},
+		{
+			name: "non-breaking space is whitespace",
+			n: &NormalizationData{
+				OriginalText: "a&nbsp;&nbsp;b",
+			},
+			e: &NormalizationData{
+				NormalizedText: "a b",
+				IndexMap:       NewOffsetMap([]int{0, 1, 13}),
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.n.NormalizeText()
+			if err != nil {
+				t.Fatalf("Normalize error: %v", err)
+			}
+			if d := cmp.Diff(tc.e.NormalizedText, tc.n.NormalizedText); d != "" {
+				t.Errorf("Didn't get expected Normalized text: %s", fmt.Sprintf("(-want, +got): %s", d))
+			}
+			if tc.e.IndexMap.Len() > 0 {
+				if d := cmp.Diff(tc.e.IndexMap.Ints(), tc.n.IndexMap.Ints()); d != "" {
+					t.Errorf("Didn't get expected IndexMap: %s", fmt.Sprintf("(-want, +got): %s", d))
+				}
+			}
+		})
+	}
+}
+
 func TestNormalizationData_NormalizeText_replaceDashLikeCharacters(t *testing.T) {
 	tcs := []struct {
 		name string
@@ -0,0 +1,168 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package normalizer
+
+import (
+	"sort"
+	"unsafe"
+)
+
+// OffsetMap maps the byte offsets of the normalized text to the byte offsets of the original text.
+// Instead of one int per byte, it is stored as segments of the normalized text:
+//   - a run of bytes copied from the original text maps to consecutive original offsets
+//   - a replacement maps its first and last bytes to the first and last bytes of the replaced text,
+//     and the bytes in between to -1 (a match starting/ending on these offsets is invalid)
+//
+// Most steps only change a few bytes of each line, so a map has far fewer segments than bytes.
+type OffsetMap struct {
+	segments []offsetSegment
+	length   int
+}
+
+type offsetSegment struct {
+	// begin is the offset of the segment in the normalized text
+	begin int
+	// original is the original offset of the first byte (-1 if none)
+	original int
+	// last is the original offset of the last byte of a replacement (-1 if none)
+	last int
+	// replacement is set for a replacement, and not for a run of bytes copied from the original text
+	replacement bool
+}
+
+// NewOffsetMap returns the offset map of the original offsets (one for each byte of the normalized text)
+func NewOffsetMap(offsets []int) OffsetMap {
+	var m OffsetMap
+	for _, o := range offsets {
+		m.appendReplacement(o, o, 1)
+	}
+	return m
+}
+
+// Len returns the length of the normalized text
+func (m *OffsetMap) Len() int {
+	return m.length
+}
+
+// At returns the original offset of the byte at offset i of the normalized text, or -1 for
+// the bytes in the middle of a replacement
+func (m *OffsetMap) At(i int) int {
+	if i < 0 || i >= m.length {
+		panic("normalizer: offset out of range")
+	}
+	s := sort.Search(len(m.segments), func(j int) bool { return m.segments[j].begin > i }) - 1
+	return m.segments[s].at(i-m.segments[s].begin, m.segmentLen(s))
+}
+
+// Ints returns the original offset of each byte of the normalized text
+func (m *OffsetMap) Ints() []int {
+	offsets := make([]int, 0, m.length)
+	for s, segment := range m.segments {
+		length := m.segmentLen(s)
+		for j := 0; j < length; j++ {
+			offsets = append(offsets, segment.at(j, length))
+		}
+	}
+	return offsets
+}
+
+// Size returns the number of bytes used by the segments of the map
+func (m *OffsetMap) Size() int {
+	return cap(m.segments) * int(unsafe.Sizeof(offsetSegment{}))
+}
+
+func (m *OffsetMap) segmentLen(s int) int {
+	if s+1 < len(m.segments) {
+		return m.segments[s+1].begin - m.segments[s].begin
+	}
+	return m.length - m.segments[s].begin
+}
+
+// at returns the original offset of byte j of the segment (of the length)
+func (s offsetSegment) at(j int, length int) int {
+	switch {
+	case !s.replacement:
+		return s.original + j
+	case j == 0:
+		return s.original
+	case j == length-1:
+		return s.last
+	default:
+		return -1
+	}
+}
+
+// appendCopy appends a run of bytes copied from the original text at the original offset
+func (m *OffsetMap) appendCopy(original int, length int) {
+	if length <= 0 {
+		return
+	}
+	if n := len(m.segments); n > 0 {
+		last := m.segments[n-1]
+		if !last.replacement && last.original+m.segmentLen(n-1) == original {
+			m.length += length
+			return
+		}
+	}
+	m.segments = append(m.segments, offsetSegment{begin: m.length, original: original, last: -1})
+	m.length += length
+}
+
+// appendReplacement appends a replacement of the length, whose first and last bytes map to the original offsets
+func (m *OffsetMap) appendReplacement(first int, last int, length int) {
+	switch {
+	case length <= 0:
+		return
+	case first >= 0 && (length == 1 || (length == 2 && last == first+1)):
+		// Same as copied bytes
+		m.appendCopy(first, length)
+		return
+	case first < 0 && (length == 1 || last < 0):
+		// Unmapped bytes extend unmapped bytes
+		if n := len(m.segments); n > 0 && m.segments[n-1].replacement && m.segments[n-1].original < 0 && m.segments[n-1].last < 0 {
+			m.length += length
+			return
+		}
+		last = -1
+	}
+	m.segments = append(m.segments, offsetSegment{begin: m.length, original: first, last: last, replacement: true})
+	m.length += length
+}
+
+// appendRange appends the part of the other map from offset begin to end (of its normalized text)
+func (m *OffsetMap) appendRange(other *OffsetMap, begin int, end int) {
+	if end > other.length {
+		end = other.length
+	}
+	if begin >= end {
+		return
+	}
+	s := sort.Search(len(other.segments), func(j int) bool { return other.segments[j].begin > begin }) - 1
+	for ; s < len(other.segments) && other.segments[s].begin < end; s++ {
+		segment := other.segments[s]
+		length := other.segmentLen(s)
+		from, to := begin-segment.begin, end-segment.begin
+		if from < 0 {
+			from = 0
+		}
+		if to > length {
+			to = length
+		}
+		switch {
+		case !segment.replacement:
+			m.appendCopy(segment.original+from, to-from)
+		case to-from == 1:
+			o := segment.at(from, length)
+			m.appendReplacement(o, o, 1)
+		default:
+			first, last := -1, -1
+			if from == 0 {
+				first = segment.original
+			}
+			if to == length {
+				last = segment.last
+			}
+			m.appendReplacement(first, last, to-from)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package normalizer
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestOffsetMap(t *testing.T) {
+	t.Parallel()
+	var m OffsetMap
+	m.appendCopy(0, 4)           // "the "
+	m.appendReplacement(4, 7, 9) // "(c) 2023" as "copyright"
+	m.appendReplacement(12, 12, 1)
+	m.appendCopy(13, 3) // copied after a one byte replacement
+	m.appendReplacement(-1, -1, 2)
+	m.appendReplacement(-1, -1, 1)
+
+	want := []int{0, 1, 2, 3, 4, -1, -1, -1, -1, -1, -1, -1, 7, 12, 13, 14, 15, -1, -1, -1}
+	if d := cmp.Diff(want, m.Ints()); d != "" {
+		t.Errorf("Didn't get expected offsets (-want, +got): %v", d)
+	}
+	for i, o := range want {
+		if m.At(i) != o {
+			t.Errorf("At(%v) = %v, want %v", i, m.At(i), o)
+		}
+	}
+	if len(m.segments) != 4 {
+		t.Errorf("expected the copies and unmapped bytes to be joined in 4 segments, got %v", len(m.segments))
+	}
+}
+
+func TestOffsetMap_appendRange(t *testing.T) {
+	t.Parallel()
+	random := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		offsets := make([]int, random.Intn(30))
+		next := 0
+		for j := range offsets {
+			switch random.Intn(4) {
+			case 0:
+				offsets[j] = -1
+			case 1:
+				next += random.Intn(5)
+				offsets[j] = next
+			default:
+				offsets[j] = next
+			}
+			next++
+		}
+		m := NewOffsetMap(offsets)
+		if d := cmp.Diff(offsets, m.Ints(), cmpopts.EquateEmpty()); d != "" {
+			t.Fatalf("NewOffsetMap(%v).Ints() (-want, +got): %v", offsets, d)
+		}
+
+		begin := random.Intn(len(offsets) + 1)
+		end := begin + random.Intn(len(offsets)-begin+1)
+		var sub OffsetMap
+		sub.appendRange(&m, begin, end)
+		if d := cmp.Diff(offsets[begin:end], sub.Ints(), cmpopts.EquateEmpty()); d != "" {
+			t.Fatalf("appendRange(%v, %v, %v) (-want, +got): %v", offsets, begin, end, d)
+		}
+	}
+}
@@ -0,0 +1,260 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package normalizer
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"golang.org/x/exp/slices"
+)
+
+// Step is a named normalization step, which changes the normalized text and updates the index map
+type Step struct {
+	Name        string
+	Description string
+	Apply       func(n *NormalizationData)
+}
+
+// RegexpStep is the configuration of a step which replaces the matches of a regex (in the lower case text)
+type RegexpStep struct {
+	Name        string `json:"name" mapstructure:"name"`
+	Pattern     string `json:"pattern" mapstructure:"pattern"`
+	Replacement string `json:"replacement" mapstructure:"replacement"`
+	// After is the step after which this step is added to the pipeline (at the end when empty)
+	After string `json:"after" mapstructure:"after"`
+}
+
+// DefaultSteps are the names of the steps of the default pipeline, in order
+var DefaultSteps = []string{
+	"extractComments",
+	"removeNoteTags",
+	"limitWildcardMatching",
+	"limitOptionalWildcardMatching",
+	"captureReplaceableTextSections",
+	"standardizeOmitableTags",
+	"removeOddCharacters",
+	"removeCodeCommentIndicators",
+	"replaceDashLikeCharacters",
+	"replaceQuoteLikeCharacters",
+	"standardizeToHTTP",
+	"reconnectSplitWords",
+	"removeHorizontalRules",
+	"replaceCopyrightSymbols",
+	"replaceBulletsAndNumbering",
+	"removeHTMLTags",
+	"decodeHTMLEntities",
+	"replaceWhitespace",
+	"replaceVarietalWordSpellings",
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = builtinSteps()
+
+	defaultPipeline = mustPipeline(DefaultSteps)
+)
+
+func builtinSteps() map[string]Step {
+	steps := []Step{
+		{"extractComments", "Keep only the comments of source files (in a known language), without the comment indicators", (*NormalizationData).extractComments},
+		{"removeNoteTags", "Remove note tags and comment line indicators", (*NormalizationData).removeNoteTags},
+		{"limitWildcardMatching", "Replace the wild card matching pattern <<match=.+>> with the range of the permitted number of characters i.e. 1, 144", (*NormalizationData).limitWildcardMatching},
+		{"limitOptionalWildcardMatching", "Replace the optional wild card matching pattern <<match=.*>> with the range of the permitted number of characters i.e. 0, 144", (*NormalizationData).limitOptionalWildcardMatching},
+		{"captureReplaceableTextSections", "Capture replaceable text sections. (Guideline 2.1.3)", (*NormalizationData).captureReplaceableTextSections},
+		{"standardizeOmitableTags", "Replace the optional tags with <<omitable>> and <</omitable>>. (Guideline 2.1.4)", (*NormalizationData).standardizeOmitableTags},
+		{"removeOddCharacters", "Remove odd characters, such as the replacement character", (*NormalizationData).removeOddCharacters},
+		{"removeCodeCommentIndicators", "Remove code comment indicators, unless the comments were extracted. (Guideline 6.1.1)", (*NormalizationData).removeCodeCommentIndicators},
+		{"replaceDashLikeCharacters", "Any hyphen, dash, en dash, em dash, or other variations should be considered equivalent. (Guideline 5.1.2)", (*NormalizationData).replaceDashLikeCharacters},
+		{"replaceQuoteLikeCharacters", "Any variation of quotations (single, double, curly, etc.) should be considered equivalent. (Guideline 5.1.3)", (*NormalizationData).replaceQuoteLikeCharacters},
+		{"standardizeToHTTP", "HTTP:// and HTTPS:// should be considered equivalent. (Guideline 13.1.1)", (*NormalizationData).standardizeToHTTP},
+		{"reconnectSplitWords", "Reconnect words split with a hyphen at the end of a line", (*NormalizationData).reconnectSplitWords},
+		{"removeHorizontalRules", "Remove horizontal rules", (*NormalizationData).removeHorizontalRules},
+		{"replaceCopyrightSymbols", "“©”, “(c)”, or “Copyright” should be considered equivalent and interchangeable. (Guideline 9.1.1)", (*NormalizationData).replaceCopyrightSymbols},
+		{"replaceBulletsAndNumbering", "Remove bullets and numbering, after replaceCopyrightSymbols to handle the overlapping case (c). (Guideline 7.1.1)", (*NormalizationData).replaceBulletsAndNumbering},
+		{"removeHTMLTags", "Remove HTML tags", (*NormalizationData).removeHTMLTags},
+		{"decodeHTMLEntities", "Decode HTML entities (e.g. &copy; &quot; &#8220; &amp;)", (*NormalizationData).decodeHTMLEntities},
+		{"replaceWhitespace", "Replace all whitespace with a single space. (Guideline 3.1.1)", (*NormalizationData).replaceWhitespace},
+		{"replaceVarietalWordSpellings", "Replace varietal word spelling. (Guideline 8.1.1)", (*NormalizationData).replaceVarietalWordSpellings},
+	}
+	m := make(map[string]Step, len(steps))
+	for _, s := range steps {
+		m[s.Name] = s
+	}
+	return m
+}
+
+// RegisterStep adds a step to the registry, so it can be used by name in pipelines (e.g. from the config)
+func RegisterStep(step Step) error {
+	if step.Name == "" || step.Apply == nil {
+		return fmt.Errorf("invalid normalization step %q", step.Name)
+	}
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if _, ok := registry[step.Name]; ok {
+		return fmt.Errorf("normalization step %q is already registered", step.Name)
+	}
+	registry[step.Name] = step
+	return nil
+}
+
+func isRegistered(name string) bool {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	_, ok := registry[name]
+	return ok
+}
+
+// RegisteredSteps returns the registered steps, sorted by name
+func RegisteredSteps() []Step {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	steps := make([]Step, 0, len(registry))
+	for _, s := range registry {
+		steps = append(steps, s)
+	}
+	sort.Slice(steps, func(i, j int) bool { return steps[i].Name < steps[j].Name })
+	return steps
+}
+
+// NewRegexpStep returns a step which replaces the matches of the regex pattern with the replacement
+// (which can use $1 for the submatches)
+func NewRegexpStep(name string, pattern string, replacement string) (Step, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return Step{}, fmt.Errorf("invalid pattern of normalization step %q: %w", name, err)
+	}
+	return Step{
+		Name:        name,
+		Description: fmt.Sprintf("Replace %q with %q", pattern, replacement),
+		Apply: func(n *NormalizationData) {
+			n.initialize()
+			allSubmatchIndex := re.FindAllStringSubmatchIndex(n.NormalizedText, -1)
+			replacements := make([]string, len(allSubmatchIndex))
+			for i, match := range allSubmatchIndex {
+				replacements[i] = string(re.ExpandString(nil, replacement, n.NormalizedText, match))
+			}
+			n.replaceMatchesWithStringsAndUpdateIndexMap(allSubmatchIndex, replacements)
+		},
+	}, nil
+}
+
+// Pipeline is an ordered list of normalization steps
+type Pipeline struct {
+	steps []Step
+	// words are the varietal word spellings of replaceVarietalWordSpellings (nil for the DefaultVarietalWords)
+	words *VarietalWords
+}
+
+// DefaultPipeline returns the pipeline of the DefaultSteps
+func DefaultPipeline() *Pipeline {
+	return defaultPipeline
+}
+
+// NewPipeline returns the pipeline of the named steps, in order. The names are looked up in the extra steps
+// (e.g. regexp steps of a license library) and then in the registry.
+func NewPipeline(names []string, extra ...Step) (*Pipeline, error) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	p := Pipeline{steps: make([]Step, 0, len(names))}
+	for _, name := range names {
+		step, ok := findStep(name, extra)
+		if !ok {
+			return nil, fmt.Errorf("unknown normalization step %q", name)
+		}
+		p.steps = append(p.steps, step)
+	}
+	return &p, nil
+}
+
+func findStep(name string, extra []Step) (Step, bool) {
+	for _, s := range extra {
+		if s.Name == name {
+			return s, true
+		}
+	}
+	s, ok := registry[name]
+	return s, ok
+}
+
+func mustPipeline(names []string) *Pipeline {
+	p, err := NewPipeline(names)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// ConfigurePipeline returns the pipeline of the steps (or the DefaultSteps, when empty), without the skipped steps,
+// and with the regexp steps added after their After step (unless the steps already name them)
+func ConfigurePipeline(steps []string, skip []string, regexpSteps []RegexpStep) (*Pipeline, error) {
+	names := steps
+	if len(names) == 0 {
+		names = DefaultSteps
+	}
+
+	var extra []Step
+	for _, rs := range regexpSteps {
+		if isRegistered(rs.Name) {
+			return nil, fmt.Errorf("normalization step %q is already registered", rs.Name)
+		}
+		step, err := NewRegexpStep(rs.Name, rs.Pattern, rs.Replacement)
+		if err != nil {
+			return nil, err
+		}
+		extra = append(extra, step)
+		if slices.Contains(names, rs.Name) {
+			continue
+		}
+		i := len(names)
+		if rs.After != "" {
+			if i = slices.Index(names, rs.After); i < 0 {
+				return nil, fmt.Errorf("normalization step %q is after unknown step %q", rs.Name, rs.After)
+			}
+			i++
+		}
+		names = append(names[:i:i], append([]string{rs.Name}, names[i:]...)...)
+	}
+
+	var kept []string
+	for _, name := range names {
+		if !slices.Contains(skip, name) {
+			kept = append(kept, name)
+		}
+	}
+	for _, name := range skip {
+		if !slices.Contains(names, name) {
+			return nil, fmt.Errorf("cannot skip unknown normalization step %q", name)
+		}
+	}
+	return NewPipeline(kept, extra...)
+}
+
+// Steps returns the names of the steps, in order
+func (p *Pipeline) Steps() []string {
+	names := make([]string, len(p.steps))
+	for i, s := range p.steps {
+		names[i] = s.Name
+	}
+	return names
+}
+
+// WithVarietalWords returns a copy of the pipeline which replaces the varietal word spellings
+// (and equivalent words) with the words, instead of the DefaultVarietalWords
+func (p *Pipeline) WithVarietalWords(words *VarietalWords) *Pipeline {
+	if p == nil {
+		p = DefaultPipeline()
+	}
+	return &Pipeline{steps: p.steps, words: words}
+}
+
+// IsDefault is true when the pipeline has the DefaultSteps and the DefaultVarietalWords
+func (p *Pipeline) IsDefault() bool {
+	if p == nil || p == defaultPipeline {
+		return true
+	}
+	return p.words == nil && strings.Join(p.Steps(), ",") == strings.Join(DefaultSteps, ",")
+}
@@ -0,0 +1,139 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package normalizer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const pipelineText = "/*\n * Copyright (c) 2023 Someone\n *\n * 1. Redistributions of source code must retain the &quot;above&quot; copyright notice.\n */"
+
+func TestPipeline_default(t *testing.T) {
+	t.Parallel()
+	p, err := ConfigurePipeline(nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !p.IsDefault() || !DefaultPipeline().IsDefault() {
+		t.Errorf("expected the default pipeline")
+	}
+
+	want := NormalizationData{OriginalText: pipelineText}
+	if err := want.NormalizeText(); err != nil {
+		t.Fatal(err)
+	}
+
+	var trace []string
+	got := NormalizationData{OriginalText: pipelineText, Pipeline: p}
+	got.OnStep = func(step string, n *NormalizationData) {
+		if len(n.NormalizedText) != n.IndexMap.Len() {
+			t.Errorf("step %v: text length %v != index map length %v", step, len(n.NormalizedText), n.IndexMap.Len())
+		}
+		trace = append(trace, step)
+	}
+	if err := got.NormalizeText(); err != nil {
+		t.Fatal(err)
+	}
+	if d := cmp.Diff(want.NormalizedText, got.NormalizedText); d != "" {
+		t.Errorf("Didn't get expected Normalized text: (-want, +got): %v", d)
+	}
+	if d := cmp.Diff(want.IndexMap.Ints(), got.IndexMap.Ints()); d != "" {
+		t.Errorf("Didn't get expected IndexMap: (-want, +got): %v", d)
+	}
+	if d := cmp.Diff(DefaultSteps, trace); d != "" {
+		t.Errorf("Didn't get expected steps (-want, +got): %v", d)
+	}
+}
+
+func TestConfigurePipeline(t *testing.T) {
+	t.Parallel()
+	p, err := ConfigurePipeline(nil, []string{"replaceBulletsAndNumbering", "replaceCopyrightSymbols"}, []RegexpStep{
+		{Name: "removeYears", Pattern: `\b(?:19|20)\d\d\b`, Replacement: "yyyy", After: "removeCodeCommentIndicators"},
+		{Name: "swapWords", Pattern: `(source) (code)`, Replacement: "$2 $1"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.IsDefault() {
+		t.Errorf("expected a pipeline which is not the default")
+	}
+	steps := p.Steps()
+	if steps[7] != "removeCodeCommentIndicators" || steps[8] != "removeYears" || steps[len(steps)-1] != "swapWords" {
+		t.Errorf("Didn't get expected steps: %v", steps)
+	}
+
+	n := NormalizationData{OriginalText: pipelineText, Pipeline: p}
+	if err := n.NormalizeText(); err != nil {
+		t.Fatal(err)
+	}
+	want := "copyright (c) yyyy someone 1. redistribution of code source must retain the 'above' copyright notice."
+	if d := cmp.Diff(want, n.NormalizedText); d != "" {
+		t.Errorf("Didn't get expected Normalized text: (-want, +got): %v", d)
+	}
+	year := strings.Index(n.NormalizedText, "yyyy")
+	if begins, ends := n.IndexMap.At(year), n.IndexMap.At(year+len("yyyy")-1); pipelineText[begins:ends+1] != "2023" {
+		t.Errorf("Didn't get expected original text for yyyy: %q", pipelineText[begins:ends+1])
+	}
+}
+
+func TestConfigurePipeline_invalid(t *testing.T) {
+	t.Parallel()
+	for name, tc := range map[string]struct {
+		steps       []string
+		skip        []string
+		regexpSteps []RegexpStep
+	}{
+		"unknown step":        {steps: []string{"removeNoteTags", "unknown"}},
+		"unknown skip":        {skip: []string{"unknown"}},
+		"invalid pattern":     {regexpSteps: []RegexpStep{{Name: "bad", Pattern: "("}}},
+		"unknown after":       {regexpSteps: []RegexpStep{{Name: "x", Pattern: "x", After: "unknown"}}},
+		"registered name":     {regexpSteps: []RegexpStep{{Name: "removeHTMLTags", Pattern: "x"}}},
+		"skip of named step":  {steps: []string{"removeNoteTags"}, skip: []string{"removeHTMLTags"}},
+		"step without regexp": {steps: []string{"removeDoxygen"}},
+	} {
+		if _, err := ConfigurePipeline(tc.steps, tc.skip, tc.regexpSteps); err == nil {
+			t.Errorf("%v: expected an error", name)
+		}
+	}
+}
+
+func TestRegisterStep(t *testing.T) {
+	t.Parallel()
+	step, err := NewRegexpStep("test_removeDoxygen", `[@\\]brief\b`, " ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := RegisterStep(step); err != nil {
+		t.Fatal(err)
+	}
+	if err := RegisterStep(step); err == nil {
+		t.Errorf("expected an error for a duplicate step")
+	}
+	if err := RegisterStep(Step{Name: "test_noApply"}); err == nil {
+		t.Errorf("expected an error for a step without Apply")
+	}
+
+	p, err := NewPipeline([]string{"test_removeDoxygen", "replaceWhitespace"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	n := NormalizationData{OriginalText: "@brief MIT License", Pipeline: p}
+	if err := n.NormalizeText(); err != nil {
+		t.Fatal(err)
+	}
+	if n.NormalizedText != "mit license" {
+		t.Errorf("NormalizedText = %q", n.NormalizedText)
+	}
+	var found bool
+	for _, s := range RegisteredSteps() {
+		found = found || s.Name == step.Name
+	}
+	if !found {
+		t.Errorf("expected %v in the registered steps", step.Name)
+	}
+}
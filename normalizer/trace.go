@@ -0,0 +1,41 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package normalizer
+
+import (
+	"encoding/binary"
+	"hash/crc32"
+)
+
+// normalizeUnicodeStep is the name of the Unicode normalization in a trace. It is not a step of the pipeline,
+// because every step expects the text to be normalized (and in lower case) already.
+const normalizeUnicodeStep = "normalizeUnicode"
+
+// TraceStep is the normalized text after a normalization step
+type TraceStep struct {
+	Step           string
+	NormalizedText string
+	// IndexMapChecksum is a checksum of the index map, which shows whether the step changed the index map
+	IndexMapChecksum uint32
+}
+
+// trace records the normalized text and index map after the step, when tracing
+func (n *NormalizationData) trace(step string) {
+	if !n.Trace {
+		return
+	}
+	n.TraceSteps = append(n.TraceSteps, TraceStep{
+		Step:             step,
+		NormalizedText:   n.NormalizedText,
+		IndexMapChecksum: IndexMapChecksum(n.IndexMap.Ints()),
+	})
+}
+
+// IndexMapChecksum returns the CRC-32 checksum of the index map
+func IndexMapChecksum(indexMap []int) uint32 {
+	b := make([]byte, 8*len(indexMap))
+	for i, v := range indexMap {
+		binary.LittleEndian.PutUint64(b[8*i:], uint64(v))
+	}
+	return crc32.ChecksumIEEE(b)
+}
@@ -0,0 +1,85 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package normalizer
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+)
+
+//go:embed replacement_words.json
+var replacementWordsBytes []byte
+
+var defaultVarietalWords = mustVarietalWords()
+
+// VarietalWords are the varietal word spellings (and equivalent words), compiled once. Each replacement word
+// replaces the matches of its pattern in the normalized (lower case) text. The replacements are applied in order
+// of the replacement words, so the normalized text does not depend on the order of a map.
+type VarietalWords struct {
+	replacements []string
+	res          []*regexp.Regexp
+}
+
+// ReadReplacementWordsJSON reads a JSON object of replacement words to patterns (like replacement_words.json)
+func ReadReplacementWordsJSON(fileContents []byte) (map[string]string, error) {
+	words := make(map[string]string)
+	if err := json.Unmarshal(fileContents, &words); err != nil {
+		return nil, err
+	}
+	return words, nil
+}
+
+// DefaultVarietalWords returns the varietal word spellings of the embedded replacement_words.json
+func DefaultVarietalWords() *VarietalWords {
+	return defaultVarietalWords
+}
+
+// NewVarietalWords returns the default varietal word spellings merged with the extra replacement words.
+// An extra pattern for a default replacement word is an alternative to the default pattern.
+func NewVarietalWords(extra ...map[string]string) (*VarietalWords, error) {
+	words, err := ReadReplacementWordsJSON(replacementWordsBytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid replacement_words.json: %w", err)
+	}
+	for _, m := range extra {
+		for replacement, pattern := range m {
+			if pattern == "" {
+				return nil, fmt.Errorf("empty pattern for the replacement word %q", replacement)
+			}
+			if existing, ok := words[replacement]; ok && existing != pattern {
+				pattern = "(?:" + existing + ")|(?:" + pattern + ")"
+			}
+			words[replacement] = pattern
+		}
+	}
+
+	v := VarietalWords{replacements: make([]string, 0, len(words))}
+	for replacement := range words {
+		v.replacements = append(v.replacements, replacement)
+	}
+	sort.Strings(v.replacements)
+	for _, replacement := range v.replacements {
+		re, err := regexp.Compile(words[replacement])
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern for the replacement word %q: %w", replacement, err)
+		}
+		v.res = append(v.res, re)
+	}
+	return &v, nil
+}
+
+func mustVarietalWords() *VarietalWords {
+	v, err := NewVarietalWords()
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Len returns the number of replacement words
+func (v *VarietalWords) Len() int {
+	return len(v.replacements)
+}
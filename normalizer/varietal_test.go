@@ -0,0 +1,78 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package normalizer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNewVarietalWords(t *testing.T) {
+	t.Parallel()
+	words, err := NewVarietalWords(map[string]string{
+		"warranty": `\bwarrenty\b`,
+		"license":  `\blisence\b`,
+	}, map[string]string{
+		"warranty": `\bwarantee\b`,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if words.Len() != DefaultVarietalWords().Len()+1 {
+		t.Errorf("expected one more replacement word than the defaults, got %v", words.Len())
+	}
+	for i := 1; i < words.Len(); i++ {
+		if words.replacements[i-1] >= words.replacements[i] {
+			t.Fatalf("expected the replacement words in order, got %q before %q", words.replacements[i-1], words.replacements[i])
+		}
+	}
+
+	pipeline := DefaultPipeline().WithVarietalWords(words)
+	if pipeline.IsDefault() {
+		t.Errorf("expected a pipeline with its own varietal words not to be the default")
+	}
+	if d := cmp.Diff(DefaultSteps, pipeline.Steps()); d != "" {
+		t.Errorf("Didn't get expected steps (-want, +got): %v", d)
+	}
+
+	text := "No Warrenty or warantee. This licence (or Lisence)."
+	for _, isTemplate := range []bool{false, true} {
+		n := NormalizationData{OriginalText: text, IsTemplate: isTemplate, Pipeline: pipeline}
+		if err := n.NormalizeText(); err != nil {
+			t.Fatal(err)
+		}
+		if d := cmp.Diff("no warranty or warranty. this license (or license).", n.NormalizedText); d != "" {
+			t.Errorf("Didn't get expected Normalized text (isTemplate %v): (-want, +got): %v", isTemplate, d)
+		}
+		// The replacement maps to the replaced word
+		i := strings.Index(n.NormalizedText, "warranty")
+		if begins, ends := n.IndexMap.At(i), n.IndexMap.At(i+len("warranty")-1); text[begins:ends+1] != "Warrenty" {
+			t.Errorf("expected the index map of %q to map to %q, got %q", "warranty", "Warrenty", text[begins:ends+1])
+		}
+	}
+
+	// The default words are unchanged
+	n := NormalizationData{OriginalText: text}
+	if err := n.NormalizeText(); err != nil {
+		t.Fatal(err)
+	}
+	if d := cmp.Diff("no warrenty or warantee. this license (or lisence).", n.NormalizedText); d != "" {
+		t.Errorf("Didn't get expected Normalized text with the default words: (-want, +got): %v", d)
+	}
+}
+
+func TestNewVarietalWords_invalid(t *testing.T) {
+	t.Parallel()
+	for _, words := range []map[string]string{
+		{"warranty": `\bwarrenty(`},
+		{"warranty": ""},
+	} {
+		if _, err := NewVarietalWords(words); err == nil {
+			t.Errorf("expected an error for the replacement words %v", words)
+		}
+	}
+}
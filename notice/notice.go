@@ -0,0 +1,304 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package notice
+
+import (
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	texttemplate "text/template"
+
+	"github.com/CycloneDX/license-scanner/identifier"
+	"github.com/CycloneDX/license-scanner/licenses"
+	"github.com/CycloneDX/license-scanner/normalizer"
+)
+
+// Output formats for a NOTICE
+const (
+	FormatText     = "text"
+	FormatMarkdown = "markdown"
+	FormatHTML     = "html"
+)
+
+//go:embed templates/*.tmpl
+var defaultTemplates embed.FS
+
+// Options for building and rendering a NOTICE
+type Options struct {
+	// Title is the heading of the NOTICE
+	Title string
+	// Root is the scanned directory. Component names are relative to Root.
+	Root string
+	// Format is one of FormatText (default), FormatMarkdown, or FormatHTML
+	Format string
+	// TemplatePath is a Go template file to use instead of the default template for the format
+	TemplatePath string
+}
+
+// Notice is the data passed to the NOTICE template
+type Notice struct {
+	Title    string
+	Licenses []LicenseGroup
+	// Unlicensed lists the components where no license was found
+	Unlicensed []Component
+}
+
+// LicenseGroup is a detected license with the components using it
+type LicenseGroup struct {
+	ID         string
+	Name       string
+	Components []Component
+	// Texts are the distinct license texts found (deduplicated by normalized digest)
+	Texts []LicenseText
+}
+
+// Component is a directory of scanned files
+type Component struct {
+	Name       string
+	Files      []string
+	Copyrights []identifier.CopyrightHolder
+}
+
+// LicenseText is a license text found in one or more files
+type LicenseText struct {
+	Hash  normalizer.Digest
+	Text  string
+	Files []string
+}
+
+// New groups the scan results by license ID. Copyrights are included when the results were
+// identified with the FlagCopyrights enhancement. The license library may be nil.
+func New(results []identifier.IdentifierResults, licenseLibrary *licenses.LicenseLibrary, options Options) *Notice {
+	n := Notice{Title: options.Title}
+	if n.Title == "" {
+		n.Title = "Third-Party Notices"
+	}
+
+	groups := make(map[string]*LicenseGroup)
+	unlicensed := make(map[string]*component)
+	groupComponents := make(map[string]map[string]*component)
+
+	// Results from a directory scan are not in any particular order
+	sorted := make([]identifier.IdentifierResults, len(results))
+	copy(sorted, results)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].File < sorted[j].File })
+
+	for _, result := range sorted {
+		name := componentName(options.Root, result.File)
+		file := identifier.RelativePath(options.Root, result.File)
+
+		if len(result.Matches) == 0 {
+			addToComponent(unlicensed, name, file, result)
+			continue
+		}
+
+		for id := range result.Matches {
+			g, ok := groups[id]
+			if !ok {
+				g = &LicenseGroup{ID: id, Name: licenseName(id, licenseLibrary)}
+				groups[id] = g
+				groupComponents[id] = make(map[string]*component)
+			}
+			addToComponent(groupComponents[id], name, file, result)
+			addLicenseText(g, id, file, result)
+		}
+	}
+
+	var ids []string
+	for id := range groups {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		g := groups[id]
+		g.Components = sortedComponents(groupComponents[id])
+		n.Licenses = append(n.Licenses, *g)
+	}
+	n.Unlicensed = sortedComponents(unlicensed)
+
+	return &n
+}
+
+func licenseName(id string, licenseLibrary *licenses.LicenseLibrary) string {
+	if licenseLibrary != nil {
+		if l, ok := licenseLibrary.LicenseMap[id]; ok && l.LicenseInfo.Name != "" {
+			return l.LicenseInfo.Name
+		}
+	}
+	return id
+}
+
+// componentName uses the file's directory (relative to root) as the component name
+func componentName(root string, file string) string {
+	dir := filepath.Dir(identifier.RelativePath(root, file))
+	if dir == "." {
+		if root != "" {
+			return filepath.Base(root)
+		}
+		return dir
+	}
+	return filepath.ToSlash(dir)
+}
+
+// component collects the files and copyrights of a component before merging the copyright holders
+type component struct {
+	files   []string
+	results []identifier.IdentifierResults
+}
+
+func addToComponent(components map[string]*component, name string, file string, result identifier.IdentifierResults) {
+	c, ok := components[name]
+	if !ok {
+		c = &component{}
+		components[name] = c
+	}
+	c.files = append(c.files, file)
+	c.results = append(c.results, identifier.IdentifierResults{File: file, Copyrights: result.Copyrights})
+}
+
+// addLicenseText adds the text of the blocks matching the license, unless the same (normalized) text was already added
+func addLicenseText(g *LicenseGroup, id string, file string, result identifier.IdentifierResults) {
+	text, hash := licenseText(id, result)
+	if text == "" {
+		return
+	}
+	for i := range g.Texts {
+		if g.Texts[i].Hash == hash {
+			g.Texts[i].Files = append(g.Texts[i].Files, file)
+			return
+		}
+	}
+	g.Texts = append(g.Texts, LicenseText{Hash: hash, Text: text, Files: []string{file}})
+}
+
+// licenseText returns the text of the blocks matching the license ID (or the whole file without blocks) and its digest
+func licenseText(id string, result identifier.IdentifierResults) (string, normalizer.Digest) {
+	var sb strings.Builder
+	for _, block := range result.Blocks {
+		for _, m := range block.Matches {
+			if m == id {
+				sb.WriteString(block.Text)
+				break
+			}
+		}
+	}
+	text := strings.TrimSpace(sb.String())
+	if text == "" {
+		return strings.TrimSpace(result.OriginalText), result.Hash
+	}
+
+	nd := normalizer.NewNormalizationData(text, false)
+	if err := nd.NormalizeText(); err != nil {
+		return strings.TrimSpace(result.OriginalText), result.Hash
+	}
+	return text, nd.Hash
+}
+
+func sortedComponents(components map[string]*component) []Component {
+	var names []string
+	for name := range components {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var ret []Component
+	for _, name := range names {
+		c := components[name]
+		ret = append(ret, Component{
+			Name:       name,
+			Files:      c.files,
+			Copyrights: identifier.MergeCopyrightHolders(c.results),
+		})
+	}
+	return ret
+}
+
+// Years formats year ranges like "2010-2012, 2014, 2016-present"
+func Years(yearRanges []identifier.YearRange) string {
+	var ys []string
+	for _, r := range yearRanges {
+		switch {
+		case r.Ongoing:
+			ys = append(ys, fmt.Sprintf("%v-present", r.Start))
+		case r.Start == r.End:
+			ys = append(ys, fmt.Sprint(r.Start))
+		default:
+			ys = append(ys, fmt.Sprintf("%v-%v", r.Start, r.End))
+		}
+	}
+	return strings.Join(ys, ", ")
+}
+
+// Copyright formats a holder as a copyright line like "Copyright (c) 2010-2012 The Go Authors"
+func Copyright(h identifier.CopyrightHolder) string {
+	s := "Copyright (c)"
+	if years := Years(h.Years); years != "" {
+		s += " " + years
+	}
+	s += " " + h.Holder
+	if len(h.Emails) > 0 {
+		s += " <" + strings.Join(h.Emails, ">, <") + ">"
+	}
+	return s
+}
+
+var funcs = map[string]interface{}{
+	"years":     Years,
+	"copyright": Copyright,
+	"join":      strings.Join,
+	"anchor":    anchor,
+}
+
+// anchor makes an HTML id or Markdown link target from a license ID
+func anchor(id string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '-':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r + 'a' - 'A'
+		}
+		return '-'
+	}, id)
+}
+
+// Render writes the NOTICE using the format's default template or the options.TemplatePath template
+func (n *Notice) Render(w io.Writer, options Options) error {
+	format := options.Format
+	if format == "" {
+		format = FormatText
+	}
+
+	var source []byte
+	var err error
+	switch format {
+	case FormatText, FormatMarkdown, FormatHTML:
+		if options.TemplatePath != "" {
+			source, err = os.ReadFile(options.TemplatePath)
+		} else {
+			source, err = defaultTemplates.ReadFile("templates/notice." + format + ".tmpl")
+		}
+	default:
+		return fmt.Errorf("invalid notice format %q (expected %v, %v, or %v)", format, FormatText, FormatMarkdown, FormatHTML)
+	}
+	if err != nil {
+		return err
+	}
+
+	if format == FormatHTML {
+		t, err := htmltemplate.New("notice").Funcs(funcs).Parse(string(source))
+		if err != nil {
+			return err
+		}
+		return t.Execute(w, n)
+	}
+	t, err := texttemplate.New("notice").Funcs(funcs).Parse(string(source))
+	if err != nil {
+		return err
+	}
+	return t.Execute(w, n)
+}
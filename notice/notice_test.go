@@ -0,0 +1,145 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package notice
+
+import (
+	"bytes"
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+
+	"github.com/CycloneDX/license-scanner/identifier"
+)
+
+const mitText = "Permission is hereby granted, free of charge, to any person obtaining a copy of this software."
+
+func testResults() []identifier.IdentifierResults {
+	return []identifier.IdentifierResults{
+		{
+			File:    "vendor/foo/LICENSE",
+			Matches: map[string][]identifier.Match{"MIT": {{Begins: 23, Ends: 117}}},
+			Blocks: []identifier.Block{
+				{Text: "Copyright 2019 Foo Inc\n", Matches: []string{"COPYRIGHT"}},
+				{Text: mitText, Matches: []string{"MIT"}},
+			},
+			Copyrights: []identifier.CopyrightStatement{
+				{Text: "Copyright 2019 Foo Inc", Holder: "Foo Inc", Years: []identifier.YearRange{{Start: 2019, End: 2019}}},
+			},
+		},
+		{
+			File:    "vendor/bar/LICENSE.md",
+			Matches: map[string][]identifier.Match{"MIT": {{Begins: 0, Ends: 94}}},
+			Blocks: []identifier.Block{
+				{Text: strings.ToUpper(mitText), Matches: []string{"MIT"}},
+			},
+		},
+		{
+			File:    "vendor/foo/NOTICE",
+			Matches: map[string][]identifier.Match{},
+			Copyrights: []identifier.CopyrightStatement{
+				{Text: "Copyright 2020 Foo Inc.", Holder: "Foo Inc.", Years: []identifier.YearRange{{Start: 2020, End: 2020}}},
+			},
+		},
+	}
+}
+
+func TestNew(t *testing.T) {
+	got := New(testResults(), nil, Options{Root: "vendor"})
+
+	want := &Notice{
+		Title: "Third-Party Notices",
+		Licenses: []LicenseGroup{
+			{
+				ID:   "MIT",
+				Name: "MIT",
+				Components: []Component{
+					{Name: "bar", Files: []string{"bar/LICENSE.md"}},
+					{
+						Name:  "foo",
+						Files: []string{"foo/LICENSE"},
+						Copyrights: []identifier.CopyrightHolder{{
+							Holder: "Foo Inc", Years: []identifier.YearRange{{Start: 2019, End: 2019}},
+							Files: []string{"foo/LICENSE"}, Statements: []string{"Copyright 2019 Foo Inc"},
+						}},
+					},
+				},
+				// Same normalized text, so only the first one is included
+				Texts: []LicenseText{{Text: strings.ToUpper(mitText), Files: []string{"bar/LICENSE.md", "foo/LICENSE"}}},
+			},
+		},
+		Unlicensed: []Component{
+			{
+				Name:  "foo",
+				Files: []string{"foo/NOTICE"},
+				Copyrights: []identifier.CopyrightHolder{{
+					Holder: "Foo Inc.", Years: []identifier.YearRange{{Start: 2020, End: 2020}},
+					Files: []string{"foo/NOTICE"}, Statements: []string{"Copyright 2020 Foo Inc."},
+				}},
+			},
+		},
+	}
+	if diff := cmp.Diff(want, got, cmpopts.IgnoreFields(LicenseText{}, "Hash")); diff != "" {
+		t.Errorf("New() (-want, +got): %+v", diff)
+	}
+}
+
+func TestNotice_Render(t *testing.T) {
+	n := New(testResults(), nil, Options{Root: "vendor", Title: "ACME NOTICE"})
+
+	tests := []struct {
+		format   string
+		expected []string
+	}{
+		{format: "", expected: []string{"ACME NOTICE", "  * foo\n      Copyright (c) 2019 Foo Inc", strings.ToUpper(mitText), "No license found"}},
+		{format: FormatMarkdown, expected: []string{"# ACME NOTICE", "* [MIT](#mit)", "## MIT", "* `foo`\n  * Copyright (c) 2019 Foo Inc", "```text\n" + strings.ToUpper(mitText)}},
+		{format: FormatHTML, expected: []string{"<title>ACME NOTICE</title>", `<h2 id="mit">MIT</h2>`, "<li>Copyright (c) 2020 Foo Inc.</li>"}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.format, func(t *testing.T) {
+			var b bytes.Buffer
+			if err := n.Render(&b, Options{Format: tt.format}); err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+			for _, expected := range tt.expected {
+				if !strings.Contains(b.String(), expected) {
+					t.Errorf("Render() expected output containing %q got:\n%v", expected, b.String())
+				}
+			}
+		})
+	}
+
+	if err := n.Render(&bytes.Buffer{}, Options{Format: "pdf"}); err == nil {
+		t.Error("Render() expected error for invalid format")
+	}
+}
+
+func TestNotice_Render_template(t *testing.T) {
+	templatePath := path.Join(t.TempDir(), "custom.tmpl")
+	if err := os.WriteFile(templatePath, []byte(`{{ range .Licenses }}{{ .ID }}:{{ range .Components }} {{ .Name }}{{ end }}{{ end }}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var b bytes.Buffer
+	n := New(testResults(), nil, Options{Root: "vendor"})
+	if err := n.Render(&b, Options{TemplatePath: templatePath}); err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if got, want := b.String(), "MIT: bar foo"; got != want {
+		t.Errorf("Render() with template got %q want %q", got, want)
+	}
+}
+
+func TestYears(t *testing.T) {
+	years := []identifier.YearRange{{Start: 2010, End: 2012}, {Start: 2014, End: 2014}, {Start: 2016, End: 2019, Ongoing: true}}
+	want := "2010-2012, 2014, 2016-present"
+	if got := Years(years); got != want {
+		t.Errorf("Years() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,155 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package purl
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ErrNotFound is wrapped by the errors for packages which are not in the local mirror
+var ErrNotFound = errors.New("package not found in the local mirror")
+
+// Mirror resolves package URLs to package directories in a local mirror (or cache) directory.
+// Nothing is downloaded. Each package type has its own directory in the mirror:
+//
+//	npm/<namespace>/<name>/<version>        (e.g. npm/@angular/core/15.0.0)
+//	maven/<group/as/path>/<name>/<version>  (the Maven repository layout, e.g. maven/org/example/example/1.0.0)
+//	pypi/<name>/<version>                   (e.g. pypi/requests/2.28.1)
+//	gem/<name>-<version>                    (the RubyGems gems layout, e.g. gem/rails-7.0.4)
+//	cargo/<name>-<version>                  (the Cargo registry src layout, e.g. cargo/serde-1.0.147)
+//
+// When the package URL has no version, the package is resolved if only one version is in the mirror.
+// The subpath of the package URL is a path within the package directory.
+type Mirror struct {
+	// Root is the mirror directory
+	Root string
+}
+
+// Resolve returns the local directory (or file, with a subpath) of the package
+func (m Mirror) Resolve(p PackageURL) (string, error) {
+	if m.Root == "" {
+		return "", errors.New("a local mirror directory is required to resolve package URLs")
+	}
+	if !safePath(p.Namespace) || !safePath(p.Name) || !safePath(p.Version) || !safePath(p.Subpath) {
+		return "", fmt.Errorf("%w %v: the path is outside of the local mirror", ErrInvalid, p)
+	}
+
+	var dir string
+	var err error
+	switch p.Type {
+	case TypeNPM:
+		dir, err = m.versionDir(p, filepath.Join(m.Root, TypeNPM, filepath.FromSlash(p.Namespace), p.Name))
+	case TypeMaven:
+		group := strings.ReplaceAll(p.Namespace, ".", "/")
+		dir, err = m.versionDir(p, filepath.Join(m.Root, TypeMaven, filepath.FromSlash(group), p.Name))
+	case TypePyPI:
+		dir, err = m.versionDir(p, filepath.Join(m.Root, TypePyPI, p.Name))
+	case TypeGem, TypeCargo:
+		dir, err = m.nameVersionDir(p, filepath.Join(m.Root, p.Type))
+	default:
+		return "", fmt.Errorf("package URL type %q is not supported (%v)", p.Type, strings.Join([]string{TypeNPM, TypeMaven, TypePyPI, TypeGem, TypeCargo}, ", "))
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if p.Subpath != "" {
+		dir = filepath.Join(dir, filepath.FromSlash(p.Subpath))
+		if _, err := os.Stat(dir); err != nil {
+			return "", fmt.Errorf("%w: %v subpath: %v", ErrNotFound, p, err)
+		}
+	}
+	return dir, nil
+}
+
+// versionDir returns the version directory in the package directory
+func (m Mirror) versionDir(p PackageURL, packageDir string) (string, error) {
+	if p.Version != "" {
+		dir := filepath.Join(packageDir, p.Version)
+		if !isDir(dir) {
+			return "", fmt.Errorf("%w: %v (expected %v)", ErrNotFound, p, dir)
+		}
+		return dir, nil
+	}
+
+	entries, err := os.ReadDir(packageDir)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v (expected %v)", ErrNotFound, p, packageDir)
+	}
+	var versions []string
+	for _, e := range entries {
+		if e.IsDir() {
+			versions = append(versions, e.Name())
+		}
+	}
+	version, err := onlyVersion(p, packageDir, versions)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(packageDir, version), nil
+}
+
+// nameVersionDir returns the <name>-<version> directory in the type directory
+func (m Mirror) nameVersionDir(p PackageURL, typeDir string) (string, error) {
+	if p.Version != "" {
+		dir := filepath.Join(typeDir, p.Name+"-"+p.Version)
+		if !isDir(dir) {
+			return "", fmt.Errorf("%w: %v (expected %v)", ErrNotFound, p, dir)
+		}
+		return dir, nil
+	}
+
+	entries, err := os.ReadDir(typeDir)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v (expected %v)", ErrNotFound, p, typeDir)
+	}
+	var versions []string
+	for _, e := range entries {
+		// The version starts with a number, so "foo-bar-1.0" is not a version of "foo".
+		// (The name is compared as is, since it can have glob characters.)
+		version := strings.TrimPrefix(e.Name(), p.Name+"-")
+		if version == e.Name() || version == "" || version[0] < '0' || version[0] > '9' {
+			continue
+		}
+		if isDir(filepath.Join(typeDir, e.Name())) {
+			versions = append(versions, version)
+		}
+	}
+	version, err := onlyVersion(p, typeDir, versions)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(typeDir, p.Name+"-"+version), nil
+}
+
+// onlyVersion returns the version when there is exactly one version in the mirror
+func onlyVersion(p PackageURL, dir string, versions []string) (string, error) {
+	switch len(versions) {
+	case 0:
+		return "", fmt.Errorf("%w: %v (no versions in %v)", ErrNotFound, p, dir)
+	case 1:
+		return versions[0], nil
+	}
+	sort.Strings(versions)
+	return "", fmt.Errorf("package URL %v requires a version (found %v)", p, strings.Join(versions, ", "))
+}
+
+// safePath is false for package URL components which are not within the mirror (e.g. "..")
+func safePath(s string) bool {
+	for _, segment := range strings.Split(s, "/") {
+		if segment == "." || segment == ".." || strings.ContainsAny(segment, `\`) {
+			return false
+		}
+	}
+	return true
+}
+
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
@@ -0,0 +1,234 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package purl
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Scheme is the URL scheme of every package URL
+const Scheme = "pkg"
+
+// Package types (the package managers) which can be resolved in a local mirror
+const (
+	TypeNPM   = "npm"
+	TypeMaven = "maven"
+	TypePyPI  = "pypi"
+	TypeGem   = "gem"
+	TypeCargo = "cargo"
+)
+
+var (
+	// ErrInvalid is wrapped by the errors for package URLs which can not be parsed
+	ErrInvalid = errors.New("invalid package URL")
+
+	// typeRegexp is the package URL type: ASCII letters, numbers, '.', '+' and '-', not starting with a number
+	typeRegexp = regexp.MustCompile(`^[a-zA-Z.+\-][a-zA-Z0-9.+\-]*$`)
+	// qualifierKeyRegexp is a qualifier key: ASCII letters, numbers, '.', '-' and '_', not starting with a number
+	qualifierKeyRegexp = regexp.MustCompile(`^[a-zA-Z.\-_][a-zA-Z0-9.\-_]*$`)
+	// pypiNameReplacer normalizes PyPI names, which are compared with "-" and "_" as the same
+	pypiNameReplacer = strings.NewReplacer("_", "-")
+	// escapeReplacer encodes '@' (the version separator) and keeps ':', which does not need to be encoded
+	escapeReplacer = strings.NewReplacer("@", "%40", "%3A", ":")
+)
+
+// PackageURL is a parsed package URL (purl), e.g. pkg:npm/%40angular/core@15.0.0
+// See https://github.com/package-url/purl-spec
+type PackageURL struct {
+	// Type is the package type or package manager (e.g. npm, maven, pypi)
+	Type string
+	// Namespace is the name prefix (e.g. the npm scope or maven group ID)
+	Namespace  string
+	Name       string
+	Version    string
+	Qualifiers map[string]string
+	// Subpath is a path within the package (e.g. a directory of the source)
+	Subpath string
+}
+
+// Parse parses and validates a package URL. The type and names are normalized for the known package types,
+// so the canonical String() of equivalent package URLs is the same.
+func Parse(s string) (PackageURL, error) {
+	var p PackageURL
+	invalid := func(reason string) (PackageURL, error) {
+		return PackageURL{}, fmt.Errorf("%w %q: %v", ErrInvalid, s, reason)
+	}
+
+	remainder := strings.TrimSpace(s)
+	scheme, remainder, found := strings.Cut(remainder, ":")
+	if !found || strings.ToLower(scheme) != Scheme {
+		return invalid(fmt.Sprintf("the scheme must be %q", Scheme))
+	}
+
+	// The subpath (after #) and qualifiers (after ?) are split from the right
+	if before, subpath, found := cutLast(remainder, "#"); found {
+		remainder = before
+		var segments []string
+		for _, segment := range strings.Split(subpath, "/") {
+			segment, err := url.PathUnescape(segment)
+			if err != nil {
+				return invalid(fmt.Sprintf("subpath: %v", err))
+			}
+			if segment != "" && segment != "." && segment != ".." {
+				segments = append(segments, segment)
+			}
+		}
+		p.Subpath = strings.Join(segments, "/")
+	}
+	if before, qualifiers, found := cutLast(remainder, "?"); found {
+		remainder = before
+		q, err := parseQualifiers(qualifiers)
+		if err != nil {
+			return invalid(err.Error())
+		}
+		p.Qualifiers = q
+	}
+
+	// The scheme may be followed by slashes (pkg://npm/...), which are ignored
+	remainder = strings.Trim(remainder, "/")
+
+	typ, remainder, found := strings.Cut(remainder, "/")
+	if !found {
+		return invalid("the type and name are required")
+	}
+	if !typeRegexp.MatchString(typ) {
+		return invalid(fmt.Sprintf("invalid type %q", typ))
+	}
+	p.Type = strings.ToLower(typ)
+
+	if before, version, found := cutLast(remainder, "@"); found && !strings.Contains(version, "/") {
+		v, err := url.PathUnescape(version)
+		if err != nil {
+			return invalid(fmt.Sprintf("version: %v", err))
+		}
+		p.Version = v
+		remainder = before
+	}
+
+	segments := strings.Split(strings.Trim(remainder, "/"), "/")
+	for i := range segments {
+		segment, err := url.PathUnescape(segments[i])
+		if err != nil {
+			return invalid(err.Error())
+		}
+		segments[i] = segment
+	}
+	p.Name = segments[len(segments)-1]
+	if p.Name == "" {
+		return invalid("the name is required")
+	}
+	var namespace []string
+	for _, segment := range segments[:len(segments)-1] {
+		if segment != "" {
+			namespace = append(namespace, segment)
+		}
+	}
+	p.Namespace = strings.Join(namespace, "/")
+
+	p.normalize()
+	if p.Type == TypeMaven && p.Namespace == "" {
+		return invalid("a maven package URL requires a namespace (the group ID)")
+	}
+	return p, nil
+}
+
+// normalize applies the type-specific rules from the purl spec
+func (p *PackageURL) normalize() {
+	switch p.Type {
+	case TypePyPI:
+		p.Name = pypiNameReplacer.Replace(strings.ToLower(p.Name))
+	case TypeNPM:
+		p.Namespace = strings.ToLower(p.Namespace)
+		p.Name = strings.ToLower(p.Name)
+	case "github", "bitbucket":
+		p.Namespace = strings.ToLower(p.Namespace)
+		p.Name = strings.ToLower(p.Name)
+	}
+}
+
+func parseQualifiers(s string) (map[string]string, error) {
+	qualifiers := make(map[string]string)
+	for _, pair := range strings.Split(s, "&") {
+		key, value, _ := strings.Cut(pair, "=")
+		if value == "" {
+			continue // A qualifier without a value is ignored
+		}
+		key = strings.ToLower(key)
+		if !qualifierKeyRegexp.MatchString(key) {
+			return nil, fmt.Errorf("invalid qualifier key %q", key)
+		}
+		if _, ok := qualifiers[key]; ok {
+			return nil, fmt.Errorf("duplicate qualifier key %q", key)
+		}
+		v, err := url.PathUnescape(value)
+		if err != nil {
+			return nil, fmt.Errorf("qualifier %v: %w", key, err)
+		}
+		qualifiers[key] = v
+	}
+	if len(qualifiers) == 0 {
+		return nil, nil
+	}
+	return qualifiers, nil
+}
+
+// cutLast slices s around the last instance of sep
+func cutLast(s, sep string) (before, after string, found bool) {
+	if i := strings.LastIndex(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):], true
+	}
+	return s, "", false
+}
+
+// String returns the canonical package URL
+func (p PackageURL) String() string {
+	var b strings.Builder
+	b.WriteString(Scheme + ":")
+	b.WriteString(p.Type)
+	b.WriteString("/")
+	if p.Namespace != "" {
+		for _, segment := range strings.Split(p.Namespace, "/") {
+			b.WriteString(escape(segment))
+			b.WriteString("/")
+		}
+	}
+	b.WriteString(escape(p.Name))
+	if p.Version != "" {
+		b.WriteString("@")
+		b.WriteString(escape(p.Version))
+	}
+	if len(p.Qualifiers) > 0 {
+		var keys []string
+		for k := range p.Qualifiers {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for i, k := range keys {
+			if i == 0 {
+				b.WriteString("?")
+			} else {
+				b.WriteString("&")
+			}
+			b.WriteString(k + "=" + escape(p.Qualifiers[k]))
+		}
+	}
+	if p.Subpath != "" {
+		b.WriteString("#")
+		var segments []string
+		for _, segment := range strings.Split(p.Subpath, "/") {
+			segments = append(segments, escape(segment))
+		}
+		b.WriteString(strings.Join(segments, "/"))
+	}
+	return b.String()
+}
+
+// escape percent-encodes a purl component
+func escape(s string) string {
+	return escapeReplacer.Replace(url.PathEscape(s))
+}
@@ -0,0 +1,110 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package purl
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const testMirror = "../testdata/mirror"
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		purl          string
+		want          PackageURL
+		wantCanonical string
+		wantErr       bool
+	}{
+		{purl: "pkg:npm/lodash@4.17.21", want: PackageURL{Type: TypeNPM, Name: "lodash", Version: "4.17.21"}, wantCanonical: "pkg:npm/lodash@4.17.21"},
+		{purl: "pkg:npm/%40angular/core@15.0.0", want: PackageURL{Type: TypeNPM, Namespace: "@angular", Name: "core", Version: "15.0.0"}, wantCanonical: "pkg:npm/%40angular/core@15.0.0"},
+		{purl: "pkg:npm/@Angular/Core", want: PackageURL{Type: TypeNPM, Namespace: "@angular", Name: "core"}, wantCanonical: "pkg:npm/%40angular/core"},
+		{purl: "pkg:maven/org.apache.commons/commons-lang3@3.12.0?type=jar&classifier=sources", want: PackageURL{Type: TypeMaven, Namespace: "org.apache.commons", Name: "commons-lang3", Version: "3.12.0", Qualifiers: map[string]string{"type": "jar", "classifier": "sources"}}, wantCanonical: "pkg:maven/org.apache.commons/commons-lang3@3.12.0?classifier=sources&type=jar"},
+		{purl: "pkg:PYPI/Django_Rest@1.0", want: PackageURL{Type: TypePyPI, Name: "django-rest", Version: "1.0"}, wantCanonical: "pkg:pypi/django-rest@1.0"},
+		{purl: "pkg:gem/rails@7.0.4#lib/rails", want: PackageURL{Type: TypeGem, Name: "rails", Version: "7.0.4", Subpath: "lib/rails"}, wantCanonical: "pkg:gem/rails@7.0.4#lib/rails"},
+		{purl: "pkg://cargo/serde@1.0.147#/src/../", want: PackageURL{Type: TypeCargo, Name: "serde", Version: "1.0.147", Subpath: "src"}, wantCanonical: "pkg:cargo/serde@1.0.147#src"},
+		{purl: "pkg:golang/github.com/spf13/cobra@v1.6.1", want: PackageURL{Type: "golang", Namespace: "github.com/spf13", Name: "cobra", Version: "v1.6.1"}, wantCanonical: "pkg:golang/github.com/spf13/cobra@v1.6.1"},
+		{purl: "npm/lodash@4.17.21", wantErr: true},
+		{purl: "pkg:npm", wantErr: true},
+		{purl: "pkg:npm/", wantErr: true},
+		{purl: "pkg:1npm/lodash", wantErr: true},
+		{purl: "pkg:maven/commons-lang3@3.12.0", wantErr: true},
+		{purl: "pkg:npm/lodash?type=a&type=b", wantErr: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.purl, func(t *testing.T) {
+			t.Parallel()
+			got, err := Parse(tt.purl)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				if !errors.Is(err, ErrInvalid) {
+					t.Errorf("Parse() error = %v, want ErrInvalid", err)
+				}
+				return
+			}
+			if d := cmp.Diff(tt.want, got); d != "" {
+				t.Errorf("Parse() (-want, +got): %v", d)
+			}
+			if got.String() != tt.wantCanonical {
+				t.Errorf("String() = %q, want %q", got.String(), tt.wantCanonical)
+			}
+		})
+	}
+}
+
+func TestMirror_Resolve(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		purl        string
+		want        string
+		wantErr     bool
+		wantMissing bool
+	}{
+		{purl: "pkg:npm/%40scope/example@1.0.0", want: "npm/@scope/example/1.0.0"},
+		{purl: "pkg:npm/%40scope/example", want: "npm/@scope/example/1.0.0"},
+		{purl: "pkg:maven/org.example/example@3.2.1", want: "maven/org/example/example/3.2.1"},
+		{purl: "pkg:pypi/Example_Pkg@1.2.3", want: "pypi/example-pkg/1.2.3"},
+		{purl: "pkg:gem/example", want: "gem/example-0.3.0"},
+		{purl: "pkg:cargo/example@0.1.0#src", want: "cargo/example-0.1.0/src"},
+		{purl: "pkg:npm/versions", wantErr: true},
+		{purl: "pkg:npm/%40scope/example@2.0.0", wantErr: true, wantMissing: true},
+		{purl: "pkg:cargo/missing", wantErr: true, wantMissing: true},
+		// Glob characters in the name do not match other packages
+		{purl: "pkg:gem/%2A", wantErr: true, wantMissing: true},
+		{purl: "pkg:gem/ex%3Fmple", wantErr: true, wantMissing: true},
+		{purl: "pkg:gem/%5Bexample", wantErr: true, wantMissing: true},
+		{purl: "pkg:cargo/example@0.1.0#tests", wantErr: true, wantMissing: true},
+		{purl: "pkg:npm/%2E%2E@1.0.0", wantErr: true},
+		{purl: "pkg:golang/github.com/spf13/cobra@v1.6.1", wantErr: true},
+	}
+	mirror := Mirror{Root: testMirror}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.purl, func(t *testing.T) {
+			t.Parallel()
+			p, err := Parse(tt.purl)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			got, err := mirror.Resolve(p)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Resolve() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if errors.Is(err, ErrNotFound) != tt.wantMissing {
+				t.Errorf("Resolve() error = %v, want ErrNotFound %v", err, tt.wantMissing)
+			}
+			if want := filepath.Join(testMirror, filepath.FromSlash(tt.want)); err == nil && got != want {
+				t.Errorf("Resolve() = %q, want %q", got, want)
+			}
+		})
+	}
+}
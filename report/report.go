@@ -0,0 +1,167 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package report
+
+import (
+	_ "embed"
+	"fmt"
+	"html/template"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"golang.org/x/exp/slices"
+
+	"github.com/CycloneDX/license-scanner/identifier"
+)
+
+// Span classes used to color-code the blocks
+const (
+	ClassLicense    = "license"
+	ClassCopyright  = "copyright"
+	ClassAuthor     = "author"
+	ClassKeyword    = "keyword"
+	ClassAcceptable = "acceptable"
+	ClassNone       = ""
+)
+
+//go:embed templates/report.html.tmpl
+var reportTemplate string
+
+var funcs = map[string]interface{}{
+	"join": strings.Join,
+}
+
+// Options for writing an HTML report
+type Options struct {
+	// Title is the heading of the report
+	Title string
+	// Root is the scanned directory. File and directory names are relative to Root.
+	Root string
+}
+
+// Report is the data passed to the HTML template
+type Report struct {
+	Title       string
+	Directories []Directory
+	Files       []File
+}
+
+// Directory summarizes the scanned files in one directory
+type Directory struct {
+	Name     string
+	Files    []*File
+	Licenses []string
+}
+
+// File is one scanned file with its text split into spans
+type File struct {
+	ID       string
+	Name     string
+	Licenses []string
+	Spans    []Span
+	Prev     *File
+	Next     *File
+}
+
+// Span is a block of the original text with the class used to color it
+type Span struct {
+	Text    string
+	Class   string
+	Matches []string
+}
+
+// New builds the report data from the scan results
+func New(results []identifier.IdentifierResults, options Options) *Report {
+	r := Report{Title: options.Title}
+	if r.Title == "" {
+		r.Title = "License Scan Report"
+	}
+
+	sorted := make([]identifier.IdentifierResults, len(results))
+	copy(sorted, results)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].File < sorted[j].File })
+
+	r.Files = make([]File, len(sorted))
+	for i, result := range sorted {
+		f := &r.Files[i]
+		f.ID = fmt.Sprintf("file-%d", i+1)
+		f.Name = identifier.RelativePath(options.Root, result.File)
+		for id := range result.Matches {
+			f.Licenses = append(f.Licenses, id)
+		}
+		sort.Strings(f.Licenses)
+		f.Spans = spans(result)
+		if i > 0 {
+			f.Prev = &r.Files[i-1]
+			r.Files[i-1].Next = f
+		}
+	}
+
+	byDir := make(map[string]*Directory)
+	var dirs []string
+	for i := range r.Files {
+		f := &r.Files[i]
+		name := filepath.ToSlash(filepath.Dir(f.Name))
+		d, ok := byDir[name]
+		if !ok {
+			d = &Directory{Name: name}
+			byDir[name] = d
+			dirs = append(dirs, name)
+		}
+		d.Files = append(d.Files, f)
+		for _, id := range f.Licenses {
+			if !slices.Contains(d.Licenses, id) {
+				d.Licenses = append(d.Licenses, id)
+			}
+		}
+	}
+	sort.Strings(dirs)
+	for _, name := range dirs {
+		sort.Strings(byDir[name].Licenses)
+		r.Directories = append(r.Directories, *byDir[name])
+	}
+
+	return &r
+}
+
+// spans uses the blocks (or the whole original text, without blocks) as spans classified by their matches
+func spans(result identifier.IdentifierResults) []Span {
+	if len(result.Blocks) == 0 {
+		return []Span{{Text: result.OriginalText}}
+	}
+	var ret []Span
+	for _, block := range result.Blocks {
+		ret = append(ret, Span{Text: block.Text, Class: class(block.Matches), Matches: block.Matches})
+	}
+	return ret
+}
+
+// class returns the span class for the block matches (anything else is a license ID)
+func class(matches []string) string {
+	if len(matches) == 0 {
+		return ClassNone
+	}
+	switch matches[0] {
+	case "COPYRIGHT":
+		return ClassCopyright
+	case "AUTHOR":
+		return ClassAuthor
+	case "KEYWORD":
+		return ClassKeyword
+	case "ACCEPTABLE":
+		return ClassAcceptable
+	default:
+		return ClassLicense
+	}
+}
+
+// WriteHTML writes the report as a single self-contained HTML file
+func (r *Report) WriteHTML(w io.Writer) error {
+	t, err := template.New("report").Funcs(funcs).Parse(reportTemplate)
+	if err != nil {
+		return err
+	}
+	return t.Execute(w, r)
+}
@@ -0,0 +1,99 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package report
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/CycloneDX/license-scanner/identifier"
+)
+
+func testResults() []identifier.IdentifierResults {
+	return []identifier.IdentifierResults{
+		{
+			File:    "vendor/foo/main.go",
+			Matches: map[string][]identifier.Match{},
+			Blocks:  []identifier.Block{{Text: "package <main>\n"}},
+		},
+		{
+			File:    "vendor/foo/LICENSE",
+			Matches: map[string][]identifier.Match{"MIT": {{Begins: 23, Ends: 40}}},
+			Blocks: []identifier.Block{
+				{Text: "Copyright 2019 Foo Inc", Matches: []string{"COPYRIGHT"}},
+				{Text: "\n"},
+				{Text: "Permission is granted", Matches: []string{"MIT"}},
+				{Text: "\n\n", Matches: []string{"ACCEPTABLE"}},
+				{Text: "Proprietary", Matches: []string{"KEYWORD"}},
+			},
+		},
+		{
+			File:    "vendor/bar/LICENSE",
+			Matches: map[string][]identifier.Match{"Apache-2.0": {{Begins: 0, Ends: 10}}},
+			Blocks:  []identifier.Block{{Text: "Apache License", Matches: []string{"Apache-2.0"}}},
+		},
+	}
+}
+
+func TestNew(t *testing.T) {
+	r := New(testResults(), Options{Root: "vendor"})
+
+	var names []string
+	for _, f := range r.Files {
+		names = append(names, f.ID+":"+f.Name)
+	}
+	if diff := cmp.Diff([]string{"file-1:bar/LICENSE", "file-2:foo/LICENSE", "file-3:foo/main.go"}, names); diff != "" {
+		t.Errorf("Files are not sorted (-want, +got): %v", diff)
+	}
+	if r.Files[0].Prev != nil || r.Files[0].Next != &r.Files[1] || r.Files[2].Prev != &r.Files[1] || r.Files[2].Next != nil {
+		t.Error("Files are not linked to the previous and next files")
+	}
+
+	var classes []string
+	for _, s := range r.Files[1].Spans {
+		classes = append(classes, s.Class)
+	}
+	if diff := cmp.Diff([]string{ClassCopyright, ClassNone, ClassLicense, ClassAcceptable, ClassKeyword}, classes); diff != "" {
+		t.Errorf("Didn't get expected span classes (-want, +got): %v", diff)
+	}
+
+	if len(r.Directories) != 2 {
+		t.Fatalf("Expected 2 directories got %v", len(r.Directories))
+	}
+	foo := r.Directories[1]
+	if foo.Name != "foo" || len(foo.Files) != 2 || !cmp.Equal([]string{"MIT"}, foo.Licenses) {
+		t.Errorf("Didn't get expected directory summary: %+v", foo)
+	}
+}
+
+func TestReport_WriteHTML(t *testing.T) {
+	var b bytes.Buffer
+	if err := New(testResults(), Options{Root: "vendor", Title: "ACME Report"}).WriteHTML(&b); err != nil {
+		t.Fatalf("WriteHTML() error = %v", err)
+	}
+	got := b.String()
+	for _, expected := range []string{
+		"<title>ACME Report</title>",
+		`<a href="#file-2">foo/LICENSE</a> (MIT)`,
+		`<span class="copyright" title="COPYRIGHT">Copyright 2019 Foo Inc</span>`,
+		`<span class="license" title="MIT">Permission is granted</span>`,
+		`<span class="keyword" title="KEYWORD">Proprietary</span>`,
+		`<a href="#file-1">previous: bar/LICENSE</a>`,
+		"package &lt;main&gt;",
+	} {
+		if !strings.Contains(got, expected) {
+			t.Errorf("WriteHTML() expected output containing %q", expected)
+		}
+	}
+	// Self-contained: no external stylesheets, scripts, or images
+	for _, unexpected := range []string{"<link", "<script", "<img", "http://", "https://"} {
+		if strings.Contains(got, unexpected) {
+			t.Errorf("WriteHTML() output should not contain %q", unexpected)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var (
+	// zipSuffixes are the archives in zip format (including Java, Python wheel, and NuGet packages)
+	zipSuffixes = []string{".zip", ".jar", ".war", ".whl", ".nupkg"}
+	// tarGzipSuffixes are the gzipped tar archives (including Cargo crates and npm packages)
+	tarGzipSuffixes = []string{".tar.gz", ".tgz", ".crate"}
+
+	errTooLarge = errors.New("the extracted files are too large")
+	errTooMany  = errors.New("too many files in the archive")
+	// errInvalidArchive is wrapped by the errors for archives which can not be extracted
+	errInvalidArchive = errors.New("invalid archive")
+)
+
+// isArchive is true for a file name with an archive suffix, which is extracted and scanned as a directory
+func isArchive(name string) bool {
+	return hasSuffix(name, zipSuffixes) || hasSuffix(name, tarGzipSuffixes) || hasSuffix(name, []string{".tar"})
+}
+
+func hasSuffix(name string, suffixes []string) bool {
+	name = strings.ToLower(name)
+	for _, suffix := range suffixes {
+		if strings.HasSuffix(name, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// extract extracts the regular files of the archive into dir within the size and file count limits of the options.
+// Entries with paths outside of dir (e.g. "../x" or absolute paths) and links are skipped.
+func extract(name string, r io.ReaderAt, size int64, dir string, options Options) error {
+	e := extractor{dir: dir, bytesLeft: options.MaxExtractedBytes, filesLeft: options.MaxExtractedFiles}
+
+	if hasSuffix(name, zipSuffixes) {
+		zr, err := zip.NewReader(r, size)
+		if err != nil {
+			return err
+		}
+		for _, f := range zr.File {
+			if !f.Mode().IsRegular() {
+				continue
+			}
+			if err := e.extractZipFile(f); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	var tr *tar.Reader
+	sr := io.NewSectionReader(r, 0, size)
+	if hasSuffix(name, tarGzipSuffixes) {
+		gr, err := gzip.NewReader(sr)
+		if err != nil {
+			return err
+		}
+		defer gr.Close()
+		tr = tar.NewReader(gr)
+	} else {
+		tr = tar.NewReader(sr)
+	}
+	for {
+		h, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if h.Typeflag != tar.TypeReg {
+			continue
+		}
+		if err := e.write(h.Name, tr); err != nil {
+			return err
+		}
+	}
+}
+
+type extractor struct {
+	dir       string
+	bytesLeft int64
+	filesLeft int
+}
+
+func (e *extractor) extractZipFile(f *zip.File) error {
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	return e.write(f.Name, rc)
+}
+
+// write writes one file from the archive, unless its path is outside of the directory
+func (e *extractor) write(name string, r io.Reader) error {
+	p := filepath.Join(e.dir, filepath.FromSlash(name))
+	if !strings.HasPrefix(p, filepath.Clean(e.dir)+string(os.PathSeparator)) {
+		return nil
+	}
+
+	if e.filesLeft <= 0 {
+		return errTooMany
+	}
+	e.filesLeft--
+
+	if err := os.MkdirAll(filepath.Dir(p), 0o700); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(p, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	// Copy one more byte than is left, to know when the limit is exceeded
+	n, err := io.Copy(f, io.LimitReader(r, e.bytesLeft+1))
+	if err != nil {
+		return fmt.Errorf("%v: %w", name, err)
+	}
+	e.bytesLeft -= n
+	if e.bytesLeft < 0 {
+		return errTooLarge
+	}
+	return f.Close()
+}
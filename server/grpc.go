@@ -0,0 +1,234 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/CycloneDX/license-scanner/api/scanner"
+	"github.com/CycloneDX/license-scanner/api/scannerpb"
+	"github.com/CycloneDX/license-scanner/licenses"
+	"github.com/CycloneDX/license-scanner/normalizer"
+)
+
+// GRPCServer returns a gRPC server with the scannerpb.Scanner service, which shares the license library,
+// options, and scan limits of the REST API. Messages are limited to the MaxRequestBytes option.
+func (s *Server) GRPCServer(opts ...grpc.ServerOption) *grpc.Server {
+	opts = append([]grpc.ServerOption{grpc.MaxRecvMsgSize(int(s.options.MaxRequestBytes))}, opts...)
+	g := grpc.NewServer(opts...)
+	scannerpb.RegisterScannerServer(g, &grpcService{s: s})
+	return g
+}
+
+// grpcService implements the scannerpb.Scanner service
+type grpcService struct {
+	scannerpb.UnimplementedScannerServer
+	s *Server
+}
+
+// start returns the license library once a scan slot is acquired, or an UNAVAILABLE error when it is not loaded yet.
+// The slot is released with s.release().
+func (g *grpcService) start(ctx context.Context) (*licenses.LicenseLibrary, error) {
+	licenseLibrary := g.s.library()
+	if licenseLibrary == nil {
+		return nil, status.Error(codes.Unavailable, "the license library is loading")
+	}
+	if err := g.s.acquire(ctx); err != nil {
+		return nil, status.FromContextError(err).Err()
+	}
+	return licenseLibrary, nil
+}
+
+func (g *grpcService) Scan(ctx context.Context, req *scannerpb.ScanRequest) (*scannerpb.ScanResponse, error) {
+	if len(req.GetSpecs()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "the scan request has no specs")
+	}
+	licenseLibrary, err := g.start(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer g.s.release()
+
+	resultsCache := make(map[normalizer.Digest]*scanner.ScanResult)
+	response := &scannerpb.ScanResponse{}
+	for i, spec := range req.GetSpecs() {
+		result := g.s.scan(fromProtoSpec(spec), licenseLibrary, resultsCache)
+		response.Results = append(response.Results, toProtoResult(i, result))
+	}
+	return response, nil
+}
+
+func (g *grpcService) ScanBatch(req *scannerpb.ScanRequest, stream scannerpb.Scanner_ScanBatchServer) error {
+	if len(req.GetSpecs()) == 0 {
+		return status.Error(codes.InvalidArgument, "the scan request has no specs")
+	}
+	licenseLibrary, err := g.start(stream.Context())
+	if err != nil {
+		return err
+	}
+	defer g.s.release()
+
+	resultsCache := make(map[normalizer.Digest]*scanner.ScanResult)
+	for i, spec := range req.GetSpecs() {
+		if err := stream.Context().Err(); err != nil {
+			return status.FromContextError(err).Err()
+		}
+		result := g.s.scan(fromProtoSpec(spec), licenseLibrary, resultsCache)
+		if err := stream.Send(toProtoResult(i, result)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (g *grpcService) ScanFile(stream scannerpb.Scanner_ScanFileServer) error {
+	req, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	info := req.GetInfo()
+	if info == nil || info.GetFilename() == "" {
+		return status.Error(codes.InvalidArgument, "the first message must have the file name")
+	}
+
+	// The content is limited in memory, like the multipart upload of the REST API
+	var content bytes.Buffer
+	for {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if req.GetInfo() != nil {
+			return status.Error(codes.InvalidArgument, "only the first message may have the file name")
+		}
+		if int64(content.Len()+len(req.GetChunk())) > g.s.options.MaxRequestBytes {
+			return status.Errorf(codes.ResourceExhausted, "the file is larger than %v bytes", g.s.options.MaxRequestBytes)
+		}
+		content.Write(req.GetChunk())
+	}
+
+	licenseLibrary, err := g.start(stream.Context())
+	if err != nil {
+		return err
+	}
+	defer g.s.release()
+
+	spec := scanner.ScanSpec{Name: info.GetFilename(), DeclaredLicense: info.GetDeclaredLicense()}
+	if info.GetName() != "" {
+		spec.Name = info.GetName()
+	}
+	result, err := g.s.scanFile(spec, info.GetFilename(), bytes.NewReader(content.Bytes()), int64(content.Len()), licenseLibrary)
+	if err != nil {
+		code := codes.Internal
+		switch {
+		case errors.Is(err, errTooLarge) || errors.Is(err, errTooMany):
+			code = codes.ResourceExhausted
+		case errors.Is(err, errInvalidArchive):
+			code = codes.InvalidArgument
+		}
+		return status.Error(code, err.Error())
+	}
+	return stream.SendAndClose(toProtoResult(0, result))
+}
+
+func (g *grpcService) ListLicenses(_ context.Context, req *scannerpb.ListLicensesRequest) (*scannerpb.ListLicensesResponse, error) {
+	licenseLibrary := g.s.library()
+	if licenseLibrary == nil {
+		return nil, status.Error(codes.Unavailable, "the license library is loading")
+	}
+	lics, deprecatedLics, exceptions, deprecatedExceptions := licenseLibrary.List()
+	response := &scannerpb.ListLicensesResponse{SpdxVersion: licenseLibrary.SPDXVersion}
+	for _, l := range lics {
+		response.Licenses = append(response.Licenses, toProtoDetail(l, false))
+	}
+	for _, e := range exceptions {
+		response.Exceptions = append(response.Exceptions, toProtoException(e, false))
+	}
+	if req.GetIncludeDeprecated() {
+		for _, l := range deprecatedLics {
+			response.Licenses = append(response.Licenses, toProtoDetail(l, true))
+		}
+		for _, e := range deprecatedExceptions {
+			response.Exceptions = append(response.Exceptions, toProtoException(e, true))
+		}
+	}
+	return response, nil
+}
+
+func fromProtoSpec(spec *scannerpb.ScanSpec) scanner.ScanSpec {
+	return scanner.ScanSpec{
+		Name:            spec.GetName(),
+		Version:         spec.GetVersion(),
+		PURL:            spec.GetPurl(),
+		LicenseText:     spec.GetLicenseText(),
+		DeclaredLicense: spec.GetDeclaredLicense(),
+	}
+}
+
+// toProtoResult converts the scan result of the specification at the index.
+// The license text is not included (the licenses are identified by ID).
+func toProtoResult(index int, r *scanner.ScanResult) *scannerpb.ScanResult {
+	result := &scannerpb.ScanResult{
+		Index: int32(index),
+		Spec: &scannerpb.ScanSpec{
+			Name:            r.Spec.Name,
+			Version:         r.Spec.Version,
+			Purl:            r.Spec.PURL,
+			DeclaredLicense: r.Spec.DeclaredLicense,
+		},
+		Files: r.Files,
+	}
+	if r.Hash != nil {
+		result.Hash = &scannerpb.Digest{Md5: r.Hash.Md5, Sha256: r.Hash.Sha256, Sha512: r.Hash.Sha512}
+	}
+	for _, l := range r.CycloneDXLicenses {
+		if l.License != nil {
+			result.Licenses = append(result.Licenses, &scannerpb.License{Id: l.License.ID, Name: l.License.Name, Url: l.License.URL})
+		}
+	}
+	if r.Declared != nil {
+		result.Declared = &scannerpb.Comparison{
+			Declared:   r.Declared.Declared,
+			Detected:   r.Declared.Detected,
+			Undeclared: r.Declared.Undeclared,
+			Outcome:    string(r.Declared.Outcome),
+			Reason:     r.Declared.Reason,
+		}
+	}
+	if r.Error != nil {
+		result.Error = r.Error.Error()
+	}
+	return result
+}
+
+func toProtoDetail(l licenses.Detail, deprecated bool) *scannerpb.LicenseDetail {
+	return &scannerpb.LicenseDetail{
+		Id:            l.ID,
+		Name:          l.Name,
+		Family:        l.Family,
+		NumTemplates:  int32(l.NumTemplates),
+		IsOsiApproved: l.IsOSIApproved,
+		IsFsfLibre:    l.IsFSFLibre,
+		IsDeprecated:  deprecated,
+	}
+}
+
+func toProtoException(e licenses.Exception, deprecated bool) *scannerpb.LicenseDetail {
+	return &scannerpb.LicenseDetail{
+		Id:           e.ID,
+		Name:         e.Name,
+		Family:       e.Family,
+		NumTemplates: int32(e.NumTemplates),
+		IsDeprecated: deprecated,
+	}
+}
@@ -0,0 +1,480 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/CycloneDX/cyclonedx-go"
+	"github.com/CycloneDX/license-scanner/api/scanner"
+	"github.com/CycloneDX/license-scanner/licenses"
+	"github.com/CycloneDX/license-scanner/manifest"
+	"github.com/CycloneDX/license-scanner/metrics"
+	"github.com/CycloneDX/license-scanner/normalizer"
+	"github.com/CycloneDX/license-scanner/purl"
+)
+
+// Defaults for the Options which are not set
+const (
+	DefaultMaxRequestBytes   = 10 << 20
+	DefaultMaxExtractedFiles = 10000
+)
+
+// Formats of the scan results
+const (
+	FormatJSON      = "json"
+	FormatCycloneDX = "cyclonedx"
+
+	mediaTypeCycloneDX = "application/vnd.cyclonedx+json"
+)
+
+// Options limit the requests and configure the scans
+type Options struct {
+	// MaxRequestBytes limits the size of a request body (the license text, JSON, or uploaded file)
+	MaxRequestBytes int64
+	// MaxExtractedBytes limits the total size of the files extracted from an uploaded archive.
+	// The default is 10 times MaxRequestBytes.
+	MaxExtractedBytes int64
+	// MaxExtractedFiles limits the number of files extracted from an uploaded archive
+	MaxExtractedFiles int
+	// MaxConcurrentScans limits the number of scans at the same time (the other scan requests wait).
+	// The default is the number of CPUs.
+	MaxConcurrentScans int
+	// Mirror is used to scan the packages of scan specifications with a package URL and no license text
+	Mirror purl.Mirror
+}
+
+// Server exposes the api/scanner functionality over HTTP using a shared license library.
+// The server is not ready (and the scan endpoints return 503) until the license library is set.
+type Server struct {
+	options Options
+	scans   chan struct{}
+
+	mu             sync.RWMutex
+	licenseLibrary *licenses.LicenseLibrary
+}
+
+// New returns a server with the options (using the defaults for options which are not set)
+func New(options Options) *Server {
+	if options.MaxRequestBytes <= 0 {
+		options.MaxRequestBytes = DefaultMaxRequestBytes
+	}
+	if options.MaxExtractedBytes <= 0 {
+		options.MaxExtractedBytes = 10 * options.MaxRequestBytes
+	}
+	if options.MaxExtractedFiles <= 0 {
+		options.MaxExtractedFiles = DefaultMaxExtractedFiles
+	}
+	if options.MaxConcurrentScans <= 0 {
+		options.MaxConcurrentScans = runtime.NumCPU()
+	}
+	return &Server{
+		options: options,
+		scans:   make(chan struct{}, options.MaxConcurrentScans),
+	}
+}
+
+// SetLicenseLibrary sets the loaded license library shared by all the requests, which makes the server ready
+func (s *Server) SetLicenseLibrary(licenseLibrary *licenses.LicenseLibrary) {
+	// The validate requests share the resolver of the library, which is built before the server is ready
+	manifest.LoadResolver(licenseLibrary)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.licenseLibrary = licenseLibrary
+}
+
+func (s *Server) library() *licenses.LicenseLibrary {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.licenseLibrary
+}
+
+// Handler returns the HTTP handler for the REST API:
+//
+//	GET  /healthz                   the server is running
+//	GET  /readyz                    the license library is loaded
+//	POST /v1/scan                   scan license text (text/plain) or scan specifications (application/json)
+//	POST /v1/scan/file              scan an uploaded file or archive (multipart/form-data "file")
+//	GET  /v1/licenses               list the licenses and exceptions in the license library
+//	POST /v1/expressions/validate   validate and normalize a license expression
+//	GET  /metrics                   the metrics in the Prometheus text format
+//
+// The scan results are JSON, or a CycloneDX BOM with ?format=cyclonedx (or Accept: application/vnd.cyclonedx+json).
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", method(http.MethodGet, s.handleHealth))
+	mux.HandleFunc("/readyz", method(http.MethodGet, s.handleReady))
+	mux.HandleFunc("/v1/scan", method(http.MethodPost, s.ready(s.handleScan)))
+	mux.HandleFunc("/v1/scan/file", method(http.MethodPost, s.ready(s.handleScanFile)))
+	mux.HandleFunc("/v1/licenses", method(http.MethodGet, s.ready(s.handleLicenses)))
+	mux.HandleFunc("/v1/expressions/validate", method(http.MethodPost, s.ready(s.handleValidate)))
+	mux.HandleFunc("/metrics", method(http.MethodGet, metrics.Default.Handler().ServeHTTP))
+	return mux
+}
+
+// ScanSpec is a scan specification in a scan request
+type ScanSpec struct {
+	Name            string `json:"name,omitempty"`
+	Version         string `json:"version,omitempty"`
+	PURL            string `json:"purl,omitempty"`
+	LicenseText     string `json:"licenseText,omitempty"`
+	DeclaredLicense string `json:"declaredLicense,omitempty"`
+}
+
+// ScanRequest is the JSON body of a scan request
+type ScanRequest struct {
+	Specs []ScanSpec `json:"specs"`
+}
+
+// ScanResult is the JSON result of one scan specification
+type ScanResult struct {
+	Name     string               `json:"name,omitempty"`
+	Version  string               `json:"version,omitempty"`
+	PURL     string               `json:"purl,omitempty"`
+	Hash     *normalizer.Digest   `json:"hash,omitempty"`
+	Licenses scanner.Licenses     `json:"licenses"`
+	Declared *manifest.Comparison `json:"declared,omitempty"`
+	Files    []string             `json:"files,omitempty"`
+	Error    string               `json:"error,omitempty"`
+}
+
+// ScanResponse is the JSON response of a scan request
+type ScanResponse struct {
+	Results []ScanResult `json:"results"`
+}
+
+// ValidateRequest is the JSON body of an expression validation request
+type ValidateRequest struct {
+	Expression string `json:"expression"`
+}
+
+// ValidateResponse is the JSON response of an expression validation request
+type ValidateResponse struct {
+	Valid bool `json:"valid"`
+	// Expression is the normalized SPDX expression (when valid)
+	Expression string   `json:"expression,omitempty"`
+	IDs        []string `json:"ids,omitempty"`
+	Unresolved []string `json:"unresolved,omitempty"`
+}
+
+// LicensesResponse is the JSON response of the license list
+type LicensesResponse struct {
+	SPDXVersion          string               `json:"spdxVersion,omitempty"`
+	Licenses             []licenses.Detail    `json:"licenses"`
+	Exceptions           []licenses.Exception `json:"exceptions"`
+	DeprecatedLicenses   []licenses.Detail    `json:"deprecatedLicenses"`
+	DeprecatedExceptions []licenses.Exception `json:"deprecatedExceptions"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// method only allows requests with the HTTP method
+func method(m string, h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != m {
+			w.Header().Set("Allow", m)
+			writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %v is not allowed", r.Method))
+			return
+		}
+		h(w, r)
+	}
+}
+
+// ready returns 503 until the license library is set
+func (s *Server) ready(h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if s.library() == nil {
+			writeError(w, http.StatusServiceUnavailable, errors.New("the license library is loading"))
+			return
+		}
+		h(w, r)
+	}
+}
+
+func (s *Server) handleHealth(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+func (s *Server) handleReady(w http.ResponseWriter, _ *http.Request) {
+	if s.library() == nil {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]string{"status": "loading"})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ready"})
+}
+
+func (s *Server) handleScan(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, s.options.MaxRequestBytes))
+	if err != nil {
+		writeRequestError(w, err)
+		return
+	}
+
+	var specs []ScanSpec
+	if mediaType(r.Header.Get("Content-Type")) == "application/json" {
+		var req ScanRequest
+		if err := json.Unmarshal(body, &req); err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid scan request: %w", err))
+			return
+		}
+		if len(req.Specs) == 0 {
+			writeError(w, http.StatusBadRequest, errors.New("the scan request has no specs"))
+			return
+		}
+		specs = req.Specs
+	} else {
+		// The body is the license text
+		q := r.URL.Query()
+		specs = []ScanSpec{{Name: q.Get("name"), LicenseText: string(body), DeclaredLicense: q.Get("declaredLicense")}}
+	}
+
+	if err := s.acquire(r.Context()); err != nil {
+		writeError(w, http.StatusServiceUnavailable, err)
+		return
+	}
+	defer s.release()
+
+	licenseLibrary := s.library()
+	resultsCache := make(map[normalizer.Digest]*scanner.ScanResult)
+	var results []*scanner.ScanResult
+	for _, spec := range specs {
+		scanSpec := scanner.ScanSpec{Name: spec.Name, Version: spec.Version, PURL: spec.PURL, LicenseText: spec.LicenseText, DeclaredLicense: spec.DeclaredLicense}
+		results = append(results, s.scan(scanSpec, licenseLibrary, resultsCache))
+	}
+	s.writeResults(w, r, results)
+}
+
+// scan scans the license text of the specification, or the package in the mirror when there is only a package URL
+func (s *Server) scan(spec scanner.ScanSpec, licenseLibrary *licenses.LicenseLibrary, resultsCache map[normalizer.Digest]*scanner.ScanResult) *scanner.ScanResult {
+	if spec.LicenseText == "" && spec.PURL != "" && s.options.Mirror.Root != "" {
+		_, result := spec.ScanPackage(s.options.Mirror, licenseLibrary)
+		return result
+	}
+	return spec.ScanLicenseText(licenseLibrary, resultsCache)
+}
+
+// scanFile scans the content of an uploaded file. An archive is extracted to a temporary directory
+// and scanned as a directory (with the file paths of the result relative to the archive).
+func (s *Server) scanFile(spec scanner.ScanSpec, filename string, r io.ReaderAt, size int64, licenseLibrary *licenses.LicenseLibrary) (*scanner.ScanResult, error) {
+	if !isArchive(filename) {
+		text, err := io.ReadAll(io.NewSectionReader(r, 0, size))
+		if err != nil {
+			return nil, err
+		}
+		spec.LicenseText = normalizer.Decode(text).Text
+		return spec.ScanLicenseText(licenseLibrary, make(map[normalizer.Digest]*scanner.ScanResult)), nil
+	}
+
+	dir, err := os.MkdirTemp("", "license-scanner-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	if err := extract(filename, r, size, dir, s.options); err != nil {
+		if errors.Is(err, errTooLarge) || errors.Is(err, errTooMany) {
+			return nil, fmt.Errorf("archive %v: %w", filename, err)
+		}
+		return nil, fmt.Errorf("%w %v: %v", errInvalidArchive, filename, err)
+	}
+	result := spec.ScanDirectory(dir, licenseLibrary)
+	for i, f := range result.Files {
+		if rel, err := filepath.Rel(dir, f); err == nil {
+			result.Files[i] = filepath.ToSlash(rel)
+		}
+	}
+	return result, nil
+}
+
+func (s *Server) handleScanFile(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, s.options.MaxRequestBytes)
+	if err := r.ParseMultipartForm(s.options.MaxRequestBytes); err != nil {
+		writeRequestError(w, err)
+		return
+	}
+	defer func() { _ = r.MultipartForm.RemoveAll() }()
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("a \"file\" is required: %w", err))
+		return
+	}
+	defer file.Close()
+
+	spec := scanner.ScanSpec{
+		Name:            header.Filename,
+		DeclaredLicense: r.FormValue("declaredLicense"),
+	}
+	if name := r.FormValue("name"); name != "" {
+		spec.Name = name
+	}
+
+	if err := s.acquire(r.Context()); err != nil {
+		writeError(w, http.StatusServiceUnavailable, err)
+		return
+	}
+	defer s.release()
+
+	result, err := s.scanFile(spec, header.Filename, file, header.Size, s.library())
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, errTooLarge) || errors.Is(err, errTooMany):
+			status = http.StatusRequestEntityTooLarge
+		case errors.Is(err, errInvalidArchive):
+			status = http.StatusBadRequest
+		}
+		writeError(w, status, err)
+		return
+	}
+	s.writeResults(w, r, []*scanner.ScanResult{result})
+}
+
+func (s *Server) handleLicenses(w http.ResponseWriter, _ *http.Request) {
+	licenseLibrary := s.library()
+	lics, deprecatedLics, exceptions, deprecatedExceptions := licenseLibrary.List()
+	writeJSON(w, http.StatusOK, LicensesResponse{
+		SPDXVersion:          licenseLibrary.SPDXVersion,
+		Licenses:             lics,
+		Exceptions:           exceptions,
+		DeprecatedLicenses:   deprecatedLics,
+		DeprecatedExceptions: deprecatedExceptions,
+	})
+}
+
+func (s *Server) handleValidate(w http.ResponseWriter, r *http.Request) {
+	var req ValidateRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, s.options.MaxRequestBytes)).Decode(&req); err != nil {
+		writeRequestError(w, err)
+		return
+	}
+	expression, ids, unresolved := manifest.ResolveLicense(req.Expression, s.library())
+	writeJSON(w, http.StatusOK, ValidateResponse{
+		Valid:      expression != "" && len(unresolved) == 0,
+		Expression: expression,
+		IDs:        ids,
+		Unresolved: unresolved,
+	})
+}
+
+// acquire waits for a scan slot (or for the request to be canceled)
+func (s *Server) acquire(ctx context.Context) error {
+	select {
+	case s.scans <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("waiting to scan: %w", ctx.Err())
+	}
+}
+
+func (s *Server) release() {
+	<-s.scans
+}
+
+// writeResults writes the scan results as JSON or a CycloneDX BOM
+func (s *Server) writeResults(w http.ResponseWriter, r *http.Request, results []*scanner.ScanResult) {
+	format := r.URL.Query().Get("format")
+	if format == "" && strings.Contains(r.Header.Get("Accept"), mediaTypeCycloneDX) {
+		format = FormatCycloneDX
+	}
+
+	switch format {
+	case "", FormatJSON:
+		response := ScanResponse{Results: []ScanResult{}}
+		for _, result := range results {
+			response.Results = append(response.Results, toScanResult(result))
+		}
+		writeJSON(w, http.StatusOK, response)
+	case FormatCycloneDX:
+		w.Header().Set("Content-Type", mediaTypeCycloneDX)
+		w.WriteHeader(http.StatusOK)
+		_ = cyclonedx.NewBOMEncoder(w, cyclonedx.BOMFileFormatJSON).SetPretty(true).Encode(toBOM(results))
+	default:
+		writeError(w, http.StatusBadRequest, fmt.Errorf("unknown format %q (use %v or %v)", format, FormatJSON, FormatCycloneDX))
+	}
+}
+
+func toScanResult(r *scanner.ScanResult) ScanResult {
+	result := ScanResult{
+		Name:     r.Spec.Name,
+		Version:  r.Spec.Version,
+		PURL:     r.Spec.PURL,
+		Hash:     r.Hash,
+		Licenses: r.CycloneDXLicenses,
+		Declared: r.Declared,
+		Files:    r.Files,
+	}
+	if result.Licenses == nil {
+		result.Licenses = scanner.Licenses{}
+	}
+	if r.Error != nil {
+		result.Error = r.Error.Error()
+	}
+	return result
+}
+
+// toBOM returns a CycloneDX BOM with a component for each scan result
+func toBOM(results []*scanner.ScanResult) *cyclonedx.BOM {
+	bom := cyclonedx.NewBOM()
+	components := []cyclonedx.Component{}
+	for i, r := range results {
+		name := r.Spec.Name
+		if name == "" {
+			name = fmt.Sprintf("scan-%d", i+1)
+		}
+		c := cyclonedx.Component{
+			BOMRef:     fmt.Sprintf("scan-%d", i+1),
+			Type:       cyclonedx.ComponentTypeLibrary,
+			Name:       name,
+			Version:    r.Spec.Version,
+			PackageURL: r.Spec.PURL,
+		}
+		if len(r.CycloneDXLicenses) > 0 {
+			l := cyclonedx.Licenses(r.CycloneDXLicenses)
+			c.Licenses = &l
+		}
+		components = append(components, c)
+	}
+	bom.Components = &components
+	return bom
+}
+
+func mediaType(contentType string) string {
+	t, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return ""
+	}
+	return t
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, errorResponse{Error: err.Error()})
+}
+
+// writeRequestError writes 413 when the request body is too large (the http.MaxBytesReader error), otherwise 400
+func writeRequestError(w http.ResponseWriter, err error) {
+	if strings.Contains(err.Error(), "request body too large") {
+		writeError(w, http.StatusRequestEntityTooLarge, err)
+		return
+	}
+	writeError(w, http.StatusBadRequest, err)
+}
@@ -0,0 +1,306 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package server
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/CycloneDX/license-scanner/licenses"
+	"github.com/CycloneDX/license-scanner/manifest"
+)
+
+const mitLicenseFile = "../testdata/manifests/npm/LICENSE"
+
+func testServer(t *testing.T, options Options) *httptest.Server {
+	t.Helper()
+	licenseLibrary, err := licenses.NewLicenseLibrary(nil)
+	if err != nil {
+		t.Fatalf("NewLicenseLibrary() error = %v", err)
+	}
+	if err := licenseLibrary.AddAll(); err != nil {
+		t.Fatalf("licenseLibrary.AddAll() error = %v", err)
+	}
+	s := New(options)
+	s.SetLicenseLibrary(licenseLibrary)
+	ts := httptest.NewServer(s.Handler())
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func readFile(t *testing.T, path string) []byte {
+	t.Helper()
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func post(t *testing.T, url string, contentType string, body []byte, wantStatus int, v interface{}) {
+	t.Helper()
+	resp, err := http.Post(url, contentType, bytes.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != wantStatus {
+		t.Fatalf("POST %v status = %v, want %v", url, resp.StatusCode, wantStatus)
+	}
+	if v != nil {
+		if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+			t.Fatalf("POST %v decode error = %v", url, err)
+		}
+	}
+}
+
+func licenseIDs(r ScanResult) []string {
+	var ids []string
+	for _, l := range r.Licenses {
+		if l.License.ID != "" {
+			ids = append(ids, l.License.ID)
+		} else {
+			ids = append(ids, l.License.Name)
+		}
+	}
+	return ids
+}
+
+func multipartFile(t *testing.T, name string, content []byte) (string, []byte) {
+	t.Helper()
+	var b bytes.Buffer
+	mw := multipart.NewWriter(&b)
+	fw, err := mw.CreateFormFile("file", name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = fw.Write(content)
+	_ = mw.Close()
+	return mw.FormDataContentType(), b.Bytes()
+}
+
+func TestServer_NotReady(t *testing.T) {
+	t.Parallel()
+	ts := httptest.NewServer(New(Options{}).Handler())
+	defer ts.Close()
+
+	for path, want := range map[string]int{"/healthz": http.StatusOK, "/readyz": http.StatusServiceUnavailable, "/v1/licenses": http.StatusServiceUnavailable} {
+		resp, err := http.Get(ts.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != want {
+			t.Errorf("GET %v status = %v, want %v", path, resp.StatusCode, want)
+		}
+	}
+}
+
+func TestServer(t *testing.T) {
+	t.Parallel()
+	ts := testServer(t, Options{MaxRequestBytes: 64 << 10, MaxExtractedBytes: 64 << 10, MaxConcurrentScans: 2})
+	mit := readFile(t, mitLicenseFile)
+
+	t.Run("ready", func(t *testing.T) {
+		resp, err := http.Get(ts.URL + "/readyz")
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("GET /readyz status = %v, want %v", resp.StatusCode, http.StatusOK)
+		}
+	})
+
+	t.Run("scan text", func(t *testing.T) {
+		var got ScanResponse
+		post(t, ts.URL+"/v1/scan?declaredLicense=GPL-3.0-only", "text/plain", mit, http.StatusOK, &got)
+		if len(got.Results) != 1 {
+			t.Fatalf("got %v results, want 1", len(got.Results))
+		}
+		if d := cmp.Diff([]string{"MIT"}, licenseIDs(got.Results[0])); d != "" {
+			t.Errorf("licenses (-want, +got): %v", d)
+		}
+		if got.Results[0].Hash == nil {
+			t.Errorf("expected the hash of the license text")
+		}
+		if got.Results[0].Declared == nil || got.Results[0].Declared.Outcome != manifest.OutcomeMorePermissive {
+			t.Errorf("declared = %+v, want outcome %v", got.Results[0].Declared, manifest.OutcomeMorePermissive)
+		}
+	})
+
+	t.Run("scan specs", func(t *testing.T) {
+		req, _ := json.Marshal(ScanRequest{Specs: []ScanSpec{
+			{Name: "mit", LicenseText: string(mit)},
+			{Name: "unknown", LicenseText: "this is not a license"},
+			{Name: "empty"},
+		}})
+		var got ScanResponse
+		post(t, ts.URL+"/v1/scan", "application/json", req, http.StatusOK, &got)
+		if len(got.Results) != 3 {
+			t.Fatalf("got %v results, want 3", len(got.Results))
+		}
+		if d := cmp.Diff([]string{"MIT"}, licenseIDs(got.Results[0])); d != "" {
+			t.Errorf("licenses (-want, +got): %v", d)
+		}
+		if d := cmp.Diff([]string{"NOASSERTION"}, licenseIDs(got.Results[1])); d != "" {
+			t.Errorf("licenses (-want, +got): %v", d)
+		}
+		if got.Results[2].Error == "" {
+			t.Errorf("expected an error for empty license text")
+		}
+	})
+
+	t.Run("invalid specs", func(t *testing.T) {
+		post(t, ts.URL+"/v1/scan", "application/json", []byte(`{"specs": []}`), http.StatusBadRequest, nil)
+		post(t, ts.URL+"/v1/scan", "application/json", []byte(`{`), http.StatusBadRequest, nil)
+	})
+
+	t.Run("too large", func(t *testing.T) {
+		post(t, ts.URL+"/v1/scan", "text/plain", bytes.Repeat([]byte("x"), 65<<10), http.StatusRequestEntityTooLarge, nil)
+	})
+
+	t.Run("cyclonedx", func(t *testing.T) {
+		resp, err := http.Post(ts.URL+"/v1/scan?format=cyclonedx", "text/plain", bytes.NewReader(mit))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		var bom struct {
+			BOMFormat  string `json:"bomFormat"`
+			Components []struct {
+				Licenses []struct {
+					License struct {
+						ID string `json:"id"`
+					} `json:"license"`
+				} `json:"licenses"`
+			} `json:"components"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&bom); err != nil {
+			t.Fatal(err)
+		}
+		if bom.BOMFormat != "CycloneDX" || len(bom.Components) != 1 || len(bom.Components[0].Licenses) != 1 || bom.Components[0].Licenses[0].License.ID != "MIT" {
+			t.Errorf("unexpected BOM %+v", bom)
+		}
+	})
+
+	t.Run("scan file", func(t *testing.T) {
+		contentType, body := multipartFile(t, "LICENSE", mit)
+		var got ScanResponse
+		post(t, ts.URL+"/v1/scan/file", contentType, body, http.StatusOK, &got)
+		if len(got.Results) != 1 || got.Results[0].Name != "LICENSE" {
+			t.Fatalf("got %+v, want 1 LICENSE result", got.Results)
+		}
+		if d := cmp.Diff([]string{"MIT"}, licenseIDs(got.Results[0])); d != "" {
+			t.Errorf("licenses (-want, +got): %v", d)
+		}
+	})
+
+	t.Run("scan archive", func(t *testing.T) {
+		var zb bytes.Buffer
+		zw := zip.NewWriter(&zb)
+		for name, content := range map[string][]byte{"pkg/LICENSE": mit, "pkg/README": []byte("readme"), "../outside/LICENSE": mit} {
+			fw, _ := zw.Create(name)
+			_, _ = fw.Write(content)
+		}
+		_ = zw.Close()
+
+		contentType, body := multipartFile(t, "pkg.zip", zb.Bytes())
+		var got ScanResponse
+		post(t, ts.URL+"/v1/scan/file", contentType, body, http.StatusOK, &got)
+		if len(got.Results) != 1 {
+			t.Fatalf("got %v results, want 1", len(got.Results))
+		}
+		if d := cmp.Diff([]string{"MIT"}, licenseIDs(got.Results[0])); d != "" {
+			t.Errorf("licenses (-want, +got): %v", d)
+		}
+		// The path outside of the archive directory is not extracted
+		if d := cmp.Diff([]string{"pkg/LICENSE"}, got.Results[0].Files); d != "" {
+			t.Errorf("files (-want, +got): %v", d)
+		}
+	})
+
+	t.Run("archive too large", func(t *testing.T) {
+		var zb bytes.Buffer
+		zw := zip.NewWriter(&zb)
+		fw, _ := zw.Create("big.txt")
+		_, _ = fw.Write(bytes.Repeat([]byte("x"), 65<<10)) // compresses within the request limit
+		_ = zw.Close()
+
+		contentType, body := multipartFile(t, "big.zip", zb.Bytes())
+		post(t, ts.URL+"/v1/scan/file", contentType, body, http.StatusRequestEntityTooLarge, nil)
+	})
+
+	t.Run("licenses", func(t *testing.T) {
+		resp, err := http.Get(ts.URL + "/v1/licenses")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		var got LicensesResponse
+		if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+			t.Fatal(err)
+		}
+		var found bool
+		for _, l := range got.Licenses {
+			found = found || l.ID == "MIT"
+		}
+		if !found {
+			t.Errorf("expected MIT in %v licenses", len(got.Licenses))
+		}
+	})
+
+	t.Run("validate", func(t *testing.T) {
+		tests := []struct {
+			expression string
+			want       ValidateResponse
+		}{
+			{expression: "mit/apache-2.0", want: ValidateResponse{Valid: true, Expression: "MIT OR Apache-2.0", IDs: []string{"MIT", "Apache-2.0"}}},
+			{expression: "MIT AND Not A License", want: ValidateResponse{IDs: []string{"MIT"}, Unresolved: []string{"Not A License"}}},
+			{expression: "", want: ValidateResponse{}},
+		}
+		for _, tt := range tests {
+			req, _ := json.Marshal(ValidateRequest{Expression: tt.expression})
+			var got ValidateResponse
+			post(t, ts.URL+"/v1/expressions/validate", "application/json", req, http.StatusOK, &got)
+			if d := cmp.Diff(tt.want, got); d != "" {
+				t.Errorf("validate %q (-want, +got): %v", tt.expression, d)
+			}
+		}
+	})
+
+	t.Run("metrics", func(t *testing.T) {
+		resp, err := http.Get(ts.URL + "/metrics")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		b, _ := io.ReadAll(resp.Body)
+		if resp.StatusCode != http.StatusOK || !strings.Contains(string(b), `license_scanner_scans_total{kind="text"}`) {
+			t.Errorf("GET /metrics status = %v, body %q", resp.StatusCode, b)
+		}
+	})
+
+	t.Run("method not allowed", func(t *testing.T) {
+		resp, err := http.Get(ts.URL + "/v1/scan")
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusMethodNotAllowed || !strings.Contains(resp.Header.Get("Allow"), http.MethodPost) {
+			t.Errorf("GET /v1/scan status = %v, want %v", resp.StatusCode, http.StatusMethodNotAllowed)
+		}
+	})
+}
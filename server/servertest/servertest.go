@@ -0,0 +1,41 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package servertest runs the gRPC API of a server in process, over an in-memory connection, for tests
+package servertest
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/CycloneDX/license-scanner/server"
+)
+
+// bufferSize is the size of the in-memory connection buffer
+const bufferSize = 1 << 20
+
+// NewGRPC serves the gRPC API of the server in process and returns a connection to it.
+// The connection and the gRPC server are closed when the test ends.
+func NewGRPC(t testing.TB, s *server.Server) *grpc.ClientConn {
+	t.Helper()
+	listener := bufconn.Listen(bufferSize)
+	g := s.GRPCServer()
+	go func() { _ = g.Serve(listener) }()
+
+	conn, err := grpc.DialContext(context.Background(), "bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("grpc.DialContext() error = %v", err)
+	}
+	t.Cleanup(func() {
+		_ = conn.Close()
+		g.Stop()
+	})
+	return conn
+}
@@ -0,0 +1,83 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package template
+
+import (
+	"fmt"
+	"regexp"
+	"regexp/syntax"
+	"strings"
+)
+
+const (
+	// space is the (normalized) whitespace, which is optional next to a tag
+	space      = " "
+	optionalRE = " *"
+)
+
+// token is a node of the template, or the end of an optional node
+type token struct {
+	node Node
+	end  bool
+}
+
+// Compile compiles the regex of a normalized template (see ParseNormalized). Each var is a capture group, and each
+// optional node is an optional group. The text is matched literally, except that a space next to a tag is optional.
+// A var which is not a supported regex is returned in the Diagnostics, at its offset in the normalized template.
+func Compile(t *Template) (*regexp.Regexp, error) {
+	tokens := flatten(nil, t.Nodes)
+	var diagnostics Diagnostics
+	var b strings.Builder
+	for i, tok := range tokens {
+		switch n := tok.node.(type) {
+		case *Text:
+			text := n.Text
+			if i > 0 {
+				text = trimTag(text, tokens[i-1], strings.TrimLeft, strings.TrimPrefix)
+			}
+			if i+1 < len(tokens) {
+				text = trimTag(text, tokens[i+1], strings.TrimRight, strings.TrimSuffix)
+			}
+			b.WriteString(regexp.QuoteMeta(text))
+		case *Var:
+			if _, err := syntax.Parse(n.Match, syntax.Perl); err != nil {
+				diagnostics = append(diagnostics, newDiagnostic(t.text, n.Offset, fmt.Sprintf("match %q is not supported: %v", n.Match, err)))
+			}
+			b.WriteString(optionalRE + "(?:(" + n.Match + ")" + optionalRE + ")")
+		case *Optional:
+			if tok.end {
+				b.WriteString(optionalRE + ")?")
+			} else {
+				b.WriteString(optionalRE + "(?:")
+			}
+		}
+	}
+	if len(diagnostics) > 0 {
+		return nil, diagnostics
+	}
+	return regexp.Compile(b.String())
+}
+
+// flatten appends the tokens of the nodes, in order: an optional node is followed by the tokens of its nodes and its end
+func flatten(tokens []token, nodes []Node) []token {
+	for _, node := range nodes {
+		tokens = append(tokens, token{node: node})
+		if o, ok := node.(*Optional); ok {
+			tokens = flatten(tokens, o.Nodes)
+			tokens = append(tokens, token{node: o, end: true})
+		}
+	}
+	return tokens
+}
+
+// trimTag removes the spaces of the text next to the tag of the token: all of the spaces next to a var, and one space
+// next to an optional tag (the regexes of the tags match the optional spaces)
+func trimTag(text string, tok token, trimAll func(string, string) string, trimOne func(string, string) string) string {
+	switch tok.node.(type) {
+	case *Var:
+		return trimAll(text, space)
+	case *Optional:
+		return trimOne(text, space)
+	}
+	return text
+}
@@ -0,0 +1,86 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package template
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCompile(t *testing.T) {
+	tests := []struct {
+		name       string
+		normalized string
+		want       string
+	}{
+		{
+			name:       "text",
+			normalized: "mit license (c) 2.0",
+			want:       `mit license \(c\) 2\.0`,
+		},
+		{
+			name:       "var",
+			normalized: "copyright <<.{0,1000}?>> all rights",
+			want:       `copyright *(?:(.{0,1000}?) *)all rights`,
+		},
+		{
+			name:       "optional",
+			normalized: "the <<omitable>>mit <</omitable>> license",
+			want:       `the *(?:mit *)?license`,
+		},
+		{
+			name:       "nested",
+			normalized: "<<omitable>>a <<omitable>>b<</omitable>> <<c|d>><</omitable>>.",
+			want:       ` *(?:a *(?:b *)? *(?:(c|d) *) *)?\.`,
+		},
+		{
+			name:       "copyright",
+			normalized: "x <<copyright>> y",
+			want:       `x *(?:(.*) *)y`,
+		},
+		{
+			name:       "< before a tag",
+			normalized: "the <<<omitable>>name<</omitable>>",
+			want:       `the < *(?:name *)?`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, err := ParseNormalized(tt.normalized)
+			if err != nil {
+				t.Fatalf("ParseNormalized() error = %v", err)
+			}
+			re, err := Compile(parsed)
+			if err != nil {
+				t.Fatalf("Compile() error = %v", err)
+			}
+			if got := re.String(); got != tt.want {
+				t.Errorf("Compile() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompile_diagnostics(t *testing.T) {
+	parsed, err := ParseNormalized("mit license\n<<a(>> and <<.{0,5000}>>")
+	if err != nil {
+		t.Fatalf("ParseNormalized() error = %v", err)
+	}
+	_, err = Compile(parsed)
+	want := Diagnostics{
+		{Offset: 12, Line: 2, Column: 1, Message: "match \"a(\" is not supported: error parsing regexp: missing closing ): `a(`"},
+		{Offset: 23, Line: 2, Column: 12, Message: "match \".{0,5000}\" is not supported: error parsing regexp: invalid repeat count: `{0,5000}`"},
+	}
+	var diagnostics Diagnostics
+	if !errors.As(err, &diagnostics) {
+		t.Fatalf("Compile() error = %v, want Diagnostics", err)
+	}
+	if diff := cmp.Diff(want, diagnostics); diff != "" {
+		t.Errorf("Compile() diagnostics (-want, +got): %v", diff)
+	}
+}
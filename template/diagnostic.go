@@ -0,0 +1,60 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package template
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Diagnostic is a problem with the markup of a template, at a line and column of the template text.
+// Lines and columns start at 1, and columns count runes.
+type Diagnostic struct {
+	Offset  int
+	Line    int
+	Column  int
+	Message string
+}
+
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("%v:%v: %v", d.Line, d.Column, d.Message)
+}
+
+// Diagnostics is the error of a template which cannot be parsed or compiled
+type Diagnostics []Diagnostic
+
+func (ds Diagnostics) Error() string {
+	s := make([]string, len(ds))
+	for i, d := range ds {
+		s[i] = d.String()
+	}
+	return strings.Join(s, "; ")
+}
+
+// Remap moves the diagnostics (e.g. of a normalized template) to the text (e.g. of the original template), using the
+// offset function to map each offset. An offset which cannot be mapped (-1) is moved to the beginning of the text.
+func (ds Diagnostics) Remap(text string, offset func(int) int) Diagnostics {
+	remapped := make(Diagnostics, len(ds))
+	for i, d := range ds {
+		remapped[i] = newDiagnostic(text, offset(d.Offset), d.Message)
+	}
+	return remapped
+}
+
+// newDiagnostic returns the diagnostic of the message at the offset of the text
+func newDiagnostic(text string, offset int, message string) Diagnostic {
+	if offset < 0 {
+		offset = 0
+	}
+	if offset > len(text) {
+		offset = len(text)
+	}
+	before := text[:offset]
+	return Diagnostic{
+		Offset:  offset,
+		Line:    strings.Count(before, "\n") + 1,
+		Column:  utf8.RuneCountInString(before[strings.LastIndexByte(before, '\n')+1:]) + 1,
+		Message: message,
+	}
+}
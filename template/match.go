@@ -0,0 +1,141 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package template
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// maxRepeat is the maximum count of a repetition, e.g. .{0,1000} (the limit of the regexp package)
+const maxRepeat = 1000
+
+// PrepareMatch prepares the match regex of a var for the regexp package:
+//   - a repetition count above the limit (e.g. the common .{0,5000}) is lowered to the limit
+//   - a greedy repetition at the end of the regex is made lazy, so that a var does not match more of the text than
+//     needed (e.g. .{0,1000}? instead of the next 1000 characters)
+//
+// Escaped characters and character classes (e.g. \* or [+]) are not repetitions.
+func PrepareMatch(match string) string {
+	var b strings.Builder
+	b.Grow(len(match) + 1)
+	greedy := false // the last token is a greedy repetition
+	for i := 0; i < len(match); {
+		token, isRepetition := nextToken(match, i)
+		switch {
+		case isRepetition && strings.HasPrefix(token, "{"):
+			token = limitRepeat(token)
+			greedy = true
+		case isRepetition:
+			greedy = token != "?" // a ? after a repetition makes it lazy
+		default:
+			greedy = false
+		}
+		b.WriteString(token)
+		i += len(token)
+	}
+	if greedy {
+		b.WriteString("?")
+	}
+	return b.String()
+}
+
+// nextToken returns the token of the regex at offset i: an escape, a character class, a repetition operator (after
+// an atom), or a single character
+func nextToken(re string, i int) (token string, isRepetition bool) {
+	switch re[i] {
+	case '\\':
+		end := i + 2
+		if end < len(re) && (re[i+1] == 'p' || re[i+1] == 'P' || re[i+1] == 'x') && re[end] == '{' {
+			if close := strings.IndexByte(re[end:], '}'); close >= 0 {
+				end += close + 1
+			}
+		}
+		if end > len(re) {
+			end = len(re)
+		}
+		return re[i:end], false
+	case '[':
+		end := i + 1
+		if end < len(re) && re[end] == '^' {
+			end++
+		}
+		if end < len(re) && re[end] == ']' {
+			end++ // a ] at the beginning of a class is a character
+		}
+		for end < len(re) && re[end] != ']' {
+			switch {
+			case re[end] == '\\':
+				end++
+			case strings.HasPrefix(re[end:], "[:"):
+				if close := strings.Index(re[end:], ":]"); close >= 0 {
+					end += close + 1
+				}
+			}
+			end++
+		}
+		if end < len(re) {
+			end++ // the ]
+		}
+		return re[i:end], false
+	case '*', '+', '?':
+		return re[i : i+1], i > 0 && !isOperatorAt(re, i-1)
+	case '{':
+		if close := strings.IndexByte(re[i:], '}'); close > 0 && isRepeat(re[i+1:i+close]) {
+			return re[i : i+close+1], true
+		}
+	}
+	return re[i : i+1], false
+}
+
+// isOperatorAt is true when the character at offset i is an operator, after which a repetition is not a repetition
+// of an atom (e.g. the ? of the lazy +?, or of (?:)
+func isOperatorAt(re string, i int) bool {
+	return (re[i] == '(' || re[i] == '|') && (i == 0 || re[i-1] != '\\')
+}
+
+// isRepeat is true for the counts of a repetition: n, n, (at least n), or n,m
+func isRepeat(counts string) bool {
+	least, most, hasMost := strings.Cut(counts, ",")
+	if _, err := strconv.Atoi(least); err != nil {
+		return false
+	}
+	if _, err := strconv.Atoi(most); hasMost && most != "" && err != nil {
+		return false
+	}
+	return true
+}
+
+// limitRepeat lowers the maximum count of a repetition {n,m} to the limit. A larger minimum count is not supported.
+func limitRepeat(repeat string) string {
+	least, most, hasMost := strings.Cut(repeat[1:len(repeat)-1], ",")
+	if m, _ := strconv.Atoi(most); hasMost && m > maxRepeat {
+		return "{" + least + "," + strconv.Itoa(maxRepeat) + "}"
+	}
+	return repeat
+}
+
+// upperCaseEscape returns the first escape of the regex with an upper case letter (e.g. \S or \P{Lu}), which the
+// normalization changes to lower case
+func upperCaseEscape(re string) string {
+	for i := 0; i < len(re); {
+		token, _ := nextToken(re, i)
+		if strings.HasPrefix(token, "[") && len(token) > 1 {
+			// An escape in a character class
+			for j := 1; j < len(token)-1; j++ {
+				if token[j] == '\\' {
+					if unicode.IsUpper(rune(token[j+1])) {
+						return token[j : j+2]
+					}
+					j++
+				}
+			}
+		}
+		if strings.HasPrefix(token, `\`) && len(token) > 1 && unicode.IsUpper(rune(token[1])) {
+			return token
+		}
+		i += len(token)
+	}
+	return ""
+}
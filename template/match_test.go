@@ -0,0 +1,42 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package template
+
+import "testing"
+
+func TestPrepareMatch(t *testing.T) {
+	tests := []struct {
+		match string
+		want  string
+	}{
+		{match: ".{0,5000}", want: ".{0,1000}?"},
+		{match: ".{0,100}", want: ".{0,100}?"},
+		{match: ".{1,}", want: ".{1,}?"},
+		{match: ".{0,5000} and .+", want: ".{0,1000} and .+?"},
+		{match: ".+", want: ".+?"},
+		{match: "(the )*", want: "(the )*?"},
+		{match: "[a-z]+", want: "[a-z]+?"},
+		{match: ".+?", want: ".+?"},
+		{match: ".{0,10}?", want: ".{0,10}?"},
+		{match: "limitation,?", want: "limitation,?"},
+		{match: "(a+)", want: "(a+)"},
+		{match: "a+|b", want: "a+|b"},
+		// Escaped characters and classes are not repetitions
+		{match: `\[\]|\{\}`, want: `\[\]|\{\}`},
+		{match: `\*`, want: `\*`},
+		{match: `[+*]`, want: `[+*]`},
+		{match: `[]+]`, want: `[]+]`},
+		{match: `[[:alpha:]+]`, want: `[[:alpha:]+]`},
+		{match: `\p{L}`, want: `\p{L}`},
+		{match: `\p{L}+`, want: `\p{L}+?`},
+		{match: "a{b}", want: "a{b}"},
+		{match: "(?:a)", want: "(?:a)"},
+	}
+	for _, tt := range tests {
+		if got := PrepareMatch(tt.match); got != tt.want {
+			t.Errorf("PrepareMatch(%q) = %q, want %q", tt.match, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,205 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package template
+
+import (
+	"fmt"
+	"regexp/syntax"
+	"sort"
+	"strings"
+)
+
+const (
+	beginTag = "<<"
+	endTag   = ">>"
+	// maxQuote is the length of the text of a tag quoted in a diagnostic
+	maxQuote = 40
+)
+
+// Parse parses the markup of an SPDX license template: the var tags (or the match tags of the custom templates), and
+// the (nested) optional tags. The note tags are left out of the tree. Any problem, e.g. an unknown tag, an
+// <<endOptional>> without a <<beginOptional>>, or a match regex which is not supported, is returned in the Diagnostics.
+func Parse(text string) (*Template, error) {
+	return parse(text, false)
+}
+
+// ParseNormalized parses a normalized template, in which the normalizer replaced the var tags with <<regex>>, and the
+// optional tags with <<omitable>> and <</omitable>>. Any other tag is a var. The match regexes are checked by Compile.
+func ParseNormalized(text string) (*Template, error) {
+	return parse(text, true)
+}
+
+type parser struct {
+	text       string
+	normalized bool
+	root       Template
+	// open is the stack of the optional nodes which are not closed yet
+	open        []*Optional
+	diagnostics Diagnostics
+}
+
+func parse(text string, normalized bool) (*Template, error) {
+	p := &parser{text: text, normalized: normalized, root: Template{text: text}}
+	pos := 0
+	for pos < len(text) {
+		begin := strings.Index(text[pos:], beginTag)
+		if begin < 0 {
+			break
+		}
+		begin += pos
+		for begin+len(beginTag) < len(text) && text[begin+len(beginTag)] == '<' {
+			begin++ // <<<tag>> is a < and a <<tag>>
+		}
+		end := strings.Index(text[begin+len(beginTag):], endTag)
+		if end < 0 {
+			break // a << which is not closed is text
+		}
+		end += begin + len(beginTag)
+		p.addText(pos, begin)
+		p.addTag(begin, text[begin+len(beginTag):end])
+		pos = end + len(endTag)
+	}
+	p.addText(pos, len(text))
+
+	for _, o := range p.open {
+		p.errorf(o.Offset, "<<beginOptional>> is not closed by an <<endOptional>>")
+	}
+	if len(p.diagnostics) > 0 {
+		sort.SliceStable(p.diagnostics, func(i, j int) bool { return p.diagnostics[i].Offset < p.diagnostics[j].Offset })
+		return nil, p.diagnostics
+	}
+	return &p.root, nil
+}
+
+// add appends the node to the innermost open optional node
+func (p *parser) add(node Node) {
+	if n := len(p.open); n > 0 {
+		p.open[n-1].Nodes = append(p.open[n-1].Nodes, node)
+	} else {
+		p.root.Nodes = append(p.root.Nodes, node)
+	}
+}
+
+// beginOptional adds the optional node, which contains the next nodes until it is closed
+func (p *parser) beginOptional(o *Optional) {
+	p.add(o)
+	p.open = append(p.open, o)
+}
+
+func (p *parser) addText(begin int, end int) {
+	if begin < end {
+		p.add(&Text{Offset: begin, Text: p.text[begin:end]})
+	}
+}
+
+// addTag adds the node of the tag at the offset
+func (p *parser) addTag(offset int, tag string) {
+	lower := strings.ToLower(tag)
+	switch {
+	case !p.normalized && strings.Contains(tag, "\n"):
+		p.errorf(offset, "the tag %v continues on the next line", quoteTag(tag))
+	case lower == "endoptional" || (p.normalized && lower == "/omitable"):
+		n := len(p.open)
+		if n == 0 {
+			p.errorf(offset, "<<endOptional>> without a <<beginOptional>>")
+			return
+		}
+		p.open = p.open[:n-1]
+	case p.normalized && lower == "omitable":
+		p.beginOptional(&Optional{Offset: offset})
+	case strings.HasPrefix(lower, "beginoptional"):
+		o := &Optional{Offset: offset}
+		switch attributes := tag[len("beginoptional"):]; {
+		case attributes == "":
+		case strings.HasPrefix(strings.ToLower(attributes), ";name="):
+			o.Name = unquote(attributes[len(";name="):])
+		default:
+			p.errorf(offset, "unknown attribute %q of <<beginOptional>> (only name is supported)", attributes)
+		}
+		p.beginOptional(o)
+	case strings.HasPrefix(lower, "note:") || strings.HasPrefix(lower, "note="):
+		// Notes are not matched
+	case strings.HasPrefix(lower, "var;") || strings.HasPrefix(lower, "match="):
+		if v := p.parseVar(offset, tag); v != nil {
+			p.add(v)
+		}
+	case p.normalized && lower == "copyright":
+		p.add(&Var{Offset: offset, Name: lower, Match: ".*"})
+	case p.normalized:
+		p.add(&Var{Offset: offset, Match: tag})
+	default:
+		p.errorf(offset, "unknown tag %v", quoteTag(tag))
+	}
+}
+
+// parseVar parses the attributes of a var tag: the optional name and original, and the match (in this order)
+func (p *parser) parseVar(offset int, tag string) *Var {
+	v := &Var{Offset: offset}
+	attributes := tag
+	if strings.HasPrefix(strings.ToLower(attributes), "var;") {
+		attributes = attributes[len("var;"):]
+	}
+	if strings.HasPrefix(strings.ToLower(attributes), "name=") {
+		end := strings.IndexByte(attributes, ';')
+		if end < 0 {
+			p.errorf(offset, "<<var>> without a match in %v", quoteTag(tag))
+			return nil
+		}
+		v.Name = unquote(attributes[len("name="):end])
+		attributes = attributes[end+1:]
+	}
+	if strings.HasPrefix(strings.ToLower(attributes), "original=") {
+		end := strings.Index(strings.ToLower(attributes), ";match=")
+		if end < 0 {
+			p.errorf(offset, "<<var>> without a match in %v", quoteTag(tag))
+			return nil
+		}
+		v.Original = unquote(attributes[len("original="):end])
+		attributes = attributes[end+1:]
+	}
+	if !strings.HasPrefix(strings.ToLower(attributes), "match=") {
+		p.errorf(offset, "unknown attribute in %v (a var has a name, original, and match)", quoteTag(tag))
+		return nil
+	}
+	v.Match = unquote(attributes[len("match="):])
+	if v.Match == "" {
+		p.errorf(offset, "<<var>> with an empty match in %v", quoteTag(tag))
+		return nil
+	}
+	if !p.normalized {
+		p.checkMatch(offset, v.Match)
+	}
+	return v
+}
+
+// checkMatch checks that the match regex is supported, after it is lower cased (like the rest of the template) by the
+// normalization, and prepared by PrepareMatch
+func (p *parser) checkMatch(offset int, match string) {
+	if escape := upperCaseEscape(match); escape != "" {
+		p.errorf(offset, "match=%q has the escape %v, which is changed by the normalization to lower case", match, escape)
+		return
+	}
+	if _, err := syntax.Parse(PrepareMatch(strings.ToLower(match)), syntax.Perl); err != nil {
+		p.errorf(offset, "match=%q is not supported: %v", match, err)
+	}
+}
+
+func (p *parser) errorf(offset int, format string, args ...interface{}) {
+	p.diagnostics = append(p.diagnostics, newDiagnostic(p.text, offset, fmt.Sprintf(format, args...)))
+}
+
+// unquote removes the double quotes around a value
+func unquote(value string) string {
+	if len(value) >= 2 && strings.HasPrefix(value, `"`) && strings.HasSuffix(value, `"`) {
+		return value[1 : len(value)-1]
+	}
+	return value
+}
+
+// quoteTag returns the tag for a diagnostic (shortened if long)
+func quoteTag(tag string) string {
+	if r := []rune(tag); len(r) > maxQuote {
+		tag = string(r[:maxQuote]) + "..."
+	}
+	return fmt.Sprintf("<<%v>>", strings.ReplaceAll(tag, "\n", `\n`))
+}
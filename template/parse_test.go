@@ -0,0 +1,166 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package template
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestParse(t *testing.T) {
+	text := "<<beginOptional;name=\"title\">>MIT License<<endOptional>>\n\n" +
+		`Copyright (c) <<var;name="copyright";original="<year> <owner>";match=".{0,5000}">>` + "\n\n" +
+		"<<note: the next paragraph>>Permission <<beginOptional>>is <<beginOptional>><<<endOptional>>hereby<<endOptional>> granted" +
+		"<<match=,?>> free of charge"
+	want := &Template{Nodes: []Node{
+		&Optional{Offset: 0, Name: "title", Nodes: []Node{&Text{Offset: 30, Text: "MIT License"}}},
+		&Text{Offset: 56, Text: "\n\nCopyright (c) "},
+		&Var{Offset: 72, Name: "copyright", Original: "<year> <owner>", Match: ".{0,5000}"},
+		&Text{Offset: 140, Text: "\n\n"},
+		&Text{Offset: 170, Text: "Permission "},
+		&Optional{Offset: 181, Nodes: []Node{
+			&Text{Offset: 198, Text: "is "},
+			&Optional{Offset: 201, Nodes: []Node{&Text{Offset: 218, Text: "<"}}},
+			&Text{Offset: 234, Text: "hereby"},
+		}},
+		&Text{Offset: 255, Text: " granted"},
+		&Var{Offset: 263, Match: ",?"},
+		&Text{Offset: 275, Text: " free of charge"},
+	}}
+
+	got, err := Parse(text)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if diff := cmp.Diff(want, got, cmpopts.IgnoreUnexported(Template{})); diff != "" {
+		t.Errorf("Parse() (-want, +got): %v", diff)
+	}
+	for _, node := range []Node{got.Nodes[2], got.Nodes[5], got.Nodes[7]} {
+		if text[node.Pos():node.Pos()+2] != beginTag {
+			t.Errorf("%T at %v is not at a tag", node, node.Pos())
+		}
+	}
+}
+
+func TestParseNormalized(t *testing.T) {
+	text := "<<omitable>>mit license <</omitable>> copyright <<.{0,1000}?>> <<copyright>>"
+	want := &Template{Nodes: []Node{
+		&Optional{Offset: 0, Nodes: []Node{&Text{Offset: 12, Text: "mit license "}}},
+		&Text{Offset: 37, Text: " copyright "},
+		&Var{Offset: 48, Match: ".{0,1000}?"},
+		&Text{Offset: 62, Text: " "},
+		&Var{Offset: 63, Name: "copyright", Match: ".*"},
+	}}
+
+	got, err := ParseNormalized(text)
+	if err != nil {
+		t.Fatalf("ParseNormalized() error = %v", err)
+	}
+	if diff := cmp.Diff(want, got, cmpopts.IgnoreUnexported(Template{})); diff != "" {
+		t.Errorf("ParseNormalized() (-want, +got): %v", diff)
+	}
+}
+
+func TestParse_diagnostics(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want Diagnostics
+	}{
+		{
+			name: "end without begin",
+			text: "MIT License\nPermission<<endOptional>>",
+			want: Diagnostics{{Offset: 22, Line: 2, Column: 11, Message: "<<endOptional>> without a <<beginOptional>>"}},
+		},
+		{
+			name: "begin without end",
+			text: "<<beginOptional>>MIT <<beginOptional>>License<<endOptional>>\n",
+			want: Diagnostics{{Offset: 0, Line: 1, Column: 1, Message: "<<beginOptional>> is not closed by an <<endOptional>>"}},
+		},
+		{
+			name: "unknown tag",
+			text: "Copyright © <<year>>",
+			want: Diagnostics{{Offset: 13, Line: 1, Column: 13, Message: "unknown tag <<year>>"}},
+		},
+		{
+			name: "unknown optional attribute",
+			text: "<<beginOptional;id=1>>x<<endOptional>>",
+			want: Diagnostics{{Offset: 0, Line: 1, Column: 1, Message: `unknown attribute ";id=1" of <<beginOptional>> (only name is supported)`}},
+		},
+		{
+			name: "var without match",
+			text: `x <<var;name="owner";original="the owner">>`,
+			want: Diagnostics{{Offset: 2, Line: 1, Column: 3, Message: `<<var>> without a match in <<var;name="owner";original="the owner">>`}},
+		},
+		{
+			name: "var with an unknown attribute",
+			text: `x <<var;name="owner";example="x";match=".+">>`,
+			want: Diagnostics{{Offset: 2, Line: 1, Column: 3, Message: `unknown attribute in <<var;name="owner";example="x";match=".+">> (a var has a name, original, and match)`}},
+		},
+		{
+			name: "tag on two lines",
+			text: "x\n<<var;name=\"owner\";original=\"the\nowner\";match=\".+\">>",
+			want: Diagnostics{{Offset: 2, Line: 2, Column: 1, Message: `the tag <<var;name="owner";original="the\nowner";ma...>> continues on the next line`}},
+		},
+		{
+			name: "lookahead",
+			text: "\n  <<match=\"(?!none).+\">>",
+			want: Diagnostics{{Offset: 3, Line: 2, Column: 3, Message: "match=\"(?!none).+\" is not supported: error parsing regexp: invalid or unsupported Perl syntax: `(?!`"}},
+		},
+		{
+			name: "upper case escape",
+			text: `<<match="\S+">>`,
+			want: Diagnostics{{Offset: 0, Line: 1, Column: 1, Message: `match="\\S+" has the escape \S, which is changed by the normalization to lower case`}},
+		},
+		{
+			name: "repetition count",
+			text: `© <<match=".{2000,}">>`,
+			want: Diagnostics{{Offset: 3, Line: 1, Column: 3, Message: "match=\".{2000,}\" is not supported: error parsing regexp: invalid repeat count: `{2000,}`"}},
+		},
+		{
+			name: "all diagnostics in order",
+			text: "<<beginOptional>>\n<<foo>>\n<<endOptional>><<endOptional>>",
+			want: Diagnostics{
+				{Offset: 18, Line: 2, Column: 1, Message: "unknown tag <<foo>>"},
+				{Offset: 41, Line: 3, Column: 16, Message: "<<endOptional>> without a <<beginOptional>>"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.text)
+			if got != nil {
+				t.Errorf("Parse() = %v, want nil", got)
+			}
+			var diagnostics Diagnostics
+			if !errors.As(err, &diagnostics) {
+				t.Fatalf("Parse() error = %v, want Diagnostics", err)
+			}
+			if diff := cmp.Diff(tt.want, diagnostics); diff != "" {
+				t.Errorf("Parse() diagnostics (-want, +got): %v", diff)
+			}
+		})
+	}
+}
+
+func TestDiagnostics_Remap(t *testing.T) {
+	original := "MIT\r\n\r\nCopyright <<var;match=\".+\">>"
+	normalized := "mit copyright <<.+?>>"
+	diagnostics := Diagnostics{newDiagnostic(normalized, 14, "a problem")}
+	offsets := func(i int) int {
+		if i == 14 {
+			return 17
+		}
+		return -1
+	}
+	want := "3:11: a problem"
+	if got := diagnostics.Remap(original, offsets).Error(); got != want {
+		t.Errorf("Remap() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,44 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package template parses the markup of SPDX license templates (see https://spdx.github.io/spdx-spec/v2.3/license-matching-guidelines-and-templates/)
+// into a tree of text, var, and optional nodes, and compiles the tree of a normalized template into a regex.
+package template
+
+// Node is a node of a template: a *Text, *Var, or *Optional
+type Node interface {
+	// Pos returns the byte offset of the node in the parsed text
+	Pos() int
+}
+
+// Text is text which must match (after normalization)
+type Text struct {
+	Offset int
+	Text   string
+}
+
+// Var is a replaceable text section, e.g. <<var;name="copyright";original="Copyright (c) <year>";match=".{0,5000}">>.
+// The quotes around the values are removed.
+type Var struct {
+	Offset   int
+	Name     string
+	Original string
+	Match    string
+}
+
+// Optional is an omitable text section, between <<beginOptional>> and <<endOptional>>, which can have nested nodes
+type Optional struct {
+	Offset int
+	Name   string
+	Nodes  []Node
+}
+
+// Template is the tree of the nodes of a template
+type Template struct {
+	Nodes []Node
+	// text is the parsed text (for the lines of the diagnostics)
+	text string
+}
+
+func (t *Text) Pos() int     { return t.Offset }
+func (v *Var) Pos() int      { return v.Offset }
+func (o *Optional) Pos() int { return o.Offset }
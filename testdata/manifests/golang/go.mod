@@ -0,0 +1,3 @@
+module github.com/example/example
+
+go 1.18